@@ -0,0 +1,8 @@
+data "migadu_address" "support" {
+  address = "support@example.com"
+}
+
+# all mailboxes and external addresses that receive emails sent to the address
+output "support_delivery_targets" {
+  value = data.migadu_address.support.delivery_targets
+}
@@ -8,3 +8,8 @@ data "migadu_alias" "idn" {
   domain_name = "bücher.example"
   local_part  = "some-name"
 }
+
+# look up an alias by its full email address
+data "migadu_alias" "address" {
+  address = "some-name@example.com"
+}
@@ -6,3 +6,15 @@ data "migadu_aliases" "aliases" {
 data "migadu_aliases" "idn" {
   domain_name = "bücher.example"
 }
+
+# only aliases that forward to a specific address and expire soon
+data "migadu_aliases" "filtered" {
+  domain_name    = "example.com"
+  destination    = "someone@example.com"
+  expires_before = "2030-01-01"
+}
+
+# look up a single alias by its local part
+output "support_destinations" {
+  value = data.migadu_aliases.aliases.aliases_by_local_part["support"].destinations
+}
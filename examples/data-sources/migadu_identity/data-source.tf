@@ -10,3 +10,8 @@ data "migadu_identity" "idn" {
   local_part  = "mailbox"
   identity    = "some-identity"
 }
+
+# look up an identity by its full email address, the owning mailbox is found automatically
+data "migadu_identity" "address" {
+  address = "some-identity@example.com"
+}
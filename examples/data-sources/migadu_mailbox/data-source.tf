@@ -8,3 +8,8 @@ data "migadu_mailbox" "idn" {
   domain_name = "bücher.example"
   local_part  = "some-name"
 }
+
+# look up a mailbox by its full email address
+data "migadu_mailbox" "address" {
+  address = "some-name@example.com"
+}
@@ -6,3 +6,16 @@ data "migadu_mailboxes" "mailboxes" {
 data "migadu_mailboxes" "idn" {
   domain_name = "bücher.example"
 }
+
+# fetch the identities of all mailboxes as well
+data "migadu_mailboxes" "with_identities" {
+  domain_name        = "example.com"
+  include_identities = true
+}
+
+# only mailboxes of the sales team that are allowed to send emails
+data "migadu_mailboxes" "filtered" {
+  domain_name      = "example.com"
+  local_part_regex = "^sales-"
+  may_send         = true
+}
@@ -6,3 +6,9 @@ data "migadu_rewrite_rules" "rewrites" {
 data "migadu_rewrite_rules" "idn" {
   domain_name = "bücher.example"
 }
+
+# only rewrite rules that forward to a specific address
+data "migadu_rewrite_rules" "filtered" {
+  domain_name = "example.com"
+  destination = "someone@example.com"
+}
@@ -0,0 +1,7 @@
+list "migadu_alias" "example" {
+  provider = migadu
+
+  config {
+    domain_name = "example.com"
+  }
+}
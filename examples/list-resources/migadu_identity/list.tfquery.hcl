@@ -0,0 +1,16 @@
+list "migadu_identity" "example" {
+  provider = migadu
+
+  config {
+    local_part  = "some-mailbox"
+    domain_name = "example.com"
+  }
+}
+
+list "migadu_identity" "all" {
+  provider = migadu
+
+  config {
+    domain_name = "example.com"
+  }
+}
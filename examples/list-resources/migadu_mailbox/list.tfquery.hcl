@@ -0,0 +1,7 @@
+list "migadu_mailbox" "example" {
+  provider = migadu
+
+  config {
+    domain_name = "example.com"
+  }
+}
@@ -0,0 +1,7 @@
+list "migadu_rewrite_rule" "example" {
+  provider = migadu
+
+  config {
+    domain_name = "example.com"
+  }
+}
@@ -9,3 +9,32 @@ provider "migadu" {
   timeout  = 35
   endpoint = "https://api.migadu.com/v1/"
 }
+
+# read the token from a mounted secret and the username from a profile of ~/.config/migadu/credentials
+provider "migadu" {
+  profile    = "customer-a"
+  token_file = "/run/secrets/migadu-token"
+}
+
+# organizational rules evaluated during every plan
+provider "migadu" {
+  policy {
+    required_value {
+      resource_types = ["migadu_mailbox"]
+      attribute      = "spam_action"
+      value          = "folder"
+    }
+    forbidden_destination_domains {
+      domains = ["gmail.com", "outlook.com"]
+    }
+    local_part_pattern {
+      pattern  = "^[a-z.]+$"
+      severity = "warning"
+    }
+    required_value {
+      resource_types = ["migadu_alias"]
+      attribute      = "is_internal"
+      value          = "true"
+    }
+  }
+}
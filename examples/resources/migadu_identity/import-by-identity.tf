@@ -0,0 +1,9 @@
+# migadu_identity resources can be imported by their identity in Terraform 1.12 and later.
+import {
+  to = migadu_identity.identity
+  identity = {
+    domain_name = "example.com"
+    identity    = "some-identity"
+    local_part  = "some-mailbox"
+  }
+}
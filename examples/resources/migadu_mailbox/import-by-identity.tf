@@ -0,0 +1,8 @@
+# migadu_mailbox resources can be imported by their identity in Terraform 1.12 and later.
+import {
+  to = migadu_mailbox.mailbox
+  identity = {
+    domain_name = "example.com"
+    local_part  = "some-name"
+  }
+}
@@ -21,3 +21,30 @@ resource "migadu_mailbox" "idn" {
   local_part  = "some-mailbox"
   password    = "Sup3r_s3cr3T"
 }
+
+# identities can be managed together with their mailbox
+resource "migadu_mailbox" "identities" {
+  name        = "Mailbox Name"
+  domain_name = "example.com"
+  local_part  = "firstname.lastname"
+  password    = "Sup3r_s3cr3T"
+  identities = {
+    "flastname" = {
+      name = "Firstname Lastname"
+    }
+    "phone" = {
+      name         = "Firstname Lastname"
+      password_use = "custom"
+      password     = "Ph0n3_s3cr3T"
+    }
+  }
+}
+
+# aliases that only forward to the mailbox can be managed together with it
+resource "migadu_mailbox" "aliases" {
+  name        = "Mailbox Name"
+  domain_name = "example.com"
+  local_part  = "firstname.lastname"
+  password    = "Sup3r_s3cr3T"
+  aliases     = ["flastname", "fl"]
+}
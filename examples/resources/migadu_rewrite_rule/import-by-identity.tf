@@ -0,0 +1,8 @@
+# migadu_rewrite_rule resources can be imported by their identity in Terraform 1.12 and later.
+import {
+  to = migadu_rewrite_rule.rewrite
+  identity = {
+    domain_name = "example.com"
+    name        = "some-rule"
+  }
+}
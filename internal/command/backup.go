@@ -0,0 +1,112 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"io"
+	"os"
+)
+
+// BackupVersion is the version of the backup document written by this binary.
+const BackupVersion = 1
+
+// Backup is a point-in-time snapshot of all objects of a single domain. Passwords cannot be read from the Migadu API
+// and are therefore never part of a backup.
+type Backup struct {
+	Version      int                 `json:"version"`
+	Domain       string              `json:"domain"`
+	Mailboxes    []model.Mailbox     `json:"mailboxes"`
+	Identities   []BackupIdentity    `json:"identities"`
+	Aliases      []model.Alias       `json:"aliases"`
+	RewriteRules []model.RewriteRule `json:"rewrite_rules"`
+}
+
+// BackupIdentity is an identity along with the local part of the mailbox it belongs to.
+type BackupIdentity struct {
+	Mailbox string `json:"mailbox"`
+	model.Identity
+}
+
+func runBackup(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("backup", stderr)
+	var clientFlags clientFlags
+	clientFlags.register(flags)
+	domain := flags.String("domain", "", "The domain to back up. International domain names can be given in their unicode form.")
+	output := flags.String("output", "", "The file to write the backup into. Defaults to stdout.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *domain == "" {
+		return fmt.Errorf("missing domain, use -domain")
+	}
+
+	migaduClient, err := clientFlags.client()
+	if err != nil {
+		return err
+	}
+
+	backup, err := NewBackup(ctx, migaduClient, *domain)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+
+	if *output == "" {
+		_, err = stdout.Write(content)
+		return err
+	}
+	if err = os.WriteFile(*output, content, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Wrote %s\n", *output)
+	return nil
+}
+
+// NewBackup reads all mailboxes, identities, aliases, and rewrite rules of the given domain. Values that change
+// without user interaction, like the storage usage of a mailbox, are left out to keep backups diffable.
+func NewBackup(ctx context.Context, migaduClient *client.MigaduClient, domain string) (*Backup, error) {
+	objects, err := fetchDomain(ctx, migaduClient, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &Backup{
+		Version:      BackupVersion,
+		Domain:       domain,
+		Mailboxes:    make([]model.Mailbox, 0, len(objects.mailboxes)),
+		Identities:   make([]BackupIdentity, 0, len(objects.identities)),
+		Aliases:      make([]model.Alias, 0, len(objects.aliases)),
+		RewriteRules: make([]model.RewriteRule, 0, len(objects.rewriteRules)),
+	}
+
+	for _, mailbox := range objects.mailboxes {
+		mailbox.Password = ""
+		mailbox.PasswordMethod = ""
+		mailbox.StorageUsage = 0
+		mailbox.Identities = nil
+		backup.Mailboxes = append(backup.Mailboxes, mailbox)
+	}
+	for _, entry := range objects.identities {
+		identity := entry.identity
+		identity.Password = ""
+		backup.Identities = append(backup.Identities, BackupIdentity{Mailbox: entry.mailbox, Identity: identity})
+	}
+	backup.Aliases = append(backup.Aliases, objects.aliases...)
+	backup.RewriteRules = append(backup.RewriteRules, objects.rewriteRules...)
+
+	return backup, nil
+}
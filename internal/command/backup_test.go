@@ -0,0 +1,187 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/command"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBackup_API_Success(t *testing.T) {
+	testCases := map[string]struct {
+		domain string
+		state  simulator.State
+		want   command.Backup
+	}{
+		"empty": {
+			domain: "example.com",
+			state:  simulator.State{},
+			want: command.Backup{
+				Version:      command.BackupVersion,
+				Domain:       "example.com",
+				Mailboxes:    []model.Mailbox{},
+				Identities:   []command.BackupIdentity{},
+				Aliases:      []model.Alias{},
+				RewriteRules: []model.RewriteRule{},
+			},
+		},
+		"all": {
+			domain: "example.com",
+			state: simulator.State{
+				Mailboxes: []model.Mailbox{
+					{
+						LocalPart:    "some",
+						DomainName:   "example.com",
+						Address:      "some@example.com",
+						Name:         "Some Name",
+						Password:     "secret",
+						StorageUsage: 12.5,
+					},
+				},
+				Identities: []model.Identity{
+					{
+						LocalPart:   "other",
+						DomainName:  "example.com",
+						Address:     "other@example.com",
+						Name:        "Other Name",
+						PasswordUse: "custom",
+						Password:    "secret",
+					},
+				},
+				Aliases: []model.Alias{
+					{
+						LocalPart:    "alias",
+						DomainName:   "example.com",
+						Address:      "alias@example.com",
+						Destinations: []string{"some@example.com"},
+					},
+				},
+				Rewrites: []model.RewriteRule{
+					{
+						DomainName:    "example.com",
+						Name:          "sample",
+						LocalPartRule: "prefix-*",
+						OrderNum:      1,
+						Destinations:  []string{"some@example.com"},
+					},
+				},
+			},
+			want: command.Backup{
+				Version: command.BackupVersion,
+				Domain:  "example.com",
+				Mailboxes: []model.Mailbox{
+					{
+						LocalPart:  "some",
+						DomainName: "example.com",
+						Address:    "some@example.com",
+						Name:       "Some Name",
+					},
+				},
+				Identities: []command.BackupIdentity{
+					{
+						Mailbox: "some",
+						Identity: model.Identity{
+							LocalPart:   "other",
+							DomainName:  "example.com",
+							Address:     "other@example.com",
+							Name:        "Other Name",
+							PasswordUse: "custom",
+						},
+					},
+				},
+				Aliases: []model.Alias{
+					{
+						LocalPart:    "alias",
+						DomainName:   "example.com",
+						Address:      "alias@example.com",
+						Destinations: []string{"some@example.com"},
+					},
+				},
+				RewriteRules: []model.RewriteRule{
+					{
+						DomainName:    "example.com",
+						Name:          "sample",
+						LocalPartRule: "prefix-*",
+						OrderNum:      1,
+						Destinations:  []string{"some@example.com"},
+					},
+				},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &testCase.state))
+			defer server.Close()
+
+			backup, err := command.NewBackup(context.Background(), newClient(t, server.URL), testCase.domain)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			assert.Equal(t, testCase.want, *backup, "backup")
+		})
+	}
+}
+
+func TestBackup_API_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		statusCode int
+	}{
+		"error-404": {statusCode: http.StatusNotFound},
+		"error-500": {statusCode: http.StatusInternalServerError},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{StatusCode: testCase.statusCode}))
+			defer server.Close()
+
+			_, err := command.NewBackup(context.Background(), newClient(t, server.URL), "example.com")
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRun_Backup(t *testing.T) {
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{
+		Aliases: []model.Alias{
+			{
+				LocalPart:    "some",
+				DomainName:   "example.com",
+				Address:      "some@example.com",
+				Destinations: []string{"other@example.com"},
+			},
+		},
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := command.Run(context.Background(), []string{
+		"backup",
+		"-endpoint", server.URL,
+		"-username", "username",
+		"-token", "token",
+		"-domain", "example.com",
+	}, &stdout, &stderr)
+
+	assert.Equal(t, 0, code, "exit code (stderr: %s)", stderr.String())
+	var backup command.Backup
+	if err := json.Unmarshal(stdout.Bytes(), &backup); err != nil {
+		t.Fatalf("Could not parse backup: %s", err)
+	}
+	assert.Equal(t, command.BackupVersion, backup.Version, "version")
+	assert.Equal(t, "example.com", backup.Domain, "domain")
+	assert.Len(t, backup.Aliases, 1, "aliases")
+}
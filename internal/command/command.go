@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/metio/migadu-client.go/client"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Run executes the subcommand named by the first element of args and returns the exit code of the process.
+func Run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "export":
+		err = runExport(ctx, args[1:], stdout, stderr)
+	case "backup":
+		err = runBackup(ctx, args[1:], stdout, stderr)
+	case "restore":
+		err = runRestore(ctx, args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s\n", args[0], usage)
+		return 2
+	}
+
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+		}
+		return 1
+	}
+	return 0
+}
+
+const usage = `Usage: terraform-provider-migadu <command> [options]
+
+Without a command, the binary serves the Terraform provider.
+
+Commands:
+  export    Write Terraform configuration and import blocks for all objects of a domain
+  backup    Write all objects of a domain as a JSON document
+  restore   Recreate objects of a domain that are missing compared to a backup`
+
+// clientFlags contains the flags shared by all commands that talk to the Migadu API. Their default values are read
+// from the same environment variables that are used by the provider.
+type clientFlags struct {
+	endpoint string
+	username string
+	token    string
+	timeout  int64
+}
+
+func (c *clientFlags) register(flags *flag.FlagSet) {
+	endpoint := os.Getenv("MIGADU_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://api.migadu.com/v1/"
+	}
+	timeout, err := strconv.ParseInt(os.Getenv("MIGADU_TIMEOUT"), 10, 64)
+	if err != nil {
+		timeout = 10
+	}
+
+	flags.StringVar(&c.endpoint, "endpoint", endpoint, "The API endpoint to use. Defaults to MIGADU_ENDPOINT.")
+	flags.StringVar(&c.username, "username", os.Getenv("MIGADU_USERNAME"), "The username to use. Defaults to MIGADU_USERNAME.")
+	flags.StringVar(&c.token, "token", os.Getenv("MIGADU_TOKEN"), "The API token to use. Defaults to MIGADU_TOKEN.")
+	flags.Int64Var(&c.timeout, "timeout", timeout, "The timeout in seconds to apply on API requests. Defaults to MIGADU_TIMEOUT.")
+}
+
+func (c *clientFlags) client() (*client.MigaduClient, error) {
+	if c.username == "" {
+		return nil, fmt.Errorf("missing username, use -username or MIGADU_USERNAME")
+	}
+	if c.token == "" {
+		return nil, fmt.Errorf("missing token, use -token or MIGADU_TOKEN")
+	}
+	return client.New(&c.endpoint, &c.username, &c.token, time.Duration(c.timeout)*time.Second)
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
@@ -0,0 +1,75 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command
+
+import (
+	"context"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"slices"
+	"strings"
+)
+
+// domainObjects contains all objects of a single domain in a stable order.
+type domainObjects struct {
+	mailboxes    []model.Mailbox
+	identities   []mailboxIdentity
+	aliases      []model.Alias
+	rewriteRules []model.RewriteRule
+}
+
+type mailboxIdentity struct {
+	mailbox  string
+	identity model.Identity
+}
+
+// fetchDomain reads all mailboxes, identities, aliases, and rewrite rules of the given domain. Each list is sorted by
+// its natural key so that repeated calls against an unchanged domain return identical results.
+func fetchDomain(ctx context.Context, migaduClient *client.MigaduClient, domain string) (*domainObjects, error) {
+	objects := &domainObjects{}
+
+	mailboxes, err := migaduClient.GetMailboxes(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	objects.mailboxes = mailboxes.Mailboxes
+	slices.SortFunc(objects.mailboxes, func(a, b model.Mailbox) int {
+		return strings.Compare(a.LocalPart, b.LocalPart)
+	})
+
+	for _, mailbox := range objects.mailboxes {
+		identities, err := migaduClient.GetIdentities(ctx, domain, mailbox.LocalPart)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortFunc(identities.Identities, func(a, b model.Identity) int {
+			return strings.Compare(a.LocalPart, b.LocalPart)
+		})
+		for _, identity := range identities.Identities {
+			objects.identities = append(objects.identities, mailboxIdentity{mailbox: mailbox.LocalPart, identity: identity})
+		}
+	}
+
+	aliases, err := migaduClient.GetAliases(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	objects.aliases = aliases.Aliases
+	slices.SortFunc(objects.aliases, func(a, b model.Alias) int {
+		return strings.Compare(a.LocalPart, b.LocalPart)
+	})
+
+	rewriteRules, err := migaduClient.GetRewriteRules(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	objects.rewriteRules = rewriteRules.RewriteRules
+	slices.SortFunc(objects.rewriteRules, func(a, b model.RewriteRule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return objects, nil
+}
@@ -0,0 +1,346 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/net/idna"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+func runExport(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("export", stderr)
+	var clientFlags clientFlags
+	clientFlags.register(flags)
+	domain := flags.String("domain", "", "The domain to export. International domain names can be given in their unicode form.")
+	output := flags.String("output", ".", "The directory to write the generated configuration into.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *domain == "" {
+		return fmt.Errorf("missing domain, use -domain")
+	}
+
+	migaduClient, err := clientFlags.client()
+	if err != nil {
+		return err
+	}
+
+	files, err := Export(ctx, migaduClient, *domain)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(*output, 0o755); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(*output, name)
+		if err = os.WriteFile(path, files[name], 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Wrote %s\n", path)
+	}
+	return nil
+}
+
+// Export fetches all mailboxes, identities, aliases, and rewrite rules of the given domain and returns Terraform
+// configuration for them along with matching import blocks. The result maps file names to their content. Passwords
+// cannot be read from the Migadu API, therefore they are declared as variables in a separate file. Mailboxes that use
+// invitations or have a password recovery email do not need a password, thus no variable is declared for them.
+func Export(ctx context.Context, migaduClient *client.MigaduClient, domain string) (map[string][]byte, error) {
+	e := &exporter{
+		domain:        domain,
+		variables:     hclwrite.NewEmptyFile(),
+		variableNames: newResourceNames(),
+	}
+
+	asciiDomain, err := idna.ToASCII(domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid domain %q: %w", domain, err)
+	}
+	e.asciiDomain = asciiDomain
+
+	objects, err := fetchDomain(ctx, migaduClient, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	addFile := func(name string, file *hclwrite.File) {
+		if len(file.Body().Blocks()) > 0 {
+			files[name] = hclwrite.Format(file.Bytes())
+		}
+	}
+
+	addFile("mailboxes.tf", e.mailboxes(objects.mailboxes))
+	addFile("identities.tf", e.identities(objects.identities))
+	addFile("aliases.tf", e.aliases(objects.aliases))
+	addFile("rewrite_rules.tf", e.rewriteRules(objects.rewriteRules))
+	addFile("variables.tf", e.variables)
+
+	return files, nil
+}
+
+type exporter struct {
+	domain        string
+	asciiDomain   string
+	variables     *hclwrite.File
+	variableNames resourceNames
+}
+
+func (e *exporter) mailboxes(mailboxes []model.Mailbox) *hclwrite.File {
+	file := hclwrite.NewEmptyFile()
+	names := newResourceNames()
+
+	for _, mailbox := range mailboxes {
+		name := names.next(mailbox.LocalPart)
+		body := appendResource(file, "migadu_mailbox", name)
+
+		body.SetAttributeValue("local_part", cty.StringVal(mailbox.LocalPart))
+		body.SetAttributeValue("domain_name", cty.StringVal(e.domain))
+		body.SetAttributeValue("name", cty.StringVal(mailbox.Name))
+		if mailbox.PasswordMethod != "invitation" && mailbox.PasswordRecoveryEmail == "" {
+			body.SetAttributeTraversal("password", e.passwordVariable("mailbox_"+name, "mailbox "+mailbox.LocalPart+"@"+e.domain))
+		}
+		setOptionalString(body, "password_recovery_email", e.address(mailbox.PasswordRecoveryEmail))
+		body.SetAttributeValue("is_internal", cty.BoolVal(mailbox.IsInternal))
+		body.SetAttributeValue("may_send", cty.BoolVal(mailbox.MaySend))
+		body.SetAttributeValue("may_receive", cty.BoolVal(mailbox.MayReceive))
+		body.SetAttributeValue("may_access_imap", cty.BoolVal(mailbox.MayAccessImap))
+		body.SetAttributeValue("may_access_pop3", cty.BoolVal(mailbox.MayAccessPop3))
+		body.SetAttributeValue("may_access_manage_sieve", cty.BoolVal(mailbox.MayAccessManageSieve))
+		setOptionalString(body, "spam_action", mailbox.SpamAction)
+		setOptionalString(body, "spam_aggressiveness", mailbox.SpamAggressiveness)
+		body.SetAttributeValue("expirable", cty.BoolVal(mailbox.Expirable))
+		setOptionalString(body, "expires_on", mailbox.ExpiresOn)
+		body.SetAttributeValue("remove_upon_expiry", cty.BoolVal(mailbox.RemoveUponExpiry))
+		setOptionalList(body, "sender_denylist", e.addresses(mailbox.SenderDenyList))
+		setOptionalList(body, "sender_allowlist", e.addresses(mailbox.SenderAllowList))
+		setOptionalList(body, "recipient_denylist", e.addresses(mailbox.RecipientDenyList))
+		setOptionalList(body, "delegations", e.addresses(mailbox.Delegations))
+		body.SetAttributeValue("auto_respond_active", cty.BoolVal(mailbox.AutoRespondActive))
+		setOptionalString(body, "auto_respond_subject", mailbox.AutoRespondSubject)
+		setOptionalString(body, "auto_respond_body", mailbox.AutoRespondBody)
+		setOptionalString(body, "auto_respond_expires_on", mailbox.AutoRespondExpiresOn)
+		body.SetAttributeValue("footer_active", cty.BoolVal(mailbox.FooterActive))
+		setOptionalString(body, "footer_plain_body", mailbox.FooterPlainBody)
+		setOptionalString(body, "footer_html_body", mailbox.FooterHtmlBody)
+
+		appendImport(file, "migadu_mailbox", name, fmt.Sprintf("%s@%s", mailbox.LocalPart, e.domain))
+	}
+
+	return file
+}
+
+func (e *exporter) identities(identities []mailboxIdentity) *hclwrite.File {
+	file := hclwrite.NewEmptyFile()
+	names := newResourceNames()
+
+	for _, entry := range identities {
+		identity := entry.identity
+		name := names.next(entry.mailbox + "_" + identity.LocalPart)
+		body := appendResource(file, "migadu_identity", name)
+
+		body.SetAttributeValue("local_part", cty.StringVal(entry.mailbox))
+		body.SetAttributeValue("domain_name", cty.StringVal(e.domain))
+		body.SetAttributeValue("identity", cty.StringVal(identity.LocalPart))
+		body.SetAttributeValue("name", cty.StringVal(identity.Name))
+		body.SetAttributeValue("may_send", cty.BoolVal(identity.MaySend))
+		body.SetAttributeValue("may_receive", cty.BoolVal(identity.MayReceive))
+		body.SetAttributeValue("may_access_imap", cty.BoolVal(identity.MayAccessImap))
+		body.SetAttributeValue("may_access_pop3", cty.BoolVal(identity.MayAccessPop3))
+		body.SetAttributeValue("may_access_manage_sieve", cty.BoolVal(identity.MayAccessManageSieve))
+		setOptionalString(body, "password_use", identity.PasswordUse)
+		if identity.PasswordUse == "custom" {
+			body.SetAttributeTraversal("password", e.passwordVariable("identity_"+name, "identity "+identity.LocalPart+"@"+e.domain))
+		}
+		body.SetAttributeValue("footer_active", cty.BoolVal(identity.FooterActive))
+		setOptionalString(body, "footer_plain_body", identity.FooterPlainBody)
+		setOptionalString(body, "footer_html_body", identity.FooterHtmlBody)
+
+		appendImport(file, "migadu_identity", name, fmt.Sprintf("%s@%s/%s", entry.mailbox, e.domain, identity.LocalPart))
+	}
+
+	return file
+}
+
+func (e *exporter) aliases(aliases []model.Alias) *hclwrite.File {
+	file := hclwrite.NewEmptyFile()
+	names := newResourceNames()
+
+	for _, alias := range aliases {
+		name := names.next(alias.LocalPart)
+		body := appendResource(file, "migadu_alias", name)
+
+		body.SetAttributeValue("local_part", cty.StringVal(alias.LocalPart))
+		body.SetAttributeValue("domain_name", cty.StringVal(e.domain))
+		body.SetAttributeValue("destinations", stringList(e.addresses(alias.Destinations)))
+		body.SetAttributeValue("is_internal", cty.BoolVal(alias.IsInternal))
+		body.SetAttributeValue("expirable", cty.BoolVal(alias.Expirable))
+		setOptionalString(body, "expires_on", alias.ExpiresOn)
+		body.SetAttributeValue("remove_upon_expiry", cty.BoolVal(alias.RemoveUponExpiry))
+
+		appendImport(file, "migadu_alias", name, fmt.Sprintf("%s@%s", alias.LocalPart, e.domain))
+	}
+
+	return file
+}
+
+func (e *exporter) rewriteRules(rewriteRules []model.RewriteRule) *hclwrite.File {
+	file := hclwrite.NewEmptyFile()
+	names := newResourceNames()
+
+	for _, rewriteRule := range rewriteRules {
+		name := names.next(rewriteRule.Name)
+		body := appendResource(file, "migadu_rewrite_rule", name)
+
+		body.SetAttributeValue("domain_name", cty.StringVal(e.domain))
+		body.SetAttributeValue("name", cty.StringVal(rewriteRule.Name))
+		body.SetAttributeValue("local_part_rule", cty.StringVal(rewriteRule.LocalPartRule))
+		body.SetAttributeValue("order_num", cty.NumberIntVal(rewriteRule.OrderNum))
+		body.SetAttributeValue("destinations", stringList(e.addresses(rewriteRule.Destinations)))
+
+		appendImport(file, "migadu_rewrite_rule", name, fmt.Sprintf("%s/%s", e.domain, rewriteRule.Name))
+	}
+
+	return file
+}
+
+// passwordVariable declares a sensitive variable and returns a reference to it. Variables of all resource types share
+// a single namespace, therefore their names are made unique across all of them.
+func (e *exporter) passwordVariable(prefix string, owner string) hcl.Traversal {
+	name := e.variableNames.next(prefix + "_password")
+
+	body := e.variables.Body()
+	if len(body.Blocks()) > 0 {
+		body.AppendNewline()
+	}
+	variable := body.AppendNewBlock("variable", []string{name}).Body()
+	variable.SetAttributeValue("description", cty.StringVal("The password of the "+owner+"."))
+	variable.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+	variable.SetAttributeValue("sensitive", cty.True)
+
+	return hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: name}}
+}
+
+// addresses converts all given email addresses with address.
+func (e *exporter) addresses(addresses []string) []string {
+	converted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		converted = append(converted, e.address(address))
+	}
+	sort.Strings(converted)
+	return converted
+}
+
+// address converts the punycode domain of an email address as returned by the Migadu API back into unicode. Addresses
+// of the exported domain use the domain exactly as given by the user.
+func (e *exporter) address(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return address
+	}
+
+	localPart := address[:at]
+	domain := address[at+1:]
+	if strings.EqualFold(domain, e.asciiDomain) {
+		return localPart + "@" + e.domain
+	}
+	if unicodeDomain, err := idna.ToUnicode(domain); err == nil {
+		return localPart + "@" + unicodeDomain
+	}
+	return address
+}
+
+func appendResource(file *hclwrite.File, resourceType string, name string) *hclwrite.Body {
+	body := file.Body()
+	if len(body.Blocks()) > 0 {
+		body.AppendNewline()
+	}
+	return body.AppendNewBlock("resource", []string{resourceType, name}).Body()
+}
+
+func appendImport(file *hclwrite.File, resourceType string, name string, id string) {
+	body := file.Body()
+	body.AppendNewline()
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{hcl.TraverseRoot{Name: resourceType}, hcl.TraverseAttr{Name: name}})
+	block.SetAttributeValue("id", cty.StringVal(id))
+}
+
+func setOptionalString(body *hclwrite.Body, name string, value string) {
+	if value != "" {
+		body.SetAttributeValue(name, cty.StringVal(value))
+	}
+}
+
+func setOptionalList(body *hclwrite.Body, name string, values []string) {
+	if len(values) > 0 {
+		body.SetAttributeValue(name, stringList(values))
+	}
+}
+
+func stringList(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	list := make([]cty.Value, 0, len(values))
+	for _, value := range values {
+		list = append(list, cty.StringVal(value))
+	}
+	return cty.ListVal(list)
+}
+
+// resourceNames generates unique Terraform resource names.
+type resourceNames map[string]bool
+
+func newResourceNames() resourceNames {
+	return make(resourceNames)
+}
+
+func (r resourceNames) next(value string) string {
+	var name strings.Builder
+	for _, char := range strings.ToLower(value) {
+		if char < unicode.MaxASCII && (unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_' || char == '-') {
+			name.WriteRune(char)
+		} else {
+			name.WriteRune('_')
+		}
+	}
+
+	base := name.String()
+	if base == "" || !unicode.IsLetter(rune(base[0])) && base[0] != '_' {
+		base = "_" + base
+	}
+
+	// names that already received a numeric suffix can be taken by later values as well, e.g. 'a.b', 'a_b', and 'a_b_2'
+	unique := base
+	for count := 2; r[unique]; count++ {
+		unique = fmt.Sprintf("%s_%d", base, count)
+	}
+	r[unique] = true
+	return unique
+}
@@ -0,0 +1,414 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/command"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestExport_API_Success(t *testing.T) {
+	testCases := map[string]struct {
+		domain string
+		state  simulator.State
+		want   map[string]string
+	}{
+		"empty": {
+			domain: "example.com",
+			state:  simulator.State{},
+			want:   map[string]string{},
+		},
+		"all": {
+			domain: "example.com",
+			state: simulator.State{
+				Mailboxes: []model.Mailbox{
+					{
+						LocalPart:      "some",
+						DomainName:     "example.com",
+						Address:        "some@example.com",
+						Name:           "Some Name",
+						MaySend:        true,
+						MayReceive:     true,
+						SenderDenyList: []string{"spam@example.org"},
+					},
+				},
+				Identities: []model.Identity{
+					{
+						LocalPart:   "other",
+						DomainName:  "example.com",
+						Address:     "other@example.com",
+						Name:        "Other Name",
+						MaySend:     true,
+						PasswordUse: "custom",
+					},
+				},
+				Aliases: []model.Alias{
+					{
+						LocalPart:    "alias",
+						DomainName:   "example.com",
+						Address:      "alias@example.com",
+						Destinations: []string{"some@example.com"},
+					},
+				},
+				Rewrites: []model.RewriteRule{
+					{
+						DomainName:    "example.com",
+						Name:          "sample",
+						LocalPartRule: "prefix-*",
+						OrderNum:      1,
+						Destinations:  []string{"some@example.com"},
+					},
+				},
+			},
+			want: map[string]string{
+				"mailboxes.tf": `resource "migadu_mailbox" "some" {
+  local_part              = "some"
+  domain_name             = "example.com"
+  name                    = "Some Name"
+  password                = var.mailbox_some_password
+  is_internal             = false
+  may_send                = true
+  may_receive             = true
+  may_access_imap         = false
+  may_access_pop3         = false
+  may_access_manage_sieve = false
+  expirable               = false
+  remove_upon_expiry      = false
+  sender_denylist         = ["spam@example.org"]
+  auto_respond_active     = false
+  footer_active           = false
+}
+
+import {
+  to = migadu_mailbox.some
+  id = "some@example.com"
+}
+`,
+				"identities.tf": `resource "migadu_identity" "some_other" {
+  local_part              = "some"
+  domain_name             = "example.com"
+  identity                = "other"
+  name                    = "Other Name"
+  may_send                = true
+  may_receive             = false
+  may_access_imap         = false
+  may_access_pop3         = false
+  may_access_manage_sieve = false
+  password_use            = "custom"
+  password                = var.identity_some_other_password
+  footer_active           = false
+}
+
+import {
+  to = migadu_identity.some_other
+  id = "some@example.com/other"
+}
+`,
+				"aliases.tf": `resource "migadu_alias" "alias" {
+  local_part         = "alias"
+  domain_name        = "example.com"
+  destinations       = ["some@example.com"]
+  is_internal        = false
+  expirable          = false
+  remove_upon_expiry = false
+}
+
+import {
+  to = migadu_alias.alias
+  id = "alias@example.com"
+}
+`,
+				"rewrite_rules.tf": `resource "migadu_rewrite_rule" "sample" {
+  domain_name     = "example.com"
+  name            = "sample"
+  local_part_rule = "prefix-*"
+  order_num       = 1
+  destinations    = ["some@example.com"]
+}
+
+import {
+  to = migadu_rewrite_rule.sample
+  id = "example.com/sample"
+}
+`,
+				"variables.tf": `variable "mailbox_some_password" {
+  description = "The password of the mailbox some@example.com."
+  type        = string
+  sensitive   = true
+}
+
+variable "identity_some_other_password" {
+  description = "The password of the identity other@example.com."
+  type        = string
+  sensitive   = true
+}
+`,
+			},
+		},
+		"invitation": {
+			domain: "example.com",
+			state: simulator.State{
+				Mailboxes: []model.Mailbox{
+					{
+						LocalPart:             "invited",
+						DomainName:            "example.com",
+						Address:               "invited@example.com",
+						Name:                  "Invited Name",
+						PasswordRecoveryEmail: "someone@example.org",
+					},
+				},
+			},
+			want: map[string]string{
+				"mailboxes.tf": `resource "migadu_mailbox" "invited" {
+  local_part              = "invited"
+  domain_name             = "example.com"
+  name                    = "Invited Name"
+  password_recovery_email = "someone@example.org"
+  is_internal             = false
+  may_send                = false
+  may_receive             = false
+  may_access_imap         = false
+  may_access_pop3         = false
+  may_access_manage_sieve = false
+  expirable               = false
+  remove_upon_expiry      = false
+  auto_respond_active     = false
+  footer_active           = false
+}
+
+import {
+  to = migadu_mailbox.invited
+  id = "invited@example.com"
+}
+`,
+			},
+		},
+		"idna": {
+			domain: "hoß.de",
+			state: simulator.State{
+				Aliases: []model.Alias{
+					{
+						LocalPart:    "some.one",
+						DomainName:   "xn--ho-hia.de",
+						Address:      "some.one@xn--ho-hia.de",
+						Destinations: []string{"other@xn--ho-hia.de", "another@xn--bcher-kva.example"},
+					},
+					{
+						LocalPart:    "some-one",
+						DomainName:   "xn--ho-hia.de",
+						Address:      "some-one@xn--ho-hia.de",
+						Destinations: []string{"other@xn--ho-hia.de"},
+					},
+				},
+			},
+			want: map[string]string{
+				"aliases.tf": `resource "migadu_alias" "some-one" {
+  local_part         = "some-one"
+  domain_name        = "hoß.de"
+  destinations       = ["other@hoß.de"]
+  is_internal        = false
+  expirable          = false
+  remove_upon_expiry = false
+}
+
+import {
+  to = migadu_alias.some-one
+  id = "some-one@hoß.de"
+}
+
+resource "migadu_alias" "some_one" {
+  local_part         = "some.one"
+  domain_name        = "hoß.de"
+  destinations       = ["another@bücher.example", "other@hoß.de"]
+  is_internal        = false
+  expirable          = false
+  remove_upon_expiry = false
+}
+
+import {
+  to = migadu_alias.some_one
+  id = "some.one@hoß.de"
+}
+`,
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &testCase.state))
+			defer server.Close()
+
+			files, err := command.Export(context.Background(), newClient(t, server.URL), testCase.domain)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			got := make(map[string]string, len(files))
+			for name, content := range files {
+				got[name] = string(content)
+			}
+			assert.Equal(t, testCase.want, got, "files")
+		})
+	}
+}
+
+func TestExport_Unique_Names(t *testing.T) {
+	state := simulator.State{
+		Mailboxes: []model.Mailbox{
+			{LocalPart: "a.b", DomainName: "example.com", Address: "a.b@example.com"},
+			{LocalPart: "a_b", DomainName: "example.com", Address: "a_b@example.com"},
+			{LocalPart: "a_b_2", DomainName: "example.com", Address: "a_b_2@example.com"},
+			{LocalPart: "a", DomainName: "example.com", Address: "a@example.com"},
+		},
+		Identities: []model.Identity{
+			{LocalPart: "b", DomainName: "example.com", Address: "b@example.com", PasswordUse: "custom"},
+		},
+		Aliases: []model.Alias{
+			{LocalPart: "a.b", DomainName: "example.com", Address: "a.b@example.com", Destinations: []string{"a@example.com"}},
+			{LocalPart: "a_b", DomainName: "example.com", Address: "a_b@example.com", Destinations: []string{"a@example.com"}},
+		},
+		Rewrites: []model.RewriteRule{
+			{DomainName: "example.com", Name: "a_b", LocalPartRule: "a-*", OrderNum: 1, Destinations: []string{"a@example.com"}},
+		},
+	}
+	server := httptest.NewServer(simulator.MigaduAPI(t, &state))
+	defer server.Close()
+
+	files, err := command.Export(context.Background(), newClient(t, server.URL), "example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	blocks := regexp.MustCompile(`(?m)^(resource "[a-z_]+"|variable) "([^"]+)"`)
+	seen := make(map[string]string)
+	for name, content := range files {
+		for _, match := range blocks.FindAllStringSubmatch(string(content), -1) {
+			address := match[1] + " " + match[2]
+			if other, ok := seen[address]; ok {
+				t.Errorf("%s declared in %s and %s", address, other, name)
+			}
+			seen[address] = name
+		}
+	}
+	// the simulator returns the identity for each of the 4 mailboxes, each mailbox and identity declares a password
+	assert.Len(t, seen, 4+4+2+1+4+4, "declarations")
+}
+
+func TestExport_API_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		statusCode int
+	}{
+		"error-404": {statusCode: http.StatusNotFound},
+		"error-500": {statusCode: http.StatusInternalServerError},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{StatusCode: testCase.statusCode}))
+			defer server.Close()
+
+			_, err := command.Export(context.Background(), newClient(t, server.URL), "example.com")
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRun_Export(t *testing.T) {
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{
+		Aliases: []model.Alias{
+			{
+				LocalPart:    "some",
+				DomainName:   "example.com",
+				Address:      "some@example.com",
+				Destinations: []string{"other@example.com"},
+			},
+		},
+	}))
+	defer server.Close()
+
+	output := t.TempDir()
+	var stdout, stderr bytes.Buffer
+	code := command.Run(context.Background(), []string{
+		"export",
+		"-endpoint", server.URL,
+		"-username", "username",
+		"-token", "token",
+		"-domain", "example.com",
+		"-output", output,
+	}, &stdout, &stderr)
+
+	assert.Equal(t, 0, code, "exit code (stderr: %s)", stderr.String())
+	assert.FileExists(t, filepath.Join(output, "aliases.tf"))
+	assert.NoFileExists(t, filepath.Join(output, "mailboxes.tf"))
+	assert.Contains(t, stdout.String(), filepath.Join(output, "aliases.tf"))
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Setenv("MIGADU_USERNAME", "")
+	t.Setenv("MIGADU_TOKEN", "")
+
+	testCases := map[string]struct {
+		args []string
+		code int
+		want string
+	}{
+		"no-command": {
+			args: []string{},
+			code: 2,
+			want: "Usage:",
+		},
+		"unknown-command": {
+			args: []string{"unknown"},
+			code: 2,
+			want: `unknown command "unknown"`,
+		},
+		"missing-domain": {
+			args: []string{"export", "-username", "username", "-token", "token"},
+			code: 1,
+			want: "missing domain",
+		},
+		"missing-username": {
+			args: []string{"export", "-token", "token", "-domain", "example.com"},
+			code: 1,
+			want: "missing username",
+		},
+		"missing-token": {
+			args: []string{"export", "-username", "username", "-domain", "example.com"},
+			code: 1,
+			want: "missing token",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := command.Run(context.Background(), testCase.args, &stdout, &stderr)
+
+			assert.Equal(t, testCase.code, code, "exit code")
+			assert.Contains(t, stderr.String(), testCase.want, "stderr")
+		})
+	}
+}
+
+func newClient(t *testing.T, endpoint string) *client.MigaduClient {
+	username := "username"
+	token := "token"
+	migaduClient, err := client.New(&endpoint, &username, &token, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Could not create client: %s", err)
+	}
+	return migaduClient
+}
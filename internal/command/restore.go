@@ -0,0 +1,195 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/metio/migadu-client.go/client"
+	"golang.org/x/net/idna"
+	"io"
+	"os"
+)
+
+func runRestore(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := newFlagSet("restore", stderr)
+	var clientFlags clientFlags
+	clientFlags.register(flags)
+	input := flags.String("input", "", "The backup file to restore.")
+	domain := flags.String("domain", "", "The domain to restore into. Defaults to the domain of the backup.")
+	dryRun := flags.Bool("dry-run", false, "Print the API calls that would be made without making them.")
+	sendInvitations := flags.Bool("send-invitations", false, "Recreate mailboxes, which sends an invitation email to the password recovery email of each of them.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *input == "" {
+		return fmt.Errorf("missing backup file, use -input")
+	}
+
+	content, err := os.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+	var backup Backup
+	if err = json.Unmarshal(content, &backup); err != nil {
+		return fmt.Errorf("invalid backup %s: %w", *input, err)
+	}
+	if *domain != "" {
+		backup.Domain = *domain
+	}
+
+	migaduClient, err := clientFlags.client()
+	if err != nil {
+		return err
+	}
+
+	return Restore(ctx, migaduClient, &backup, RestoreOptions{DryRun: *dryRun, SendInvitations: *sendInvitations}, stdout, stderr)
+}
+
+// RestoreOptions control which API calls Restore makes.
+type RestoreOptions struct {
+	// DryRun only writes the API calls that would be made without making them.
+	DryRun bool
+	// SendInvitations allows recreating mailboxes, which sends a real invitation email for each of them.
+	SendInvitations bool
+}
+
+// Restore creates all objects of the backup that do not exist in its domain. Existing objects are left untouched,
+// therefore restoring the same backup multiple times is safe. Since backups do not contain passwords, mailboxes are
+// recreated with an invitation sent to their password recovery email and identities with a custom password are
+// skipped. Mailboxes are only recreated with SendInvitations set, and the number of invitation emails is written
+// before any API call is made. Each API call is written to stdout, and skipped objects are reported on stderr.
+func Restore(ctx context.Context, migaduClient *client.MigaduClient, backup *Backup, options RestoreOptions, stdout io.Writer, stderr io.Writer) error {
+	if backup.Version != BackupVersion {
+		return fmt.Errorf("unsupported backup version %d, expected %d", backup.Version, BackupVersion)
+	}
+	if backup.Domain == "" {
+		return fmt.Errorf("backup does not specify a domain")
+	}
+
+	domain := backup.Domain
+	asciiDomain, err := idna.ToASCII(domain)
+	if err != nil {
+		return fmt.Errorf("invalid domain %q: %w", domain, err)
+	}
+
+	existing, err := fetchDomain(ctx, migaduClient, domain)
+	if err != nil {
+		return err
+	}
+
+	mailboxes := make(map[string]bool)
+	for _, mailbox := range existing.mailboxes {
+		mailboxes[mailbox.LocalPart] = true
+	}
+	identities := make(map[string]bool)
+	for _, entry := range existing.identities {
+		identities[entry.mailbox+"/"+entry.identity.LocalPart] = true
+	}
+	aliases := make(map[string]bool)
+	for _, alias := range existing.aliases {
+		aliases[alias.LocalPart] = true
+	}
+	rewriteRules := make(map[string]bool)
+	for _, rewriteRule := range existing.rewriteRules {
+		rewriteRules[rewriteRule.Name] = true
+	}
+
+	call := func(kind string, id string, path string) {
+		fmt.Fprintf(stdout, "POST /domains/%s/%s (%s %s)\n", asciiDomain, path, kind, id)
+	}
+
+	var invitations int
+	for _, mailbox := range backup.Mailboxes {
+		if !mailboxes[mailbox.LocalPart] && mailbox.PasswordRecoveryEmail != "" {
+			invitations++
+		}
+	}
+	if invitations > 0 && options.SendInvitations {
+		fmt.Fprintf(stdout, "Recreating mailboxes sends %d invitation email(s)\n", invitations)
+	}
+
+	for _, mailbox := range backup.Mailboxes {
+		if mailboxes[mailbox.LocalPart] {
+			continue
+		}
+		id := fmt.Sprintf("%s@%s", mailbox.LocalPart, domain)
+		if mailbox.PasswordRecoveryEmail == "" {
+			fmt.Fprintf(stderr, "Skipping mailbox %s: it has no password recovery email to send an invitation to\n", id)
+			continue
+		}
+		if !options.SendInvitations {
+			fmt.Fprintf(stderr, "Skipping mailbox %s: recreating it sends an invitation email to %s, use -send-invitations to allow it\n", id, mailbox.PasswordRecoveryEmail)
+			continue
+		}
+
+		call("mailbox", id, "mailboxes")
+		mailboxes[mailbox.LocalPart] = true
+		if options.DryRun {
+			continue
+		}
+		mailbox.PasswordMethod = "invitation"
+		if _, err = migaduClient.CreateMailbox(ctx, domain, &mailbox); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range backup.Identities {
+		identity := entry.Identity
+		if identities[entry.Mailbox+"/"+identity.LocalPart] {
+			continue
+		}
+		id := fmt.Sprintf("%s@%s/%s", entry.Mailbox, domain, identity.LocalPart)
+		if !mailboxes[entry.Mailbox] {
+			fmt.Fprintf(stderr, "Skipping identity %s: its mailbox does not exist\n", id)
+			continue
+		}
+		if identity.PasswordUse == "custom" {
+			fmt.Fprintf(stderr, "Skipping identity %s: its custom password is not part of the backup\n", id)
+			continue
+		}
+
+		call("identity", id, fmt.Sprintf("mailboxes/%s/identities", entry.Mailbox))
+		if options.DryRun {
+			continue
+		}
+		if _, err = migaduClient.CreateIdentity(ctx, domain, entry.Mailbox, &identity); err != nil {
+			return err
+		}
+	}
+
+	for _, alias := range backup.Aliases {
+		if aliases[alias.LocalPart] {
+			continue
+		}
+
+		call("alias", fmt.Sprintf("%s@%s", alias.LocalPart, domain), "aliases")
+		if options.DryRun {
+			continue
+		}
+		if _, err = migaduClient.CreateAlias(ctx, domain, &alias); err != nil {
+			return err
+		}
+	}
+
+	for _, rewriteRule := range backup.RewriteRules {
+		if rewriteRules[rewriteRule.Name] {
+			continue
+		}
+
+		call("rewrite rule", fmt.Sprintf("%s/%s", domain, rewriteRule.Name), "rewrites")
+		if options.DryRun {
+			continue
+		}
+		if _, err = migaduClient.CreateRewriteRule(ctx, domain, &rewriteRule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/command"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func restoreBackup() *command.Backup {
+	return &command.Backup{
+		Version: command.BackupVersion,
+		Domain:  "example.com",
+		Mailboxes: []model.Mailbox{
+			{
+				LocalPart: "existing",
+				Name:      "Existing Name",
+			},
+			{
+				LocalPart:             "missing",
+				Name:                  "Missing Name",
+				PasswordRecoveryEmail: "recovery@example.org",
+			},
+			{
+				LocalPart: "unrecoverable",
+				Name:      "Unrecoverable Name",
+			},
+		},
+		Identities: []command.BackupIdentity{
+			{
+				Mailbox:  "missing",
+				Identity: model.Identity{LocalPart: "identity", Name: "Identity Name", PasswordUse: "mailbox"},
+			},
+			{
+				Mailbox:  "missing",
+				Identity: model.Identity{LocalPart: "custom", Name: "Custom Name", PasswordUse: "custom"},
+			},
+			{
+				Mailbox:  "unrecoverable",
+				Identity: model.Identity{LocalPart: "orphan", Name: "Orphan Name", PasswordUse: "mailbox"},
+			},
+		},
+		Aliases: []model.Alias{
+			{
+				LocalPart:    "alias",
+				Destinations: []string{"missing@example.com"},
+			},
+		},
+		RewriteRules: []model.RewriteRule{
+			{
+				Name:          "sample",
+				LocalPartRule: "prefix-*",
+				OrderNum:      1,
+				Destinations:  []string{"missing@example.com"},
+			},
+		},
+	}
+}
+
+func restoreState() *simulator.State {
+	return &simulator.State{
+		Mailboxes: []model.Mailbox{
+			{
+				LocalPart:  "existing",
+				DomainName: "example.com",
+				Address:    "existing@example.com",
+				Name:       "Existing Name",
+			},
+		},
+	}
+}
+
+func TestRestore_API_Success(t *testing.T) {
+	state := restoreState()
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := command.Restore(context.Background(), newClient(t, server.URL), restoreBackup(), command.RestoreOptions{SendInvitations: true}, &stdout, &stderr)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, `Recreating mailboxes sends 1 invitation email(s)
+POST /domains/example.com/mailboxes (mailbox missing@example.com)
+POST /domains/example.com/mailboxes/missing/identities (identity missing@example.com/identity)
+POST /domains/example.com/aliases (alias alias@example.com)
+POST /domains/example.com/rewrites (rewrite rule example.com/sample)
+`, stdout.String(), "stdout")
+	assert.Equal(t, `Skipping mailbox unrecoverable@example.com: it has no password recovery email to send an invitation to
+Skipping identity missing@example.com/custom: its custom password is not part of the backup
+Skipping identity unrecoverable@example.com/orphan: its mailbox does not exist
+`, stderr.String(), "stderr")
+	assert.Len(t, state.Mailboxes, 2, "mailboxes")
+	assert.Equal(t, "invitation", state.Mailboxes[1].PasswordMethod, "password method")
+	assert.Len(t, state.Identities, 1, "identities")
+	assert.Len(t, state.Aliases, 1, "aliases")
+	assert.Len(t, state.Rewrites, 1, "rewrite rules")
+}
+
+func TestRestore_Without_Invitations(t *testing.T) {
+	state := restoreState()
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := command.Restore(context.Background(), newClient(t, server.URL), restoreBackup(), command.RestoreOptions{}, &stdout, &stderr)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, `POST /domains/example.com/aliases (alias alias@example.com)
+POST /domains/example.com/rewrites (rewrite rule example.com/sample)
+`, stdout.String(), "stdout")
+	assert.Equal(t, `Skipping mailbox missing@example.com: recreating it sends an invitation email to recovery@example.org, use -send-invitations to allow it
+Skipping mailbox unrecoverable@example.com: it has no password recovery email to send an invitation to
+Skipping identity missing@example.com/identity: its mailbox does not exist
+Skipping identity missing@example.com/custom: its mailbox does not exist
+Skipping identity unrecoverable@example.com/orphan: its mailbox does not exist
+`, stderr.String(), "stderr")
+	assert.Len(t, state.Mailboxes, 1, "mailboxes")
+	assert.Empty(t, state.Identities, "identities")
+}
+
+func TestRestore_Idempotent(t *testing.T) {
+	state := restoreState()
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+	migaduClient := newClient(t, server.URL)
+
+	var stdout, stderr bytes.Buffer
+	if err := command.Restore(context.Background(), migaduClient, restoreBackup(), command.RestoreOptions{SendInvitations: true}, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	stdout.Reset()
+	if err := command.Restore(context.Background(), migaduClient, restoreBackup(), command.RestoreOptions{SendInvitations: true}, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	assert.Empty(t, stdout.String(), "stdout")
+	assert.Len(t, state.Mailboxes, 2, "mailboxes")
+	assert.Len(t, state.Identities, 1, "identities")
+	assert.Len(t, state.Aliases, 1, "aliases")
+	assert.Len(t, state.Rewrites, 1, "rewrite rules")
+}
+
+func TestRestore_DryRun(t *testing.T) {
+	state := restoreState()
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := command.Restore(context.Background(), newClient(t, server.URL), restoreBackup(), command.RestoreOptions{DryRun: true, SendInvitations: true}, &stdout, &stderr)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Contains(t, stdout.String(), "Recreating mailboxes sends 1 invitation email(s)", "stdout")
+	assert.Contains(t, stdout.String(), "POST /domains/example.com/mailboxes (mailbox missing@example.com)", "stdout")
+	assert.Contains(t, stdout.String(), "POST /domains/example.com/mailboxes/missing/identities (identity missing@example.com/identity)", "stdout")
+	assert.Len(t, state.Mailboxes, 1, "mailboxes")
+	assert.Empty(t, state.Identities, "identities")
+	assert.Empty(t, state.Aliases, "aliases")
+	assert.Empty(t, state.Rewrites, "rewrite rules")
+}
+
+func TestRestore_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		backup command.Backup
+		want   string
+	}{
+		"unsupported-version": {
+			backup: command.Backup{Version: command.BackupVersion + 1, Domain: "example.com"},
+			want:   "unsupported backup version",
+		},
+		"missing-domain": {
+			backup: command.Backup{Version: command.BackupVersion},
+			want:   "backup does not specify a domain",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := command.Restore(context.Background(), nil, &testCase.backup, command.RestoreOptions{DryRun: true}, &stdout, &stderr)
+
+			assert.ErrorContains(t, err, testCase.want)
+		})
+	}
+}
+
+func TestRun_Restore(t *testing.T) {
+	state := restoreState()
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	content, err := json.Marshal(restoreBackup())
+	if err != nil {
+		t.Fatalf("Could not write backup: %s", err)
+	}
+	input := filepath.Join(t.TempDir(), "backup.json")
+	if err = os.WriteFile(input, content, 0o600); err != nil {
+		t.Fatalf("Could not write backup: %s", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := command.Run(context.Background(), []string{
+		"restore",
+		"-endpoint", server.URL,
+		"-username", "username",
+		"-token", "token",
+		"-input", input,
+		"--dry-run",
+		"-send-invitations",
+	}, &stdout, &stderr)
+
+	assert.Equal(t, 0, code, "exit code (stderr: %s)", stderr.String())
+	assert.Contains(t, stdout.String(), "Recreating mailboxes sends 1 invitation email(s)", "stdout")
+	assert.Contains(t, stdout.String(), "POST /domains/example.com/aliases (alias alias@example.com)", "stdout")
+	assert.Empty(t, state.Aliases, "aliases")
+}
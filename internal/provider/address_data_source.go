@@ -0,0 +1,399 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	_ datasource.DataSource              = (*AddressDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*AddressDataSource)(nil)
+)
+
+// The kinds of objects that can receive emails for an address.
+const (
+	addressKindMailbox     = "mailbox"
+	addressKindIdentity    = "identity"
+	addressKindAlias       = "alias"
+	addressKindRewriteRule = "rewrite_rule"
+	addressKindExternal    = "external"
+	addressKindUnknown     = "unknown"
+)
+
+func NewAddressDataSource() datasource.DataSource {
+	return &AddressDataSource{}
+}
+
+type AddressDataSource struct {
+	MigaduClient          *client.MigaduClient
+	DomainScope           DomainScope
+	IdentitiesConcurrency int
+}
+
+type AddressDataSourceModel struct {
+	ID              custom_types.EmailAddressValue    `tfsdk:"id"`
+	Address         custom_types.EmailAddressValue    `tfsdk:"address"`
+	Kind            types.String                      `tfsdk:"kind"`
+	Owner           types.String                      `tfsdk:"owner"`
+	Destinations    custom_types.EmailAddressSetValue `tfsdk:"destinations"`
+	DeliveryTargets custom_types.EmailAddressSetValue `tfsdk:"delivery_targets"`
+}
+
+func (d *AddressDataSource) Metadata(_ context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_address"
+}
+
+func (d *AddressDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description:         "Resolve an email address to the mailbox, identity, alias, or rewrite rule that receives its emails and follow aliases and rewrite rules to their final delivery targets.",
+		MarkdownDescription: "Resolve an email address to the mailbox, identity, alias, or rewrite rule that receives its emails and follow aliases and rewrite rules to their final delivery targets.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description:         "Same value as the 'address' attribute.",
+				MarkdownDescription: "Same value as the `address` attribute.",
+				Required:            false,
+				Optional:            false,
+				Computed:            true,
+				CustomType:          custom_types.EmailAddressType{},
+			},
+			"address": schema.StringAttribute{
+				Description:         "The email address to resolve.",
+				MarkdownDescription: "The email address to resolve.",
+				Required:            true,
+				Optional:            false,
+				Computed:            false,
+				CustomType:          custom_types.EmailAddressType{},
+			},
+			"kind": schema.StringAttribute{
+				Description:         "The kind of object that receives emails for the address. One of 'mailbox', 'identity', 'alias', 'rewrite_rule', 'external' for addresses of domains not managed by Migadu, or 'unknown' for addresses that match no object of their domain.",
+				MarkdownDescription: "The kind of object that receives emails for the address. One of `mailbox`, `identity`, `alias`, `rewrite_rule`, `external` for addresses of domains not managed by Migadu, or `unknown` for addresses that match no object of their domain.",
+				Required:            false,
+				Optional:            false,
+				Computed:            true,
+			},
+			"owner": schema.StringAttribute{
+				Description:         "The object that receives emails for the address. Contains the address of the mailbox for mailboxes and identities, the address of the alias for aliases, and the name of the rewrite rule for rewrite rules. Empty for external and unknown addresses.",
+				MarkdownDescription: "The object that receives emails for the address. Contains the address of the mailbox for mailboxes and identities, the address of the alias for aliases, and the name of the rewrite rule for rewrite rules. Empty for external and unknown addresses.",
+				Required:            false,
+				Optional:            false,
+				Computed:            true,
+			},
+			"destinations": schema.SetAttribute{
+				Description:         "The destinations of the alias or rewrite rule that receives emails for the address. Empty for all other kinds.",
+				MarkdownDescription: "The destinations of the alias or rewrite rule that receives emails for the address. Empty for all other kinds.",
+				Required:            false,
+				Optional:            false,
+				Computed:            true,
+				CustomType: custom_types.EmailAddressSetType{
+					SetType: types.SetType{
+						ElemType: custom_types.EmailAddressType{},
+					},
+				},
+			},
+			"delivery_targets": schema.SetAttribute{
+				Description:         "The mailboxes and external addresses that finally receive emails sent to the address after following all aliases and rewrite rules, including those of other domains in the domain scope of the provider. Destinations outside the domain scope are kept as external addresses without following their aliases and rewrite rules. Destinations that match no object of their domain are left out. Forwarding loops, destinations outside the domain scope, and left out destinations are reported as warnings.",
+				MarkdownDescription: "The mailboxes and external addresses that finally receive emails sent to the address after following all aliases and rewrite rules, including those of other domains in the domain scope of the provider. Destinations outside the domain scope are kept as external addresses without following their aliases and rewrite rules. Destinations that match no object of their domain are left out. Forwarding loops, destinations outside the domain scope, and left out destinations are reported as warnings.",
+				Required:            false,
+				Optional:            false,
+				Computed:            true,
+				CustomType: custom_types.EmailAddressSetType{
+					SetType: types.SetType{
+						ElemType: custom_types.EmailAddressType{},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AddressDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.MigaduClient = providerData.MigaduClient
+		d.DomainScope = providerData.DomainScope
+		d.IdentitiesConcurrency = providerData.IdentitiesConcurrency
+	} else {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+	}
+}
+
+func (d *AddressDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var data AddressDataSourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &data)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	_, domainName := data.Address.Split()
+	response.Diagnostics.Append(d.DomainScope.Diagnostics(path.Root("address"), custom_types.NewDomainNameValue(domainName))...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	address, err := data.Address.NormalizedValue()
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("address"),
+			"Invalid Email Address",
+			"The supplied email address cannot be normalized: "+err.Error(),
+		)
+		return
+	}
+
+	resolver := &addressResolver{
+		migaduClient:          d.MigaduClient,
+		domainScope:           d.DomainScope,
+		identitiesConcurrency: d.IdentitiesConcurrency,
+		domains:               make(map[string]*addressDomain),
+	}
+	match, diagnostic := resolver.resolve(ctx, address)
+	if diagnostic != nil {
+		response.Diagnostics.Append(diagnostic)
+		return
+	}
+	targets := make(map[string]struct{})
+	resolver.deliveryTargets(ctx, address, nil, targets, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	destinations, diags := custom_types.NewEmailAddressSetValueFrom(ctx, match.destinations)
+	response.Diagnostics.Append(diags...)
+	deliveryTargets, diags := custom_types.NewEmailAddressSetValueFrom(ctx, slices.Sorted(maps.Keys(targets)))
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.Address
+	data.Kind = types.StringValue(match.kind)
+	data.Owner = types.StringValue(match.owner)
+	data.Destinations = destinations
+	data.DeliveryTargets = deliveryTargets
+
+	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
+}
+
+// addressResolver looks up email addresses in the mailboxes, identities, aliases, and rewrite rules of their domain.
+// The objects of each domain are fetched once and reused for all addresses of the same domain.
+type addressResolver struct {
+	migaduClient          *client.MigaduClient
+	domainScope           DomainScope
+	identitiesConcurrency int
+	domains               map[string]*addressDomain
+}
+
+// addressDomain holds the objects of a single domain. The owners of identities are only fetched once an address
+// matches no mailbox and no alias, since the Migadu API requires one request per mailbox to list them.
+type addressDomain struct {
+	name           string
+	managed        bool
+	mailboxes      []model.Mailbox
+	aliases        []model.Alias
+	rewriteRules   []model.RewriteRule
+	identityOwners map[string]string
+}
+
+// addressMatch describes the object that receives emails for an address.
+type addressMatch struct {
+	kind         string
+	owner        string
+	destinations []string
+}
+
+// resolve returns the object that receives emails for the given normalized address. Migadu prefers exact matches of
+// mailboxes, aliases, and identities over rewrite rules, which are evaluated in their order.
+func (r *addressResolver) resolve(ctx context.Context, address string) (addressMatch, diag.Diagnostic) {
+	localPart, domainName := custom_types.NewEmailAddressValue(address).Split()
+	domain, diagnostic := r.domain(ctx, domainName)
+	if diagnostic != nil {
+		return addressMatch{}, diagnostic
+	}
+	if !domain.managed {
+		return addressMatch{kind: addressKindExternal}, nil
+	}
+
+	for _, mailbox := range domain.mailboxes {
+		if strings.EqualFold(mailbox.LocalPart, localPart) {
+			return addressMatch{kind: addressKindMailbox, owner: domain.address(mailbox.LocalPart)}, nil
+		}
+	}
+	for _, alias := range domain.aliases {
+		if strings.EqualFold(alias.LocalPart, localPart) {
+			return addressMatch{kind: addressKindAlias, owner: domain.address(alias.LocalPart), destinations: alias.Destinations}, nil
+		}
+	}
+
+	if domain.identityOwners == nil {
+		owners, diagnostic := r.identityOwners(ctx, domain)
+		if diagnostic != nil {
+			return addressMatch{}, diagnostic
+		}
+		domain.identityOwners = owners
+	}
+	if owner, ok := domain.identityOwners[strings.ToLower(localPart)]; ok {
+		return addressMatch{kind: addressKindIdentity, owner: domain.address(owner)}, nil
+	}
+
+	for _, rewriteRule := range domain.rewriteRules {
+		if matchesLocalPartRule(rewriteRule.LocalPartRule, localPart) {
+			return addressMatch{kind: addressKindRewriteRule, owner: rewriteRule.Name, destinations: rewriteRule.Destinations}, nil
+		}
+	}
+	return addressMatch{kind: addressKindUnknown}, nil
+}
+
+// deliveryTargets adds the mailboxes and external addresses that finally receive emails sent to the given address to
+// the targets. The chain contains all addresses that forwarded to the given address and is used to detect loops.
+// Destinations that match no object of their domain and destinations outside the domain scope are reported as
+// warnings, since the resulting targets are incomplete in both cases.
+func (r *addressResolver) deliveryTargets(ctx context.Context, address string, chain []string, targets map[string]struct{}, diagnostics *diag.Diagnostics) {
+	normalized, err := custom_types.NewEmailAddressValue(address).NormalizedValue()
+	if err != nil {
+		diagnostics.AddWarning(
+			"Invalid Destination",
+			fmt.Sprintf("The destination '%s' cannot be normalized and is ignored: %s", address, err),
+		)
+		return
+	}
+	chain = append(slices.Clip(chain), normalized)
+	if slices.Contains(chain[:len(chain)-1], normalized) {
+		diagnostics.AddWarning(
+			"Forwarding Loop Detected",
+			fmt.Sprintf("Emails sent to '%s' are forwarded in a loop: %s", chain[0], strings.Join(chain, " -> ")),
+		)
+		return
+	}
+
+	match, diagnostic := r.resolve(ctx, normalized)
+	if diagnostic != nil {
+		diagnostics.Append(diagnostic)
+		return
+	}
+	switch match.kind {
+	case addressKindMailbox, addressKindIdentity:
+		targets[match.owner] = struct{}{}
+	case addressKindExternal:
+		targets[normalized] = struct{}{}
+		if _, domainName := custom_types.NewEmailAddressValue(normalized).Split(); !r.domainScope.allows(domainName) {
+			diagnostics.AddWarning(
+				"Delivery Target Outside Domain Scope",
+				fmt.Sprintf("Emails sent to '%s' are forwarded to '%s' whose domain is outside the domain scope of the provider. "+
+					"Its aliases and rewrite rules are not followed and the address is treated as an external delivery target: %s", chain[0], normalized, strings.Join(chain, " -> ")),
+			)
+		}
+	case addressKindUnknown:
+		if len(chain) > 1 {
+			diagnostics.AddWarning(
+				"Unknown Destination",
+				fmt.Sprintf("Emails sent to '%s' are forwarded to '%s' which matches no mailbox, identity, alias, or rewrite rule of its domain. "+
+					"The address is left out of the delivery targets: %s", chain[0], normalized, strings.Join(chain, " -> ")),
+			)
+		}
+	case addressKindAlias, addressKindRewriteRule:
+		for _, destination := range match.destinations {
+			r.deliveryTargets(ctx, destination, chain, targets, diagnostics)
+			if diagnostics.HasError() {
+				return
+			}
+		}
+	}
+}
+
+// domain returns the objects of the given normalized domain. Domains outside the domain scope are not fetched and
+// treated like domains that are not managed by Migadu.
+func (r *addressResolver) domain(ctx context.Context, domainName string) (*addressDomain, diag.Diagnostic) {
+	if domain, ok := r.domains[domainName]; ok {
+		return domain, nil
+	}
+
+	domain := &addressDomain{name: domainName}
+	r.domains[domainName] = domain
+	if !r.domainScope.allows(domainName) {
+		return domain, nil
+	}
+
+	mailboxes, err := r.migaduClient.GetMailboxes(ctx, domainName)
+	if err != nil {
+		var requestError *client.RequestError
+		if errors.As(err, &requestError) && requestError.StatusCode == http.StatusNotFound {
+			return domain, nil
+		}
+		return nil, MailboxReadError(err)
+	}
+	aliases, err := r.migaduClient.GetAliases(ctx, domainName)
+	if err != nil {
+		return nil, AliasReadError(err)
+	}
+	rewriteRules, err := r.migaduClient.GetRewriteRules(ctx, domainName)
+	if err != nil {
+		return nil, RewriteRuleReadError(err)
+	}
+
+	domain.managed = true
+	domain.mailboxes = mailboxes.Mailboxes
+	domain.aliases = aliases.Aliases
+	domain.rewriteRules = slices.SortedStableFunc(slices.Values(rewriteRules.RewriteRules), func(a, b model.RewriteRule) int {
+		return cmp.Compare(a.OrderNum, b.OrderNum)
+	})
+	return domain, nil
+}
+
+// identityOwners returns the local part of the owning mailbox for the lower-case local part of each identity of the
+// given domain.
+func (r *addressResolver) identityOwners(ctx context.Context, domain *addressDomain) (map[string]string, diag.Diagnostic) {
+	localParts := make([]string, 0, len(domain.mailboxes))
+	for _, mailbox := range domain.mailboxes {
+		localParts = append(localParts, mailbox.LocalPart)
+	}
+	identities, err := getMailboxIdentities(ctx, r.migaduClient, r.identitiesConcurrency, domain.name, localParts)
+	if err != nil {
+		return nil, IdentityReadError(err)
+	}
+
+	owners := make(map[string]string)
+	for index, mailboxIdentities := range identities {
+		for _, identity := range mailboxIdentities {
+			owners[strings.ToLower(identity.LocalPart)] = localParts[index]
+		}
+	}
+	return owners, nil
+}
+
+func (d *addressDomain) address(localPart string) string {
+	return strings.ToLower(localPart) + "@" + d.name
+}
+
+// matchesLocalPartRule reports whether the local part matches the rule of a rewrite rule. Rules use '*' as a wildcard
+// for any number of characters and are matched case-insensitive.
+func matchesLocalPartRule(rule string, localPart string) bool {
+	pattern := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(rule), `\*`, ".*") + "$"
+	matched, err := regexp.MatchString(pattern, localPart)
+	return err == nil && matched
+}
@@ -0,0 +1,241 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAddressDataSource(t *testing.T) {
+	ctx := context.Background()
+	api := sandbox.New(sandbox.State{
+		Mailboxes: []model.Mailbox{
+			{LocalPart: "alice", DomainName: "example.com", Address: "alice@example.com"},
+			{LocalPart: "bob", DomainName: "example.com", Address: "bob@example.com"},
+			{LocalPart: "carol", DomainName: "example.org", Address: "carol@example.org"},
+		},
+		Identities: []sandbox.Identity{
+			{Mailbox: "alice", Identity: model.Identity{LocalPart: "alice-work", DomainName: "example.com", Address: "alice-work@example.com"}},
+		},
+		Aliases: []model.Alias{
+			{LocalPart: "team", DomainName: "example.com", Destinations: []string{"alice@example.com", "sales@example.com"}},
+			{LocalPart: "sales", DomainName: "example.com", Destinations: []string{"bob@example.com", "someone@external.test"}},
+			{LocalPart: "work", DomainName: "example.com", Destinations: []string{"alice-work@example.com", "carol@example.org"}},
+			{LocalPart: "loop-a", DomainName: "example.com", Destinations: []string{"loop-b@example.com"}},
+			{LocalPart: "loop-b", DomainName: "example.com", Destinations: []string{"loop-a@example.com", "alice@example.com"}},
+			{LocalPart: "broken", DomainName: "example.com", Destinations: []string{"nobody@example.com"}},
+			{LocalPart: "relay", DomainName: "example.com", Destinations: []string{"forward@example.org"}},
+			{LocalPart: "forward", DomainName: "example.org", Destinations: []string{"carol@example.org"}},
+		},
+		RewriteRules: []model.RewriteRule{
+			{Name: "support-all", DomainName: "example.com", LocalPartRule: "support-*", OrderNum: 2, Destinations: []string{"bob@example.com"}},
+			{Name: "support-urgent", DomainName: "example.com", LocalPartRule: "support-urgent-*", OrderNum: 1, Destinations: []string{"team@example.com"}},
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if strings.Contains(request.URL.Path, "/domains/external.test/") {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		api.ServeHTTP(writer, request)
+	}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		address          string
+		providerConfig   map[string]tftypes.Value
+		wantKind         string
+		wantOwner        string
+		wantDestinations []string
+		wantTargets      []string
+		wantWarning      string
+		wantError        string
+	}{
+		"mailbox": {
+			address:     "alice@example.com",
+			wantKind:    "mailbox",
+			wantOwner:   "alice@example.com",
+			wantTargets: []string{"alice@example.com"},
+		},
+		"mailbox-case-insensitive": {
+			address:     "Alice@Example.com",
+			wantKind:    "mailbox",
+			wantOwner:   "alice@example.com",
+			wantTargets: []string{"alice@example.com"},
+		},
+		"identity": {
+			address:     "alice-work@example.com",
+			wantKind:    "identity",
+			wantOwner:   "alice@example.com",
+			wantTargets: []string{"alice@example.com"},
+		},
+		"alias-chain": {
+			address:          "team@example.com",
+			wantKind:         "alias",
+			wantOwner:        "team@example.com",
+			wantDestinations: []string{"alice@example.com", "sales@example.com"},
+			wantTargets:      []string{"alice@example.com", "bob@example.com", "someone@external.test"},
+		},
+		"alias-other-domain": {
+			address:          "work@example.com",
+			wantKind:         "alias",
+			wantOwner:        "work@example.com",
+			wantDestinations: []string{"alice-work@example.com", "carol@example.org"},
+			wantTargets:      []string{"alice@example.com", "carol@example.org"},
+		},
+		"alias-chain-other-domain": {
+			address:          "relay@example.com",
+			wantKind:         "alias",
+			wantOwner:        "relay@example.com",
+			wantDestinations: []string{"forward@example.org"},
+			wantTargets:      []string{"carol@example.org"},
+		},
+		"alias-chain-denied-domain": {
+			address: "relay@example.com",
+			providerConfig: map[string]tftypes.Value{
+				"denied_domains": stringSet([]string{"example.org"}),
+			},
+			wantKind:         "alias",
+			wantOwner:        "relay@example.com",
+			wantDestinations: []string{"forward@example.org"},
+			wantTargets:      []string{"forward@example.org"},
+			wantWarning:      "Delivery Target Outside Domain Scope",
+		},
+		"alias-chain-not-allowed-domain": {
+			address: "relay@example.com",
+			providerConfig: map[string]tftypes.Value{
+				"allowed_domains": stringSet([]string{"example.com"}),
+			},
+			wantKind:         "alias",
+			wantOwner:        "relay@example.com",
+			wantDestinations: []string{"forward@example.org"},
+			wantTargets:      []string{"forward@example.org"},
+			wantWarning:      "Delivery Target Outside Domain Scope",
+		},
+		"alias-unknown-destination": {
+			address:          "broken@example.com",
+			wantKind:         "alias",
+			wantOwner:        "broken@example.com",
+			wantDestinations: []string{"nobody@example.com"},
+			wantWarning:      "Unknown Destination",
+		},
+		"loop": {
+			address:          "loop-a@example.com",
+			wantKind:         "alias",
+			wantOwner:        "loop-a@example.com",
+			wantDestinations: []string{"loop-b@example.com"},
+			wantTargets:      []string{"alice@example.com"},
+			wantWarning:      "Forwarding Loop Detected",
+		},
+		"rewrite-rule": {
+			address:          "support-42@example.com",
+			wantKind:         "rewrite_rule",
+			wantOwner:        "support-all",
+			wantDestinations: []string{"bob@example.com"},
+			wantTargets:      []string{"bob@example.com"},
+		},
+		"rewrite-rule-order": {
+			address:          "support-urgent-42@example.com",
+			wantKind:         "rewrite_rule",
+			wantOwner:        "support-urgent",
+			wantDestinations: []string{"team@example.com"},
+			wantTargets:      []string{"alice@example.com", "bob@example.com", "someone@external.test"},
+		},
+		"unknown": {
+			address:  "nobody@example.com",
+			wantKind: "unknown",
+		},
+		"external": {
+			address:     "someone@external.test",
+			wantKind:    "external",
+			wantTargets: []string{"someone@external.test"},
+		},
+		"denied-domain": {
+			address: "alice@example.com",
+			providerConfig: map[string]tftypes.Value{
+				"denied_domains": stringSet([]string{"example.com"}),
+			},
+			wantError: "Domain Not Allowed",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			providerServer := configuredProviderServerWith(t, server.URL, testCase.providerConfig)
+			schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatalf("GetProviderSchema error: %s", err)
+			}
+			dataSourceType := schemaResponse.DataSourceSchemas["migadu_address"].ValueType()
+
+			response, err := providerServer.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+				TypeName: "migadu_address",
+				Config: dynamicValue(t, dataSourceType, objectValue(dataSourceType, map[string]tftypes.Value{
+					"address": tftypes.NewValue(tftypes.String, testCase.address),
+				})),
+			})
+			if err != nil {
+				t.Fatalf("ReadDataSource error: %s", err)
+			}
+			assertDiagnosticSummary(t, testCase.wantError, response.Diagnostics)
+			if testCase.wantError != "" {
+				return
+			}
+			var warnings []string
+			for _, diagnostic := range response.Diagnostics {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityWarning {
+					warnings = append(warnings, diagnostic.Summary)
+				}
+			}
+			if testCase.wantWarning != "" {
+				assert.Equal(t, []string{testCase.wantWarning}, warnings, "warnings")
+			} else {
+				assert.Empty(t, warnings, "warnings")
+			}
+
+			state, err := response.State.Unmarshal(dataSourceType)
+			if err != nil {
+				t.Fatalf("Could not read state: %s", err)
+			}
+			var attributes map[string]tftypes.Value
+			if err := state.As(&attributes); err != nil {
+				t.Fatalf("Could not read attributes: %s", err)
+			}
+			assert.Equal(t, tftypes.NewValue(tftypes.String, testCase.address), attributes["id"], "id")
+			assert.Equal(t, tftypes.NewValue(tftypes.String, testCase.wantKind), attributes["kind"], "kind")
+			assert.Equal(t, tftypes.NewValue(tftypes.String, testCase.wantOwner), attributes["owner"], "owner")
+			assert.Equal(t, testCase.wantDestinations, sortedStrings(t, attributes["destinations"]), "destinations")
+			assert.Equal(t, testCase.wantTargets, sortedStrings(t, attributes["delivery_targets"]), "delivery_targets")
+		})
+	}
+}
+
+// sortedStrings returns the sorted elements of a set of strings or nil for an empty set.
+func sortedStrings(t *testing.T, set tftypes.Value) []string {
+	var elements []tftypes.Value
+	if err := set.As(&elements); err != nil {
+		t.Fatalf("Could not read set: %s", err)
+	}
+	var values []string
+	for _, element := range elements {
+		var value string
+		if err := element.As(&value); err != nil {
+			t.Fatalf("Could not read element: %s", err)
+		}
+		values = append(values, value)
+	}
+	slices.Sort(values)
+	return values
+}
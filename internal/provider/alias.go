@@ -20,32 +20,31 @@ func CreateAliasIDString(localPart string, domainName string) string {
 	return fmt.Sprintf("%s@%s", localPart, domainName)
 }
 
+// aliasAttributes maps the API fields of an alias to the attributes of the alias resource.
+var aliasAttributes = rootAttributes(map[string]string{
+	"local_part":         "local_part",
+	"domain_name":        "domain_name",
+	"destinations":       "destinations",
+	"is_internal":        "is_internal",
+	"expireable":         "expirable",
+	"expires_on":         "expires_on",
+	"remove_upon_expiry": "remove_upon_expiry",
+})
+
 func AliasCreateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Creating Alias",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Creating Alias", "alias", apiOperationCreate, err, aliasAttributes)
 }
 
 func AliasReadError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Reading Alias",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Reading Alias", "alias", apiOperationRead, err, nil)
 }
 
 func AliasUpdateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Updating Alias",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Updating Alias", "alias", apiOperationUpdate, err, aliasAttributes)
 }
 
 func AliasDeleteError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Deleting Alias",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Deleting Alias", "alias", apiOperationDelete, err, nil)
 }
 
 func AliasImportError(id string) diag.Diagnostic {
@@ -54,3 +53,10 @@ func AliasImportError(id string) diag.Diagnostic {
 		standardImportErrorDetail("local_part@domain_name", id),
 	)
 }
+
+func AliasUpgradeStateError(err error) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Error Upgrading Alias State",
+		standardUpgradeStateErrorDetail(err),
+	)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
@@ -28,6 +29,7 @@ func NewAliasDataSource() datasource.DataSource {
 
 type AliasDataSource struct {
 	migaduClient *client.MigaduClient
+	domainScope  DomainScope
 }
 
 type AliasDataSourceModel struct {
@@ -60,33 +62,39 @@ func (d *AliasDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				CustomType:          custom_types.EmailAddressType{},
 			},
 			"local_part": schema.StringAttribute{
-				Description:         "The local part of the alias.",
-				MarkdownDescription: "The local part of the alias.",
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
+				Description:         "The local part of the alias. Required unless 'address' is given.",
+				MarkdownDescription: "The local part of the alias. Required unless `address` is given.",
+				Required:            false,
+				Optional:            true,
+				Computed:            true,
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("domain_name")),
 				},
 			},
 			"domain_name": schema.StringAttribute{
-				Description:         "The domain name of the alias.",
-				MarkdownDescription: "The domain name of the alias.",
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
+				Description:         "The domain name of the alias. Required unless 'address' is given.",
+				MarkdownDescription: "The domain name of the alias. Required unless `address` is given.",
+				Required:            false,
+				Optional:            true,
+				Computed:            true,
 				CustomType:          custom_types.DomainNameType{},
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("local_part")),
 				},
 			},
 			"address": schema.StringAttribute{
-				Description:         "The email address of the alias 'local_part@domain_name' as returned by the Migadu API. This might be different from the 'id' attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
-				MarkdownDescription: "The email address of the alias `local_part@domain_name` as returned by the Migadu API. This might be different from the `id` attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
+				Description:         "The email address of the alias 'local_part@domain_name'. Can be given instead of 'local_part' and 'domain_name', otherwise contains the address as returned by the Migadu API. This might be different from the 'id' attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
+				MarkdownDescription: "The email address of the alias `local_part@domain_name`. Can be given instead of `local_part` and `domain_name`, otherwise contains the address as returned by the Migadu API. This might be different from the `id` attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
 				Required:            false,
-				Optional:            false,
+				Optional:            true,
 				Computed:            true,
 				CustomType:          custom_types.EmailAddressType{},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("local_part")),
+					stringvalidator.ConflictsWith(path.MatchRoot("domain_name")),
+				},
 			},
 			"destinations": schema.SetAttribute{
 				Description:         "List of email addresses that act as destinations of the alias.",
@@ -137,12 +145,13 @@ func (d *AliasDataSource) Configure(_ context.Context, request datasource.Config
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.migaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.migaduClient = providerData.MigaduClient
+		d.domainScope = providerData.DomainScope
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *model.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -154,6 +163,12 @@ func (d *AliasDataSource) Read(ctx context.Context, request datasource.ReadReque
 		return
 	}
 
+	domainPath := splitAddress(data.Address, &data.LocalPart, &data.DomainName)
+	response.Diagnostics.Append(d.domainScope.Diagnostics(domainPath, data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	alias, err := d.migaduClient.GetAlias(ctx, data.DomainName.ValueString(), data.LocalPart.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(AliasReadError(err))
@@ -167,7 +182,9 @@ func (d *AliasDataSource) Read(ctx context.Context, request datasource.ReadReque
 	}
 
 	data.ID = custom_types.NewEmailAddressValue(CreateAliasID(data.LocalPart, data.DomainName))
-	data.Address = custom_types.NewEmailAddressValue(alias.Address)
+	if data.Address.IsNull() {
+		data.Address = custom_types.NewEmailAddressValue(alias.Address)
+	}
 	data.Destinations = destinations
 	data.IsInternal = types.BoolValue(alias.IsInternal)
 	data.Expirable = types.BoolValue(alias.Expirable)
@@ -9,10 +9,13 @@ import (
 	"context"
 	"fmt"
 	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -238,13 +241,13 @@ func TestAliasDataSource_Configuration_Errors(t *testing.T) {
 			Configuration: `
 				local_part  = "test"
 			`,
-			ErrorRegex: `The argument "domain_name" is required, but no definition was found`,
+			ErrorRegex: `Attribute "domain_name" must be specified when "local_part" is specified`,
 		},
 		"missing-local-part": {
 			Configuration: `
 				domain_name = "example.com"
 			`,
-			ErrorRegex: `The argument "local_part" is required, but no definition was found`,
+			ErrorRegex: `Attribute "local_part" must be specified when "domain_name" is specified`,
 		},
 		"invalid-domain-name": {
 			Configuration: `
@@ -253,6 +256,30 @@ func TestAliasDataSource_Configuration_Errors(t *testing.T) {
 			`,
 			ErrorRegex: "Domain names must be convertible to ASCII",
 		},
+		"missing-address": {
+			Configuration: ``,
+			ErrorRegex:    `No attribute specified when one \(and only one\) of`,
+		},
+		"address-and-local-part": {
+			Configuration: `
+				address    = "test@example.com"
+				local_part = "test"
+			`,
+			ErrorRegex: `2 attributes specified when one \(and only one\) of`,
+		},
+		"address-and-domain-name": {
+			Configuration: `
+				address     = "test@example.com"
+				domain_name = "example.com"
+			`,
+			ErrorRegex: `Attribute "domain_name" cannot be specified when "address" is specified`,
+		},
+		"invalid-address": {
+			Configuration: `
+				address = "example.com"
+			`,
+			ErrorRegex: "An email must match the format 'local_part@domain'",
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -272,3 +299,24 @@ func TestAliasDataSource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestAliasDataSource_Address(t *testing.T) {
+	server := httptest.NewServer(sandbox.New(sandbox.State{
+		Aliases: []model.Alias{
+			{LocalPart: "info", DomainName: "xn--ho-hia.de", Address: "info@xn--ho-hia.de", Destinations: []string{"other@example.com"}},
+		},
+	}))
+	defer server.Close()
+
+	attributes := readDataSource(t, configuredProviderServerWith(t, server.URL, nil), "migadu_alias", map[string]tftypes.Value{
+		"address": tftypes.NewValue(tftypes.String, "info@hoß.de"),
+	})
+
+	assert.Equal(t, tftypes.NewValue(tftypes.String, "info"), attributes["local_part"], "local_part")
+	assert.Equal(t, tftypes.NewValue(tftypes.String, "hoß.de"), attributes["domain_name"], "domain_name")
+	assert.Equal(t, tftypes.NewValue(tftypes.String, "info@hoß.de"), attributes["id"], "id")
+	assert.Equal(t, tftypes.NewValue(tftypes.String, "info@hoß.de"), attributes["address"], "address")
+	assert.Equal(t, tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "other@example.com"),
+	}), attributes["destinations"], "destinations")
+}
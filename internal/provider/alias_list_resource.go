@@ -0,0 +1,138 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+)
+
+var (
+	_ list.ListResource              = (*AliasListResource)(nil)
+	_ list.ListResourceWithConfigure = (*AliasListResource)(nil)
+)
+
+func NewAliasListResource() list.ListResource {
+	return &AliasListResource{}
+}
+
+type AliasListResource struct {
+	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
+}
+
+type AliasListResourceModel struct {
+	DomainName custom_types.DomainNameValue `tfsdk:"domain_name"`
+}
+
+func (r *AliasListResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_alias"
+}
+
+func (r *AliasListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, response *list.ListResourceSchemaResponse) {
+	response.Schema = schema.Schema{
+		Description:         "Lists all email aliases of a domain.",
+		MarkdownDescription: "Lists all email aliases of a domain.",
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				Description:         "The domain name of the aliases.",
+				MarkdownDescription: "The domain name of the aliases.",
+				Required:            true,
+				Optional:            false,
+				CustomType:          custom_types.DomainNameType{},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *AliasListResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.DomainScope = providerData.DomainScope
+	} else {
+		response.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+	}
+}
+
+func (r *AliasListResource) List(ctx context.Context, request list.ListRequest, stream *list.ListResultsStream) {
+	var config AliasListResourceModel
+	diags := request.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	diags = r.DomainScope.Diagnostics(path.Root("domain_name"), config.DomainName)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	aliases, err := r.MigaduClient.GetAliases(ctx, config.DomainName.ValueString())
+	if err != nil {
+		stream.Results = list.ListResultsStreamDiagnostics(diag.Diagnostics{AliasReadError(err)})
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for _, alias := range aliases.Aliases {
+			result := request.NewListResult(ctx)
+			result.DisplayName = alias.Address
+
+			result.Diagnostics.Append(result.Identity.Set(ctx, AliasResourceIdentityModel{
+				LocalPart:  types.StringValue(alias.LocalPart),
+				DomainName: types.StringValue(config.DomainName.ValueString()),
+			})...)
+
+			if request.IncludeResource {
+				state, diags := newAliasResourceModel(ctx, config.DomainName, alias)
+				result.Diagnostics.Append(diags...)
+				result.Diagnostics.Append(result.Resource.Set(ctx, state)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+func newAliasResourceModel(ctx context.Context, domainName custom_types.DomainNameValue, alias model.Alias) (AliasResourceModel, diag.Diagnostics) {
+	destinations, diags := custom_types.NewEmailAddressSetValueFrom(ctx, alias.Destinations)
+
+	return AliasResourceModel{
+		ID:               custom_types.NewEmailAddressValue(CreateAliasIDString(alias.LocalPart, domainName.ValueString())),
+		LocalPart:        types.StringValue(alias.LocalPart),
+		DomainName:       domainName,
+		Address:          custom_types.NewEmailAddressValue(alias.Address),
+		Destinations:     destinations,
+		IsInternal:       types.BoolValue(alias.IsInternal),
+		Expirable:        types.BoolValue(alias.Expirable),
+		ExpiresOn:        types.StringValue(alias.ExpiresOn),
+		RemoveUponExpiry: types.BoolValue(alias.RemoveUponExpiry),
+	}, diags
+}
@@ -0,0 +1,140 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestAliasListResource_Schema(t *testing.T) {
+	ctx := context.Background()
+	schemaRequest := list.ListResourceSchemaRequest{}
+	schemaResponse := &list.ListResourceSchemaResponse{}
+
+	provider.NewAliasListResource().ListResourceConfigSchema(ctx, schemaRequest, schemaResponse)
+
+	if schemaResponse.Diagnostics.HasError() {
+		t.Fatalf("Schema method diagnostics: %+v", schemaResponse.Diagnostics)
+	}
+
+	diagnostics := schemaResponse.Schema.ValidateImplementation(ctx)
+	if diagnostics.HasError() {
+		t.Fatalf("Schema validation diagnostics: %+v", diagnostics)
+	}
+}
+
+func TestAliasListResource_API_Success(t *testing.T) {
+	testCases := map[string]struct {
+		domain string
+		state  []model.Alias
+		want   []ListedResource
+	}{
+		"empty": {
+			domain: "example.com",
+			state:  []model.Alias{},
+			want:   nil,
+		},
+		"multiple": {
+			domain: "example.com",
+			state: []model.Alias{
+				{
+					LocalPart:    "some",
+					DomainName:   "example.com",
+					Address:      "some@example.com",
+					Destinations: []string{"other@example.com"},
+				},
+				{
+					LocalPart:    "another",
+					DomainName:   "example.com",
+					Address:      "another@example.com",
+					Destinations: []string{"other@example.com", "third@example.com"},
+				},
+				{
+					LocalPart:    "some",
+					DomainName:   "example.org",
+					Address:      "some@example.org",
+					Destinations: []string{"other@example.org"},
+				},
+			},
+			want: []ListedResource{
+				{
+					DisplayName: "some@example.com",
+					ID:          "some@example.com",
+					Identity:    map[string]string{"local_part": "some", "domain_name": "example.com"},
+				},
+				{
+					DisplayName: "another@example.com",
+					ID:          "another@example.com",
+					Identity:    map[string]string{"local_part": "another", "domain_name": "example.com"},
+				},
+			},
+		},
+		"idna": {
+			domain: "hoß.de",
+			state: []model.Alias{
+				{
+					LocalPart:    "some",
+					DomainName:   "xn--ho-hia.de",
+					Address:      "some@xn--ho-hia.de",
+					Destinations: []string{"other@xn--ho-hia.de"},
+				},
+			},
+			want: []ListedResource{
+				{
+					DisplayName: "some@xn--ho-hia.de",
+					ID:          "some@hoß.de",
+					Identity:    map[string]string{"local_part": "some", "domain_name": "hoß.de"},
+				},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{Aliases: testCase.state}))
+			defer server.Close()
+
+			listed, diagnostics := listResources(t, server.URL, "migadu_alias", map[string]string{"domain_name": testCase.domain})
+
+			assertNoDiagnostics(t, diagnostics)
+			assert.ElementsMatch(t, testCase.want, listed, "Listed")
+		})
+	}
+}
+
+func TestAliasListResource_API_Errors(t *testing.T) {
+	testCases := map[string]APIErrorTestCase{
+		"error-404": {
+			StatusCode: http.StatusNotFound,
+			ErrorRegex: "GetAliases: status: 404",
+		},
+		"error-500": {
+			StatusCode: http.StatusInternalServerError,
+			ErrorRegex: "GetAliases: status: 500",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{StatusCode: testCase.StatusCode}))
+			defer server.Close()
+
+			listed, diagnostics := listResources(t, server.URL, "migadu_alias", map[string]string{"domain_name": "example.com"})
+
+			assert.Empty(t, listed, "Listed")
+			if len(diagnostics) != 1 || !regexp.MustCompile(testCase.ErrorRegex).MatchString(diagnostics[0].Detail) {
+				t.Fatalf("Expected error matching %s, got: %+v", testCase.ErrorRegex, diagnostics)
+			}
+		})
+	}
+}
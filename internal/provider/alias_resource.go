@@ -13,11 +13,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/metio/migadu-client.go/client"
 	"github.com/metio/migadu-client.go/model"
@@ -27,9 +29,12 @@ import (
 )
 
 var (
-	_ resource.Resource                = (*AliasResource)(nil)
-	_ resource.ResourceWithConfigure   = (*AliasResource)(nil)
-	_ resource.ResourceWithImportState = (*AliasResource)(nil)
+	_ resource.Resource                 = (*AliasResource)(nil)
+	_ resource.ResourceWithConfigure    = (*AliasResource)(nil)
+	_ resource.ResourceWithModifyPlan   = (*AliasResource)(nil)
+	_ resource.ResourceWithImportState  = (*AliasResource)(nil)
+	_ resource.ResourceWithIdentity     = (*AliasResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*AliasResource)(nil)
 )
 
 func NewAliasResource() resource.Resource {
@@ -38,6 +43,11 @@ func NewAliasResource() resource.Resource {
 
 type AliasResource struct {
 	MigaduClient *client.MigaduClient
+	ReadOnly     bool
+	DomainScope  DomainScope
+	Policy       Policy
+	AuditLog     *AuditLog
+	ReadCache    *ReadCache
 }
 
 type AliasResourceModel struct {
@@ -52,6 +62,11 @@ type AliasResourceModel struct {
 	RemoveUponExpiry types.Bool                        `tfsdk:"remove_upon_expiry"`
 }
 
+type AliasResourceIdentityModel struct {
+	LocalPart  types.String `tfsdk:"local_part"`
+	DomainName types.String `tfsdk:"domain_name"`
+}
+
 func (r *AliasResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
 	response.TypeName = request.ProviderTypeName + "_alias"
 }
@@ -60,6 +75,7 @@ func (r *AliasResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 	response.Schema = schema.Schema{
 		Description:         "Provides an email alias.",
 		MarkdownDescription: "Provides an email alias.",
+		Version:             1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description:         "Contains the value 'local_part@domain_name'.",
@@ -156,21 +172,46 @@ func (r *AliasResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 		},
 	}
 }
+
+func (r *AliasResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, response *resource.IdentitySchemaResponse) {
+	response.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"local_part": identityschema.StringAttribute{
+				Description:       "The local part of the alias.",
+				RequiredForImport: true,
+			},
+			"domain_name": identityschema.StringAttribute{
+				Description:       "The domain name of the alias.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
 func (r *AliasResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if request.ProviderData == nil {
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		r.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.ReadOnly = providerData.ReadOnly
+		r.DomainScope = providerData.DomainScope
+		r.Policy = providerData.Policy
+		r.AuditLog = providerData.AuditLog
+		r.ReadCache = providerData.ReadCache
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
 
+func (r *AliasResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	response.Diagnostics.Append(resourcePlanDiagnostics(ctx, r.ReadOnly, r.DomainScope, r.Policy, "migadu_alias", "Alias", request)...)
+}
+
 func (r *AliasResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 	var plan AliasResourceModel
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
@@ -194,7 +235,9 @@ func (r *AliasResource) Create(ctx context.Context, request resource.CreateReque
 	}
 
 	createdAlias, err := r.MigaduClient.CreateAlias(ctx, plan.DomainName.ValueString(), alias)
+	r.ReadCache.Invalidate(plan.DomainName.ValueString())
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_alias", AuditOperationCreate, CreateAliasID(plan.LocalPart, plan.DomainName), tftypes.Value{}, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(AliasCreateError(err))
 		return
 	}
@@ -206,7 +249,12 @@ func (r *AliasResource) Create(ctx context.Context, request resource.CreateReque
 	plan.ExpiresOn = types.StringValue(createdAlias.ExpiresOn)
 	plan.RemoveUponExpiry = types.BoolValue(createdAlias.RemoveUponExpiry)
 
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_alias", AuditOperationCreate, CreateAliasID(plan.LocalPart, plan.DomainName), tftypes.Value{}, auditState(ctx, request.Plan, plan), nil)...)
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, AliasResourceIdentityModel{
+		LocalPart:  plan.LocalPart,
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+	})...)
 }
 
 func (r *AliasResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -216,7 +264,7 @@ func (r *AliasResource) Read(ctx context.Context, request resource.ReadRequest,
 		return
 	}
 
-	alias, err := r.MigaduClient.GetAlias(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString())
+	alias, err := r.ReadCache.GetAlias(ctx, r.MigaduClient, state.DomainName.ValueString(), state.LocalPart.ValueString())
 	if err != nil {
 		var requestError *client.RequestError
 		if errors.As(err, &requestError) {
@@ -247,6 +295,10 @@ func (r *AliasResource) Read(ctx context.Context, request resource.ReadRequest,
 	state.RemoveUponExpiry = types.BoolValue(alias.RemoveUponExpiry)
 
 	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, AliasResourceIdentityModel{
+		LocalPart:  state.LocalPart,
+		DomainName: types.StringValue(state.DomainName.ValueString()),
+	})...)
 }
 
 func (r *AliasResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
@@ -272,7 +324,9 @@ func (r *AliasResource) Update(ctx context.Context, request resource.UpdateReque
 	}
 
 	updatedAlias, err := r.MigaduClient.UpdateAlias(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), alias)
+	r.ReadCache.Invalidate(plan.DomainName.ValueString())
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_alias", AuditOperationUpdate, CreateAliasID(plan.LocalPart, plan.DomainName), request.State.Raw, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(AliasUpdateError(err))
 		return
 	}
@@ -284,7 +338,12 @@ func (r *AliasResource) Update(ctx context.Context, request resource.UpdateReque
 	plan.ExpiresOn = types.StringValue(updatedAlias.ExpiresOn)
 	plan.RemoveUponExpiry = types.BoolValue(updatedAlias.RemoveUponExpiry)
 
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_alias", AuditOperationUpdate, CreateAliasID(plan.LocalPart, plan.DomainName), request.State.Raw, auditState(ctx, request.Plan, plan), nil)...)
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, AliasResourceIdentityModel{
+		LocalPart:  plan.LocalPart,
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+	})...)
 }
 
 func (r *AliasResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -295,6 +354,8 @@ func (r *AliasResource) Delete(ctx context.Context, request resource.DeleteReque
 	}
 
 	_, err := r.MigaduClient.DeleteAlias(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString())
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_alias", AuditOperationDelete, CreateAliasID(state.LocalPart, state.DomainName), request.State.Raw, tftypes.Value{}, err)...)
+	r.ReadCache.Invalidate(state.DomainName.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(AliasDeleteError(err))
 		return
@@ -302,22 +363,60 @@ func (r *AliasResource) Delete(ctx context.Context, request resource.DeleteReque
 }
 
 func (r *AliasResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
-	idParts := strings.Split(request.ID, "@")
+	var localPart, domainName string
 
-	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-		response.Diagnostics.Append(AliasImportError(request.ID))
-		return
+	if request.ID != "" {
+		idParts := strings.Split(request.ID, "@")
+
+		if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+			response.Diagnostics.Append(AliasImportError(request.ID))
+			return
+		}
+
+		localPart = idParts[0]
+		domainName = idParts[1]
+	} else {
+		var identity AliasResourceIdentityModel
+		response.Diagnostics.Append(request.Identity.Get(ctx, &identity)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		localPart = identity.LocalPart.ValueString()
+		domainName = identity.DomainName.ValueString()
+
+		if localPart == "" || domainName == "" {
+			response.Diagnostics.Append(AliasImportError(CreateAliasIDString(localPart, domainName)))
+			return
+		}
 	}
 
-	localPart := idParts[0]
-	domainName := idParts[1]
 	tflog.Trace(ctx, "parsed import ID", map[string]interface{}{
 		"local_part":  localPart,
 		"domain_name": domainName,
 	})
 
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), request, response)
-
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), CreateAliasIDString(localPart, domainName))...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("local_part"), localPart)...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("domain_name"), domainName)...)
 }
+
+func (r *AliasResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// version 0 covers the schemas before and after the breaking changes of 2023.8.23, see MIGRATIONS.md
+		0: {
+			StateUpgrader: func(ctx context.Context, request resource.UpgradeStateRequest, response *resource.UpgradeStateResponse) {
+				upgradedState, err := convertRawState(ctx, r, request.RawState, []string{
+					"destinations_punycode",
+				}, []string{
+					"destinations",
+				})
+				if err != nil {
+					response.Diagnostics.Append(AliasUpgradeStateError(err))
+					return
+				}
+				response.State.Raw = upgradedState
+			},
+		},
+	}
+}
@@ -6,11 +6,13 @@
 package provider_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -407,3 +409,89 @@ func TestAliasResource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestAliasResource_UpgradeState(t *testing.T) {
+	testCases := map[string]struct {
+		rawState string
+		want     []string
+	}{
+		"before-2023.8.23": {
+			rawState: `{
+				"id": "test@hoß.de",
+				"local_part": "test",
+				"domain_name": "hoß.de",
+				"address": "test@xn--ho-hia.de",
+				"destinations": ["some@hoß.de", "other@example.com", "some@hoß.de"],
+				"destinations_punycode": ["some@xn--ho-hia.de", "other@example.com", "some@xn--ho-hia.de"],
+				"is_internal": false,
+				"expirable": false,
+				"expires_on": "",
+				"remove_upon_expiry": false
+			}`,
+			want: []string{"other@example.com", "some@hoß.de"},
+		},
+		"after-2023.8.23": {
+			rawState: `{
+				"id": "test@example.com",
+				"local_part": "test",
+				"domain_name": "example.com",
+				"address": "test@example.com",
+				"destinations": ["other@example.com"],
+				"is_internal": true,
+				"expirable": false,
+				"expires_on": "",
+				"remove_upon_expiry": false
+			}`,
+			want: []string{"other@example.com"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			state := upgradeState(t, provider.NewAliasResource(), 0, testCase.rawState)
+
+			var alias provider.AliasResourceModel
+			diagnostics := state.Get(ctx, &alias)
+			if diagnostics.HasError() {
+				t.Fatalf("Could not read upgraded state: %+v", diagnostics)
+			}
+
+			var destinations []string
+			diagnostics = alias.Destinations.ElementsAs(ctx, &destinations, false)
+			if diagnostics.HasError() {
+				t.Fatalf("Could not read destinations: %+v", diagnostics)
+			}
+
+			assert.Equal(t, "test", alias.LocalPart.ValueString(), "LocalPart")
+			assert.ElementsMatch(t, testCase.want, destinations, "Destinations")
+		})
+	}
+}
+
+func TestAliasResource_Identity(t *testing.T) {
+	state := &simulator.State{
+		Aliases: []model.Alias{
+			{
+				LocalPart:    "test",
+				DomainName:   "example.com",
+				Address:      "test@example.com",
+				Destinations: []string{"other@example.com"},
+			},
+		},
+	}
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	testResourceIdentity(t, server.URL, ResourceIdentityTestCase{
+		TypeName: "migadu_alias",
+		ImportID: "test@example.com",
+		Identity: map[string]string{
+			"local_part":  "test",
+			"domain_name": "example.com",
+		},
+		ChangedIdentity: map[string]string{
+			"local_part":  "other",
+			"domain_name": "example.com",
+		},
+	})
+}
@@ -11,10 +11,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
 	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"slices"
+	"strings"
 )
 
 var (
@@ -28,12 +31,19 @@ func NewAliasesDataSource() datasource.DataSource {
 
 type AliasesDataSource struct {
 	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
 }
 
 type AliasesDataSourceModel struct {
-	ID         custom_types.DomainNameValue `tfsdk:"id"`
-	DomainName custom_types.DomainNameValue `tfsdk:"domain_name"`
-	Aliases    []AliasModel                 `tfsdk:"aliases"`
+	ID                 custom_types.DomainNameValue   `tfsdk:"id"`
+	DomainName         custom_types.DomainNameValue   `tfsdk:"domain_name"`
+	LocalPartRegex     types.String                   `tfsdk:"local_part_regex"`
+	Destination        custom_types.EmailAddressValue `tfsdk:"destination"`
+	IsInternal         types.Bool                     `tfsdk:"is_internal"`
+	Expirable          types.Bool                     `tfsdk:"expirable"`
+	ExpiresBefore      types.String                   `tfsdk:"expires_before"`
+	Aliases            []AliasModel                   `tfsdk:"aliases"`
+	AliasesByLocalPart map[string]AliasModel          `tfsdk:"aliases_by_local_part"`
 }
 
 type AliasModel struct {
@@ -75,76 +85,25 @@ func (d *AliasesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"local_part_regex": regexFilterAttribute("Only return aliases whose local part matches this regular expression."),
+			"destination":      destinationFilterAttribute("aliases"),
+			"is_internal":      boolFilterAttribute("Only return aliases that are internal or not internal."),
+			"expirable":        boolFilterAttribute("Only return aliases that are expirable or not expirable."),
+			"expires_before":   expiresBeforeFilterAttribute("aliases"),
 			"aliases": schema.ListNestedAttribute{
-				Description:         "The configured aliases for the given 'domain_name'.",
-				MarkdownDescription: "The configured aliases for the given `domain_name`.",
+				Description:         "The configured aliases for the given 'domain_name' sorted by their local part.",
+				MarkdownDescription: "The configured aliases for the given `domain_name` sorted by their local part.",
 				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"local_part": schema.StringAttribute{
-							Description:         "The local part of the alias.",
-							MarkdownDescription: "The local part of the alias.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"domain_name": schema.StringAttribute{
-							Description:         "The domain name of the alias.",
-							MarkdownDescription: "The domain name of the alias.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-							CustomType:          custom_types.DomainNameType{},
-						},
-						"address": schema.StringAttribute{
-							Description:         "The email address 'local_part@domain_name' as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
-							MarkdownDescription: "The email address `local_part@domain_name` as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-							CustomType:          custom_types.EmailAddressType{},
-						},
-						"destinations": schema.SetAttribute{
-							Description:         "List of email addresses that act as destinations of the alias.",
-							MarkdownDescription: "List of email addresses that act as destinations of the alias.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-							CustomType: custom_types.EmailAddressSetType{
-								SetType: types.SetType{
-									ElemType: custom_types.EmailAddressType{},
-								},
-							},
-						},
-						"is_internal": schema.BoolAttribute{
-							Description:         "Whether the alias is internal and can only receive emails from Migadu servers.",
-							MarkdownDescription: "Whether the alias is internal and can only receive emails from Migadu servers.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"expirable": schema.BoolAttribute{
-							Description:         "Whether the alias expires some time in the future.",
-							MarkdownDescription: "Whether the alias expires some time in the future.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"expires_on": schema.StringAttribute{
-							Description:         "The expiration date of the alias.",
-							MarkdownDescription: "The expiration date of the alias.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"remove_upon_expiry": schema.BoolAttribute{
-							Description:         "Whether the alias is removed once it is expired.",
-							MarkdownDescription: "Whether the alias is removed once it is expired.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-					},
+					Attributes: aliasModelAttributes(),
+				},
+			},
+			"aliases_by_local_part": schema.MapNestedAttribute{
+				Description:         "The configured aliases for the given 'domain_name' keyed by their local part.",
+				MarkdownDescription: "The configured aliases for the given `domain_name` keyed by their local part.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: aliasModelAttributes(),
 				},
 			},
 		},
@@ -156,12 +115,13 @@ func (d *AliasesDataSource) Configure(_ context.Context, request datasource.Conf
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.MigaduClient = providerData.MigaduClient
+		d.DomainScope = providerData.DomainScope
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -173,6 +133,18 @@ func (d *AliasesDataSource) Read(ctx context.Context, request datasource.ReadReq
 		return
 	}
 
+	response.Diagnostics.Append(d.DomainScope.Diagnostics(path.Root("domain_name"), data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	localPartRegex := regexFilter(path.Root("local_part_regex"), data.LocalPartRegex, &response.Diagnostics)
+	destination := destinationFilter(path.Root("destination"), data.Destination, &response.Diagnostics)
+	expiresBefore := dateFilter(path.Root("expires_before"), data.ExpiresBefore, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	aliases, err := d.MigaduClient.GetAliases(ctx, data.DomainName.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(AliasReadError(err))
@@ -180,6 +152,14 @@ func (d *AliasesDataSource) Read(ctx context.Context, request datasource.ReadReq
 	}
 
 	for _, alias := range aliases.Aliases {
+		if !matchesRegex(localPartRegex, alias.LocalPart) ||
+			!matchesDestination(destination, alias.Destinations) ||
+			!matchesBool(data.IsInternal, alias.IsInternal) ||
+			!matchesBool(data.Expirable, alias.Expirable) ||
+			!matchesExpiresBefore(expiresBefore, alias.Expirable, alias.ExpiresOn) {
+			continue
+		}
+
 		destinations, diags := custom_types.NewEmailAddressSetValueFrom(ctx, alias.Destinations)
 		response.Diagnostics.Append(diags...)
 		if response.Diagnostics.HasError() {
@@ -200,7 +180,86 @@ func (d *AliasesDataSource) Read(ctx context.Context, request datasource.ReadReq
 		data.Aliases = append(data.Aliases, model)
 	}
 
+	slices.SortFunc(data.Aliases, func(a, b AliasModel) int {
+		return strings.Compare(a.LocalPart.ValueString(), b.LocalPart.ValueString())
+	})
+	if data.Aliases != nil {
+		data.AliasesByLocalPart = make(map[string]AliasModel, len(data.Aliases))
+		for _, alias := range data.Aliases {
+			data.AliasesByLocalPart[alias.LocalPart.ValueString()] = alias
+		}
+	}
+
 	data.ID = data.DomainName
 
 	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
 }
+
+// aliasModelAttributes returns the computed attributes of a single alias as used by the data sources.
+func aliasModelAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"local_part": schema.StringAttribute{
+			Description:         "The local part of the alias.",
+			MarkdownDescription: "The local part of the alias.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"domain_name": schema.StringAttribute{
+			Description:         "The domain name of the alias.",
+			MarkdownDescription: "The domain name of the alias.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+			CustomType:          custom_types.DomainNameType{},
+		},
+		"address": schema.StringAttribute{
+			Description:         "The email address 'local_part@domain_name' as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+			MarkdownDescription: "The email address `local_part@domain_name` as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+			CustomType:          custom_types.EmailAddressType{},
+		},
+		"destinations": schema.SetAttribute{
+			Description:         "List of email addresses that act as destinations of the alias.",
+			MarkdownDescription: "List of email addresses that act as destinations of the alias.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+			CustomType: custom_types.EmailAddressSetType{
+				SetType: types.SetType{
+					ElemType: custom_types.EmailAddressType{},
+				},
+			},
+		},
+		"is_internal": schema.BoolAttribute{
+			Description:         "Whether the alias is internal and can only receive emails from Migadu servers.",
+			MarkdownDescription: "Whether the alias is internal and can only receive emails from Migadu servers.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"expirable": schema.BoolAttribute{
+			Description:         "Whether the alias expires some time in the future.",
+			MarkdownDescription: "Whether the alias expires some time in the future.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"expires_on": schema.StringAttribute{
+			Description:         "The expiration date of the alias.",
+			MarkdownDescription: "The expiration date of the alias.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"remove_upon_expiry": schema.BoolAttribute{
+			Description:         "Whether the alias is removed once it is expired.",
+			MarkdownDescription: "Whether the alias is removed once it is expired.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+	}
+}
@@ -9,10 +9,13 @@ import (
 	"context"
 	"fmt"
 	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -98,20 +101,20 @@ func TestAliasesDataSource_API_Success(t *testing.T) {
 			want: model.Aliases{
 				Aliases: []model.Alias{
 					{
-						LocalPart:        "some",
+						LocalPart:        "other",
 						DomainName:       "example.com",
 						Address:          "some@example.com",
-						Destinations:     []string{"other@example"},
+						Destinations:     []string{"another@example"},
 						IsInternal:       true,
 						Expirable:        false,
 						ExpiresOn:        "",
 						RemoveUponExpiry: false,
 					},
 					{
-						LocalPart:        "other",
+						LocalPart:        "some",
 						DomainName:       "example.com",
 						Address:          "some@example.com",
-						Destinations:     []string{"another@example"},
+						Destinations:     []string{"other@example"},
 						IsInternal:       true,
 						Expirable:        false,
 						ExpiresOn:        "",
@@ -294,3 +297,25 @@ func TestAliasesDataSource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestAliasesDataSource_Keyed(t *testing.T) {
+	server := httptest.NewServer(sandbox.New(sandbox.State{
+		Aliases: []model.Alias{
+			{LocalPart: "support", DomainName: "example.com", Address: "support@example.com"},
+			{LocalPart: "billing", DomainName: "example.com", Address: "billing@example.com"},
+			{LocalPart: "sales", DomainName: "example.com", Address: "sales@example.com"},
+		},
+	}))
+	defer server.Close()
+
+	attributes := readDataSource(t, configuredProviderServerWith(t, server.URL, nil), "migadu_aliases", map[string]tftypes.Value{
+		"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+	})
+
+	assert.Equal(t, []string{"billing", "sales", "support"}, nestedStrings(t, attributes["aliases"], "local_part"), "aliases")
+	assert.Equal(t, map[string]string{
+		"billing": "billing@example.com",
+		"sales":   "sales@example.com",
+		"support": "support@example.com",
+	}, nestedStringsByKey(t, attributes["aliases_by_local_part"], "address"), "aliases_by_local_part")
+}
@@ -0,0 +1,202 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/client"
+	"math/big"
+	"net/http"
+	"os"
+	"os/user"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	AuditOperationCreate = "create"
+	AuditOperationUpdate = "update"
+	AuditOperationDelete = "delete"
+)
+
+// auditRedactedAttributes contains the names of all attributes whose values are masked in the audit log. Nested
+// attributes with these names are masked as well.
+var auditRedactedAttributes = []string{
+	"password",
+	"footer_plain_body",
+	"footer_html_body",
+}
+
+// AuditLog appends a JSON line for each mutating API call to a local file. Its methods can be called on a nil
+// AuditLog in which case nothing is recorded.
+type AuditLog struct {
+	path  string
+	user  string
+	mutex sync.Mutex
+}
+
+// AuditEntry is a single line of the audit log.
+type AuditEntry struct {
+	Timestamp    time.Time      `json:"timestamp"`
+	User         string         `json:"user"`
+	ResourceType string         `json:"resource_type"`
+	ID           string         `json:"id"`
+	Operation    string         `json:"operation"`
+	Before       map[string]any `json:"before,omitempty"`
+	After        map[string]any `json:"after,omitempty"`
+	StatusCode   int            `json:"status_code"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// NewAuditLog creates an audit log that appends to the file at the given path. The file is created if it does not
+// exist yet.
+func NewAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err = file.Close(); err != nil {
+		return nil, err
+	}
+
+	name := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+
+	return &AuditLog{path: path, user: name}, nil
+}
+
+// Record appends an entry for the given API call. The before and after values are the raw prior and applied state of
+// the resource and can be null. Since the API call already happened, failures to write the entry are returned as
+// warnings.
+func (l *AuditLog) Record(resourceType, operation, id string, before, after tftypes.Value, err error) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	if l == nil {
+		return diagnostics
+	}
+
+	entry := AuditEntry{
+		Timestamp:    time.Now().UTC(),
+		User:         l.user,
+		ResourceType: resourceType,
+		ID:           id,
+		Operation:    operation,
+		Before:       auditValues(before),
+		After:        auditValues(after),
+		StatusCode:   auditStatusCode(err),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if writeErr := l.write(entry); writeErr != nil {
+		diagnostics.AddWarning(
+			"Unable to Write Audit Log",
+			"The "+operation+" of '"+id+"' was sent to the API but could not be recorded in the audit log at '"+l.path+"'.\n\n"+
+				"Error: "+writeErr.Error(),
+		)
+	}
+	return diagnostics
+}
+
+// auditState converts the given resource model into a raw state, so that audit entries contain the values returned
+// by the API instead of a plan that can still contain unknown values.
+func auditState(ctx context.Context, plan tfsdk.Plan, model any) tftypes.Value {
+	state := tfsdk.State{Schema: plan.Schema}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		return tftypes.Value{}
+	}
+	return state.Raw
+}
+
+func (l *AuditLog) write(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	return errors.Join(err, file.Close())
+}
+
+// auditStatusCode returns the HTTP status code of an API call. The client only returns successfully for status code
+// 200, and calls that failed before receiving a response have status code 0.
+func auditStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var requestError *client.RequestError
+	if errors.As(err, &requestError) {
+		return requestError.StatusCode
+	}
+	return 0
+}
+
+// auditValues converts the top-level attributes of a raw state or plan into redacted JSON values.
+func auditValues(value tftypes.Value) map[string]any {
+	if value.Type() == nil || value.IsNull() || !value.IsKnown() {
+		return nil
+	}
+	values, _ := auditValue(value).(map[string]any)
+	return values
+}
+
+func auditValue(value tftypes.Value) any {
+	if value.IsNull() || !value.IsKnown() {
+		return nil
+	}
+
+	switch {
+	case value.Type().Is(tftypes.String):
+		var str string
+		_ = value.As(&str)
+		return str
+	case value.Type().Is(tftypes.Bool):
+		var boolean bool
+		_ = value.As(&boolean)
+		return boolean
+	case value.Type().Is(tftypes.Number):
+		number := new(big.Float)
+		_ = value.As(&number)
+		return json.Number(number.Text('f', -1))
+	case value.Type().Is(tftypes.Set{}), value.Type().Is(tftypes.List{}):
+		var elements []tftypes.Value
+		_ = value.As(&elements)
+		values := make([]any, 0, len(elements))
+		for _, element := range elements {
+			values = append(values, auditValue(element))
+		}
+		return values
+	case value.Type().Is(tftypes.Map{}), value.Type().Is(tftypes.Object{}):
+		var attributes map[string]tftypes.Value
+		_ = value.As(&attributes)
+		values := make(map[string]any, len(attributes))
+		for name, attribute := range attributes {
+			if slices.Contains(auditRedactedAttributes, name) && value.Type().Is(tftypes.Object{}) && !attribute.IsNull() && attribute.IsKnown() {
+				values[name] = "***"
+			} else {
+				values[name] = auditValue(attribute)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
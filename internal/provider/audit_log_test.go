@@ -0,0 +1,242 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestAuditLog_Apply(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{}))
+	defer server.Close()
+
+	auditLogPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	providerServer := configuredProviderServerWith(t, server.URL, map[string]tftypes.Value{
+		"audit_log_path": tftypes.NewValue(tftypes.String, auditLogPath),
+	})
+	schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+
+	resourceType := schemaResponse.ResourceSchemas["migadu_alias"].ValueType()
+	prior := dynamicValue(t, resourceType, tftypes.NewValue(resourceType, nil))
+	config := dynamicValue(t, resourceType, objectValue(resourceType, map[string]tftypes.Value{
+		"local_part":   tftypes.NewValue(tftypes.String, "some"),
+		"domain_name":  tftypes.NewValue(tftypes.String, "example.com"),
+		"destinations": stringSet([]string{"other@example.com"}),
+	}))
+	plan, err := providerServer.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "migadu_alias",
+		PriorState:       prior,
+		ProposedNewState: config,
+		Config:           config,
+	})
+	if err != nil {
+		t.Fatalf("PlanResourceChange error: %s", err)
+	}
+	assertNoDiagnostics(t, plan.Diagnostics)
+
+	apply, err := providerServer.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     "migadu_alias",
+		PriorState:   prior,
+		PlannedState: plan.PlannedState,
+		Config:       config,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange error: %s", err)
+	}
+	assertNoDiagnostics(t, apply.Diagnostics)
+
+	entries := readAuditLog(t, auditLogPath)
+	if assert.Len(t, entries, 1, "entries") {
+		assert.Equal(t, "migadu_alias", entries[0].ResourceType, "resource_type")
+		assert.Equal(t, "some@example.com", entries[0].ID, "id")
+		assert.Equal(t, provider.AuditOperationCreate, entries[0].Operation, "operation")
+		assert.Equal(t, 200, entries[0].StatusCode, "status_code")
+		assert.Nil(t, entries[0].Before, "before")
+		assert.Equal(t, "some", entries[0].After["local_part"], "after.local_part")
+		assert.Equal(t, "some@example.com", entries[0].After["id"], "after.id")
+		assert.Equal(t, []any{"other@example.com"}, entries[0].After["destinations"], "after.destinations")
+	}
+}
+
+func TestAuditLog_Apply_Mailbox(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(sandbox.New(sandbox.State{}))
+	defer server.Close()
+
+	auditLogPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	providerServer := configuredProviderServerWith(t, server.URL, map[string]tftypes.Value{
+		"audit_log_path": tftypes.NewValue(tftypes.String, auditLogPath),
+	})
+	schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+
+	resourceType := schemaResponse.ResourceSchemas["migadu_mailbox"].ValueType()
+	identitiesType := resourceType.(tftypes.Object).AttributeTypes["identities"].(tftypes.Map)
+	prior := dynamicValue(t, resourceType, tftypes.NewValue(resourceType, nil))
+	config := dynamicValue(t, resourceType, objectValue(resourceType, map[string]tftypes.Value{
+		"local_part":  tftypes.NewValue(tftypes.String, "some"),
+		"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+		"name":        tftypes.NewValue(tftypes.String, "Some Name"),
+		"password":    tftypes.NewValue(tftypes.String, "secret"),
+		"identities": tftypes.NewValue(identitiesType, map[string]tftypes.Value{
+			"work": objectValue(identitiesType.ElementType, map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "Work Name"),
+			}),
+		}),
+		"aliases": stringSet([]string{"sales"}),
+	}))
+	plan, err := providerServer.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "migadu_mailbox",
+		PriorState:       prior,
+		ProposedNewState: config,
+		Config:           config,
+	})
+	if err != nil {
+		t.Fatalf("PlanResourceChange error: %s", err)
+	}
+	assertNoDiagnostics(t, plan.Diagnostics)
+
+	apply, err := providerServer.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     "migadu_mailbox",
+		PriorState:   prior,
+		PlannedState: plan.PlannedState,
+		Config:       config,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange error: %s", err)
+	}
+	assertNoDiagnostics(t, apply.Diagnostics)
+
+	entries := readAuditLog(t, auditLogPath)
+	if assert.Len(t, entries, 3, "entries") {
+		assert.Equal(t, "migadu_mailbox", entries[0].ResourceType, "resource_type")
+		assert.Equal(t, "some@example.com", entries[0].ID, "id")
+		assert.Equal(t, "some@example.com", entries[0].After["address"], "after.address")
+		assert.Equal(t, "***", entries[0].After["password"], "after.password")
+		assert.Nil(t, entries[0].After["identities"], "after.identities")
+
+		assert.Equal(t, "migadu_identity", entries[1].ResourceType, "resource_type")
+		assert.Equal(t, "some@example.com/work", entries[1].ID, "id")
+		assert.Equal(t, provider.AuditOperationCreate, entries[1].Operation, "operation")
+		assert.Equal(t, "work@example.com", entries[1].After["address"], "after.address")
+		assert.Equal(t, "Work Name", entries[1].After["name"], "after.name")
+
+		assert.Equal(t, "migadu_alias", entries[2].ResourceType, "resource_type")
+		assert.Equal(t, "sales@example.com", entries[2].ID, "id")
+		assert.Equal(t, provider.AuditOperationCreate, entries[2].Operation, "operation")
+		assert.Equal(t, []any{"some@example.com"}, entries[2].After["destinations"], "after.destinations")
+	}
+}
+
+func TestAuditLog_Record(t *testing.T) {
+	identityType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":     tftypes.String,
+		"password": tftypes.String,
+	}}
+	mailboxType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"local_part":        tftypes.String,
+		"password":          tftypes.String,
+		"footer_plain_body": tftypes.String,
+		"footer_html_body":  tftypes.String,
+		"identities":        tftypes.Map{ElementType: identityType},
+	}}
+	mailbox := tftypes.NewValue(mailboxType, map[string]tftypes.Value{
+		"local_part":        tftypes.NewValue(tftypes.String, "some"),
+		"password":          tftypes.NewValue(tftypes.String, "secret"),
+		"footer_plain_body": tftypes.NewValue(tftypes.String, "footer"),
+		"footer_html_body":  tftypes.NewValue(tftypes.String, nil),
+		"identities": tftypes.NewValue(tftypes.Map{ElementType: identityType}, map[string]tftypes.Value{
+			"password": tftypes.NewValue(identityType, map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, "Other"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			}),
+		}),
+	})
+
+	auditLogPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := provider.NewAuditLog(auditLogPath)
+	if err != nil {
+		t.Fatalf("NewAuditLog error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			diagnostics := auditLog.Record("migadu_mailbox", provider.AuditOperationUpdate, "some@example.com", mailbox, mailbox, &client.RequestError{StatusCode: 409})
+			assert.Empty(t, diagnostics, "diagnostics")
+		}()
+	}
+	wg.Wait()
+
+	entries := readAuditLog(t, auditLogPath)
+	assert.Len(t, entries, 50, "entries")
+	for _, entry := range entries {
+		assert.Equal(t, 409, entry.StatusCode, "status_code")
+		assert.Equal(t, map[string]any{
+			"local_part":        "some",
+			"password":          "***",
+			"footer_plain_body": "***",
+			"footer_html_body":  nil,
+			"identities": map[string]any{
+				"password": map[string]any{
+					"name":     "Other",
+					"password": "***",
+				},
+			},
+		}, entry.After, "after")
+	}
+
+	diagnostics := (*provider.AuditLog)(nil).Record("migadu_mailbox", provider.AuditOperationDelete, "some@example.com", mailbox, tftypes.Value{}, errors.New("unreachable"))
+	assert.Empty(t, diagnostics, "nil audit log")
+}
+
+func TestAuditLog_InvalidPath(t *testing.T) {
+	_, err := provider.NewAuditLog(filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+
+	assert.Error(t, err)
+}
+
+func readAuditLog(t *testing.T, path string) []provider.AuditEntry {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Could not open audit log: %s", err)
+	}
+	defer file.Close()
+
+	var entries []provider.AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry provider.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid audit log line %q: %s", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
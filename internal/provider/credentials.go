@@ -0,0 +1,307 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/metio/migadu-client.go/client"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultProfile is the profile of the shared credentials file that is used in case no other source provides
+// credentials.
+const DefaultProfile = "default"
+
+// Credentials are used to authenticate against the Migadu API.
+type Credentials struct {
+	Username string
+	Token    string
+}
+
+// CredentialSources contains all sources of credentials on a single level, e.g. the provider configuration or the
+// environment variables.
+type CredentialSources struct {
+	Username     string
+	Token        string
+	TokenFile    string
+	TokenCommand string
+	Profile      string
+}
+
+// DefaultCredentialsFile returns the path of the shared credentials file, ~/.config/migadu/credentials.
+func DefaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "migadu", "credentials")
+}
+
+// ResolveCredentials returns the first username and the first token provided by the given sources. Within each
+// source, the username and token are used before the profile, and the token is used before the token file and the
+// token command. The token command is only executed in case no earlier source provided a token. The default profile
+// of the shared credentials file is used as the last source if the file exists.
+func ResolveCredentials(ctx context.Context, credentialsFile string, sources ...CredentialSources) (Credentials, error) {
+	credentials := Credentials{}
+	for _, source := range sources {
+		if credentials.Username == "" {
+			credentials.Username = source.Username
+		}
+
+		if credentials.Token == "" {
+			token, err := source.token(ctx)
+			if err != nil {
+				return credentials, err
+			}
+			credentials.Token = token
+		}
+
+		if source.Profile != "" && (credentials.Username == "" || credentials.Token == "") {
+			profile, err := ReadCredentialsProfile(credentialsFile, source.Profile)
+			if err != nil {
+				return credentials, err
+			}
+			credentials.merge(profile)
+		}
+	}
+
+	if credentials.Username == "" || credentials.Token == "" {
+		profile, err := ReadCredentialsProfile(credentialsFile, DefaultProfile)
+		if err == nil {
+			credentials.merge(profile)
+		} else if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, errProfileNotFound) {
+			return credentials, err
+		}
+	}
+
+	return credentials, nil
+}
+
+func (c *Credentials) merge(other Credentials) {
+	if c.Username == "" {
+		c.Username = other.Username
+	}
+	if c.Token == "" {
+		c.Token = other.Token
+	}
+}
+
+func (s CredentialSources) token(ctx context.Context) (string, error) {
+	switch {
+	case s.Token != "":
+		return s.Token, nil
+	case s.TokenFile != "":
+		content, err := os.ReadFile(s.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("cannot read token file: %w", err)
+		}
+		return strings.TrimSpace(string(content)), nil
+	case s.TokenCommand != "":
+		return runTokenCommand(ctx, s.TokenCommand)
+	default:
+		return "", nil
+	}
+}
+
+// runTokenCommand executes the given command with the shell of the operating system and returns its trimmed output.
+func runTokenCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+var errProfileNotFound = errors.New("profile not found")
+
+// ReadCredentialsProfile reads a single profile from a shared credentials file. The file contains one section per
+// profile with 'username' and 'token' keys, e.g.:
+//
+//	[customer-a]
+//	username = admin@example.com
+//	token    = secret
+//
+// Lines starting with '#' or ';' are comments.
+func ReadCredentialsProfile(credentialsFile string, profile string) (Credentials, error) {
+	credentials := Credentials{}
+	if credentialsFile == "" {
+		return credentials, fmt.Errorf("cannot read profile '%s': %w", profile, fs.ErrNotExist)
+	}
+
+	content, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return credentials, fmt.Errorf("cannot read credentials file: %w", err)
+	}
+
+	found := false
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			found = found || section == profile
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return credentials, fmt.Errorf("invalid line %d in credentials file '%s': expected 'key = value'", number, credentialsFile)
+		}
+		if section != profile {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "username":
+			credentials.Username = strings.TrimSpace(value)
+		case "token":
+			credentials.Token = strings.TrimSpace(value)
+		}
+	}
+
+	if !found {
+		return credentials, fmt.Errorf("cannot find profile '%s' in credentials file '%s': %w", profile, credentialsFile, errProfileNotFound)
+	}
+	return credentials, nil
+}
+
+// ValidateEndpoint returns an error in case the given endpoint is not a well-formed https URL. Loopback hosts can use
+// http as well, e.g. for local proxies and test servers.
+func ValidateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if !parsed.IsAbs() || parsed.Host == "" {
+		return fmt.Errorf("'%s' is not an absolute URL", endpoint)
+	}
+	if parsed.Scheme == "https" {
+		return nil
+	}
+	if parsed.Scheme == "http" && isLoopbackHost(parsed.Hostname()) {
+		return nil
+	}
+	return fmt.Errorf("'%s' does not use https", endpoint)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ValidateCredentials makes a single authenticated call to the Migadu API and returns an error that points to the
+// username, token, or endpoint in case the call does not return the domains of the user.
+func ValidateCredentials(ctx context.Context, migaduClient *client.MigaduClient) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if address, err := mail.ParseAddress(migaduClient.Username); err != nil || address.Address != migaduClient.Username {
+		diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Invalid Migadu API Username",
+			"The Migadu API username must be the email address you use to sign in to the Migadu admin panel.",
+		)
+		return diagnostics
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(migaduClient.Endpoint, "/")+"/domains", http.NoBody)
+	if err != nil {
+		diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Invalid Migadu API Endpoint",
+			"The provider cannot create a request for the Migadu API endpoint: "+err.Error(),
+		)
+		return diagnostics
+	}
+	request.SetBasicAuth(migaduClient.Username, migaduClient.Token)
+
+	response, err := migaduClient.HTTPClient.Do(request)
+	if err != nil {
+		diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unreachable Migadu API Endpoint",
+			"The provider cannot reach the Migadu API endpoint '"+migaduClient.Endpoint+"'. "+
+				"Check the endpoint, your network connection, and any proxies in between.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return diagnostics
+	}
+	var body struct {
+		Domains *json.RawMessage `json:"domains"`
+	}
+	decodeErr := json.NewDecoder(response.Body).Decode(&body)
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusUnauthorized:
+		diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Invalid Migadu API Credentials",
+			"The Migadu API rejected the token for the username '"+migaduClient.Username+"'. "+
+				"The API does not tell whether the username or the token is wrong. "+
+				"Check that the username is the email address you use to sign in to the Migadu admin panel "+
+				"and that the token is an active API key created by this user.",
+		)
+	case response.StatusCode == http.StatusForbidden:
+		diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Insufficient Migadu API Token",
+			"The Migadu API accepted the credentials of the username '"+migaduClient.Username+"' but refused to list its domains. "+
+				"Check that the token is an active API key that is allowed to access the domains of this user.",
+		)
+	case response.StatusCode >= http.StatusInternalServerError:
+		diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unreachable Migadu API Endpoint",
+			fmt.Sprintf("The Migadu API endpoint '%s' responded with status code %d. Try again later or check the endpoint.", migaduClient.Endpoint, response.StatusCode),
+		)
+	case response.StatusCode < 200 || response.StatusCode > 299:
+		diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unexpected Migadu API Response",
+			fmt.Sprintf("The endpoint '%s' responded with status code %d while listing domains. Check that the endpoint points to the Migadu API.", migaduClient.Endpoint, response.StatusCode),
+		)
+	case decodeErr != nil || body.Domains == nil:
+		diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unexpected Migadu API Response",
+			fmt.Sprintf("The endpoint '%s' did not respond with a list of domains. Check that the endpoint points to the Migadu API.", migaduClient.Endpoint),
+		)
+	}
+	return diagnostics
+}
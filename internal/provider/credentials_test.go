@@ -0,0 +1,374 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const credentialsFileContent = `
+# shared credentials
+[default]
+username = default@example.com
+token    = default-token
+
+[customer-a]
+username = a@example.com
+token = a-token
+
+; profiles can omit keys
+[username-only]
+username = only@example.com
+`
+
+func TestResolveCredentials(t *testing.T) {
+	directory := t.TempDir()
+	credentialsFile := writeFile(t, directory, "credentials", credentialsFileContent)
+	tokenFile := writeFile(t, directory, "token", "  file-token\n")
+
+	testCases := map[string]struct {
+		credentialsFile string
+		sources         []provider.CredentialSources
+		want            provider.Credentials
+		wantErr         bool
+	}{
+		"configuration-before-environment": {
+			sources: []provider.CredentialSources{
+				{Username: "config@example.com", Token: "config-token"},
+				{Username: "env@example.com", Token: "env-token"},
+			},
+			want: provider.Credentials{Username: "config@example.com", Token: "config-token"},
+		},
+		"environment": {
+			sources: []provider.CredentialSources{
+				{},
+				{Username: "env@example.com", Token: "env-token"},
+			},
+			want: provider.Credentials{Username: "env@example.com", Token: "env-token"},
+		},
+		"token-file": {
+			sources: []provider.CredentialSources{
+				{Username: "config@example.com", TokenFile: tokenFile},
+				{Token: "env-token"},
+			},
+			want: provider.Credentials{Username: "config@example.com", Token: "file-token"},
+		},
+		"token-before-token-file": {
+			sources: []provider.CredentialSources{
+				{Username: "config@example.com", Token: "config-token", TokenFile: filepath.Join(directory, "missing")},
+			},
+			want: provider.Credentials{Username: "config@example.com", Token: "config-token"},
+		},
+		"token-command": {
+			sources: []provider.CredentialSources{
+				{Username: "config@example.com", TokenCommand: "echo command-token"},
+			},
+			want: provider.Credentials{Username: "config@example.com", Token: "command-token"},
+		},
+		"token-command-not-executed": {
+			sources: []provider.CredentialSources{
+				{Username: "config@example.com", Token: "config-token"},
+				{TokenCommand: "exit 1"},
+			},
+			want: provider.Credentials{Username: "config@example.com", Token: "config-token"},
+		},
+		"configuration-profile-before-environment": {
+			credentialsFile: credentialsFile,
+			sources: []provider.CredentialSources{
+				{Profile: "customer-a"},
+				{Username: "env@example.com", Token: "env-token"},
+			},
+			want: provider.Credentials{Username: "a@example.com", Token: "a-token"},
+		},
+		"environment-profile": {
+			credentialsFile: credentialsFile,
+			sources: []provider.CredentialSources{
+				{Username: "config@example.com"},
+				{Profile: "customer-a"},
+			},
+			want: provider.Credentials{Username: "config@example.com", Token: "a-token"},
+		},
+		"partial-profile": {
+			credentialsFile: credentialsFile,
+			sources: []provider.CredentialSources{
+				{Profile: "username-only"},
+				{Token: "env-token"},
+			},
+			want: provider.Credentials{Username: "only@example.com", Token: "env-token"},
+		},
+		"default-profile": {
+			credentialsFile: credentialsFile,
+			sources: []provider.CredentialSources{
+				{},
+				{},
+			},
+			want: provider.Credentials{Username: "default@example.com", Token: "default-token"},
+		},
+		"missing-credentials-file": {
+			credentialsFile: filepath.Join(directory, "missing"),
+			sources: []provider.CredentialSources{
+				{Username: "config@example.com"},
+			},
+			want: provider.Credentials{Username: "config@example.com"},
+		},
+		"missing-profile": {
+			credentialsFile: credentialsFile,
+			sources: []provider.CredentialSources{
+				{Profile: "customer-b"},
+			},
+			wantErr: true,
+		},
+		"missing-profile-credentials-file": {
+			credentialsFile: filepath.Join(directory, "missing"),
+			sources: []provider.CredentialSources{
+				{Profile: "customer-a"},
+			},
+			wantErr: true,
+		},
+		"missing-token-file": {
+			sources: []provider.CredentialSources{
+				{TokenFile: filepath.Join(directory, "missing")},
+			},
+			wantErr: true,
+		},
+		"failing-token-command": {
+			sources: []provider.CredentialSources{
+				{TokenCommand: "echo broken >&2; exit 3"},
+			},
+			wantErr: true,
+		},
+		"invalid-credentials-file": {
+			credentialsFile: writeFile(t, directory, "invalid", "[default]\nusername\n"),
+			sources: []provider.CredentialSources{
+				{},
+			},
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := provider.ResolveCredentials(context.Background(), testCase.credentialsFile, testCase.sources...)
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			assert.Equal(t, testCase.want, got, "credentials")
+		})
+	}
+}
+
+func TestResolveCredentials_Provider(t *testing.T) {
+	ctx := context.Background()
+	api := simulator.MigaduAPI(t, &simulator.State{})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		username, token, _ := request.BasicAuth()
+		if username != "a@example.com" || token != "file-token" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		api.ServeHTTP(writer, request)
+	}))
+	defer server.Close()
+
+	t.Setenv("MIGADU_USERNAME", "env@example.com")
+	t.Setenv("MIGADU_TOKEN", "env-token")
+	directory := t.TempDir()
+
+	providerServer := configuredProviderServerWith(t, server.URL, map[string]tftypes.Value{
+		"username":         tftypes.NewValue(tftypes.String, nil),
+		"token":            tftypes.NewValue(tftypes.String, nil),
+		"profile":          tftypes.NewValue(tftypes.String, "customer-a"),
+		"credentials_file": tftypes.NewValue(tftypes.String, writeFile(t, directory, "credentials", credentialsFileContent)),
+		"token_file":       tftypes.NewValue(tftypes.String, writeFile(t, directory, "token", "file-token")),
+	})
+	schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+
+	dataSourceType := schemaResponse.DataSourceSchemas["migadu_aliases"].ValueType()
+	response, err := providerServer.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "migadu_aliases",
+		Config: dynamicValue(t, dataSourceType, objectValue(dataSourceType, map[string]tftypes.Value{
+			"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+		})),
+	})
+	if err != nil {
+		t.Fatalf("ReadDataSource error: %s", err)
+	}
+	assertNoDiagnostics(t, response.Diagnostics)
+}
+
+func TestValidateCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		username, token, _ := request.BasicAuth()
+		switch {
+		case request.URL.Path == "/other/domains":
+			_, _ = writer.Write([]byte(`{"status":"ok"}`))
+		case request.URL.Path != "/domains":
+			writer.WriteHeader(http.StatusNotFound)
+		case username == "admin@example.com" && token == "broken":
+			writer.WriteHeader(http.StatusBadGateway)
+		case username == "admin@example.com" && token == "restricted":
+			writer.WriteHeader(http.StatusForbidden)
+		case username != "admin@example.com" || token != "token":
+			writer.WriteHeader(http.StatusUnauthorized)
+		default:
+			_, _ = writer.Write([]byte(`{"domains":[]}`))
+		}
+	}))
+	defer server.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	testCases := map[string]struct {
+		endpoint string
+		username string
+		token    string
+		validate bool
+		want     string
+		path     string
+		detail   string
+	}{
+		"valid": {
+			endpoint: server.URL,
+			username: "admin@example.com",
+			token:    "token",
+			validate: true,
+		},
+		"not-validated": {
+			endpoint: server.URL,
+			username: "username",
+			token:    "wrong",
+			validate: false,
+		},
+		"wrong-username": {
+			endpoint: server.URL,
+			username: "username",
+			token:    "token",
+			validate: true,
+			want:     "Invalid Migadu API Username",
+			path:     "username",
+		},
+		"wrong-token": {
+			endpoint: server.URL,
+			username: "admin@example.com",
+			token:    "wrong",
+			validate: true,
+			want:     "Invalid Migadu API Credentials",
+			path:     "token",
+			detail:   "'admin@example.com'",
+		},
+		"other-username": {
+			endpoint: server.URL,
+			username: "other@example.com",
+			token:    "token",
+			validate: true,
+			want:     "Invalid Migadu API Credentials",
+			path:     "token",
+			detail:   "'other@example.com'",
+		},
+		"forbidden-token": {
+			endpoint: server.URL,
+			username: "admin@example.com",
+			token:    "restricted",
+			validate: true,
+			want:     "Insufficient Migadu API Token",
+			path:     "token",
+		},
+		"missing-endpoint": {
+			endpoint: server.URL + "/missing/",
+			username: "admin@example.com",
+			token:    "token",
+			validate: true,
+			want:     "Unexpected Migadu API Response",
+			path:     "endpoint",
+		},
+		"other-endpoint": {
+			endpoint: server.URL + "/other/",
+			username: "admin@example.com",
+			token:    "token",
+			validate: true,
+			want:     "Unexpected Migadu API Response",
+			path:     "endpoint",
+		},
+		"unreachable-endpoint": {
+			endpoint: unreachable.URL,
+			username: "admin@example.com",
+			token:    "token",
+			validate: true,
+			want:     "Unreachable Migadu API Endpoint",
+			path:     "endpoint",
+		},
+		"failing-endpoint": {
+			endpoint: server.URL,
+			username: "admin@example.com",
+			token:    "broken",
+			validate: true,
+			want:     "Unreachable Migadu API Endpoint",
+			path:     "endpoint",
+		},
+		"insecure-endpoint": {
+			endpoint: "http://api.migadu.com/v1/",
+			username: "admin@example.com",
+			token:    "token",
+			validate: true,
+			want:     "Invalid Migadu API Endpoint",
+			path:     "endpoint",
+		},
+		"relative-endpoint": {
+			endpoint: "api.migadu.com/v1/",
+			username: "admin@example.com",
+			token:    "token",
+			validate: true,
+			want:     "Invalid Migadu API Endpoint",
+			path:     "endpoint",
+		},
+		"sandbox": {
+			endpoint: "memory://validate-credentials",
+			username: "username",
+			token:    "token",
+			validate: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diagnostics := configureProviderServer(t, testCase.endpoint, map[string]tftypes.Value{
+				"username":             tftypes.NewValue(tftypes.String, testCase.username),
+				"token":                tftypes.NewValue(tftypes.String, testCase.token),
+				"validate_credentials": tftypes.NewValue(tftypes.Bool, testCase.validate),
+			})
+
+			assertDiagnosticSummary(t, testCase.want, diagnostics)
+			if testCase.want != "" && len(diagnostics) == 1 {
+				assert.Equal(t, tftypes.NewAttributePath().WithAttributeName(testCase.path), diagnostics[0].Attribute, "attribute")
+				assert.Contains(t, diagnostics[0].Detail, testCase.detail, "detail")
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, directory, name, content string) string {
+	file := filepath.Join(directory, name)
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("Could not write %s: %s", name, err)
+	}
+	return file
+}
@@ -110,3 +110,9 @@ func (v DomainNameValue) ValidateAttribute(_ context.Context, request xattr.Vali
 		return
 	}
 }
+
+// NormalizedValue returns the lower-case ASCII form of the domain name which is equal for all semantically equal
+// values.
+func (v DomainNameValue) NormalizedValue() (string, error) {
+	return normalizeDomain(v.ValueString())
+}
@@ -110,3 +110,19 @@ func (v EmailAddressValue) ValidateAttribute(_ context.Context, request xattr.Va
 		return
 	}
 }
+
+// NormalizedValue returns the lower-case ASCII form of the email address which is equal for all semantically equal values.
+func (v EmailAddressValue) NormalizedValue() (string, error) {
+	return normalizeEmail(v.ValueString())
+}
+
+// Split returns the local part and the domain of the email address. The domain keeps its given unicode or punycode
+// form since the Migadu API accepts both and DomainNameValue treats them as equal.
+func (v EmailAddressValue) Split() (string, string) {
+	value := strings.TrimSpace(v.ValueString())
+	index := strings.LastIndex(value, "@")
+	if index < 0 {
+		return value, ""
+	}
+	return value[:index], value[index+1:]
+}
@@ -30,17 +30,19 @@ func (v passwordUseValidator) ValidateString(ctx context.Context, request valida
 	}
 
 	value := request.ConfigValue
+	password := path.MatchRelative().AtParent().AtName("password")
 
 	stringvalidator.OneOf("none", "mailbox", "custom").ValidateString(ctx, request, response)
 
 	if value.ValueString() == "none" || value.ValueString() == "mailbox" {
-		stringvalidator.ConflictsWith(path.MatchRoot("password")).ValidateString(ctx, request, response)
+		stringvalidator.ConflictsWith(password).ValidateString(ctx, request, response)
 	} else if value.ValueString() == "custom" {
-		stringvalidator.AlsoRequires(path.MatchRoot("password")).ValidateString(ctx, request, response)
+		stringvalidator.AlsoRequires(password).ValidateString(ctx, request, response)
 	}
 }
 
-// PasswordUse validates the `password_use` attribute of an identity resource
+// PasswordUse validates the `password_use` attribute of an identity resource. The `password` attribute is resolved
+// relative to `password_use` which allows this validator to be used for nested identities as well.
 func PasswordUse() validator.String {
 	return passwordUseValidator{}
 }
@@ -0,0 +1,25 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+)
+
+// splitAddress fills the local part and domain name of a single-object data source from its 'address' attribute in
+// case the address was given instead of the separate attributes. It returns the path of the attribute that contains
+// the domain name, so that errors about the domain point to the attribute the user actually configured.
+func splitAddress(address custom_types.EmailAddressValue, localPart *types.String, domainName *custom_types.DomainNameValue) path.Path {
+	if address.IsNull() || address.IsUnknown() {
+		return path.Root("domain_name")
+	}
+	addressLocalPart, addressDomainName := address.Split()
+	*localPart = types.StringValue(addressLocalPart)
+	*domainName = custom_types.NewDomainNameValue(addressDomainName)
+	return path.Root("address")
+}
@@ -0,0 +1,144 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"regexp"
+	"time"
+)
+
+// The plural data sources accept optional filters which are applied to the objects returned by the Migadu API before
+// they are written to the state. Filters that are not set match every object.
+
+func regexFilterAttribute(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description:         description + " Uses the RE2 syntax, see https://github.com/google/re2/wiki/Syntax.",
+		MarkdownDescription: description + " Uses the [RE2 syntax](https://github.com/google/re2/wiki/Syntax).",
+		Required:            false,
+		Optional:            true,
+		Computed:            false,
+	}
+}
+
+func destinationFilterAttribute(objects string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description:         "Only return " + objects + " whose destinations contain this email address.",
+		MarkdownDescription: "Only return " + objects + " whose destinations contain this email address.",
+		Required:            false,
+		Optional:            true,
+		Computed:            false,
+		CustomType:          custom_types.EmailAddressType{},
+	}
+}
+
+func boolFilterAttribute(description string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description:         description,
+		MarkdownDescription: description,
+		Required:            false,
+		Optional:            true,
+		Computed:            false,
+	}
+}
+
+func expiresBeforeFilterAttribute(objects string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description:         "Only return expirable " + objects + " that expire before this date. The date must use the format 'YYYY-MM-DD'.",
+		MarkdownDescription: "Only return expirable " + objects + " that expire before this date. The date must use the format `YYYY-MM-DD`.",
+		Required:            false,
+		Optional:            true,
+		Computed:            false,
+	}
+}
+
+// regexFilter compiles the given pattern. It returns nil in case the filter is not set or the pattern is invalid.
+func regexFilter(attributePath path.Path, pattern types.String, diagnostics *diag.Diagnostics) *regexp.Regexp {
+	if pattern.IsNull() || pattern.IsUnknown() {
+		return nil
+	}
+	compiled, err := regexp.Compile(pattern.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(
+			attributePath,
+			"Invalid Regular Expression",
+			"The supplied pattern cannot be compiled into a regular expression: "+err.Error(),
+		)
+		return nil
+	}
+	return compiled
+}
+
+// destinationFilter normalizes the given email address. It returns an empty string in case the filter is not set.
+func destinationFilter(attributePath path.Path, destination custom_types.EmailAddressValue, diagnostics *diag.Diagnostics) string {
+	if destination.IsNull() || destination.IsUnknown() {
+		return ""
+	}
+	normalized, err := destination.NormalizedValue()
+	if err != nil {
+		diagnostics.AddAttributeError(
+			attributePath,
+			"Invalid Destination Filter",
+			"The supplied destination cannot be normalized: "+err.Error(),
+		)
+		return ""
+	}
+	return normalized
+}
+
+// dateFilter parses the given date. It returns nil in case the filter is not set or the date is invalid.
+func dateFilter(attributePath path.Path, date types.String, diagnostics *diag.Diagnostics) *time.Time {
+	if date.IsNull() || date.IsUnknown() {
+		return nil
+	}
+	parsed, err := time.Parse(time.DateOnly, date.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(
+			attributePath,
+			"Invalid Date Filter",
+			"The supplied date must use the format 'YYYY-MM-DD': "+err.Error(),
+		)
+		return nil
+	}
+	return &parsed
+}
+
+func matchesRegex(pattern *regexp.Regexp, value string) bool {
+	return pattern == nil || pattern.MatchString(value)
+}
+
+func matchesBool(filter types.Bool, value bool) bool {
+	return filter.IsNull() || filter.IsUnknown() || filter.ValueBool() == value
+}
+
+func matchesDestination(destination string, destinations []string) bool {
+	if destination == "" {
+		return true
+	}
+	for _, candidate := range destinations {
+		if normalized, err := custom_types.NewEmailAddressValue(candidate).NormalizedValue(); err == nil && normalized == destination {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesExpiresBefore returns true if the object expires before the given date. The Migadu API returns expiration
+// dates either as plain dates or as timestamps, therefore only the date part of the expiration is compared.
+func matchesExpiresBefore(before *time.Time, expirable bool, expiresOn string) bool {
+	if before == nil {
+		return true
+	}
+	if !expirable || len(expiresOn) < len(time.DateOnly) {
+		return false
+	}
+	expires, err := time.Parse(time.DateOnly, expiresOn[:len(time.DateOnly)])
+	return err == nil && expires.Before(*before)
+}
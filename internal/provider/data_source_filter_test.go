@@ -0,0 +1,203 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDataSourceFilter(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(sandbox.New(sandbox.State{
+		Aliases: []model.Alias{
+			{LocalPart: "sales", DomainName: "example.com", Destinations: []string{"alice@example.com", "bob@example.com"}},
+			{LocalPart: "support", DomainName: "example.com", Destinations: []string{"Bob@Example.com"}, IsInternal: true},
+			{LocalPart: "promo", DomainName: "example.com", Destinations: []string{"carol@example.com"}, Expirable: true, ExpiresOn: "2025-01-31"},
+			{LocalPart: "summer", DomainName: "example.com", Destinations: []string{"carol@example.com"}, Expirable: true, ExpiresOn: "2025-08-31"},
+		},
+		Mailboxes: []model.Mailbox{
+			{LocalPart: "alice", DomainName: "example.com", MaySend: true, MayReceive: true},
+			{LocalPart: "bob", DomainName: "example.com", MaySend: false, MayReceive: true, IsInternal: true},
+			{LocalPart: "intern", DomainName: "example.com", MaySend: true, MayReceive: false, Expirable: true, ExpiresOn: "2025-03-01"},
+		},
+		RewriteRules: []model.RewriteRule{
+			{Name: "sales-team", DomainName: "example.com", LocalPartRule: "sales-*", Destinations: []string{"alice@example.com"}},
+			{Name: "support-team", DomainName: "example.com", LocalPartRule: "support-*", Destinations: []string{"bob@example.com"}},
+		},
+	}))
+	defer server.Close()
+
+	providerServer := configuredProviderServerWith(t, server.URL, nil)
+	schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+
+	testCases := map[string]struct {
+		dataSource string
+		filters    map[string]tftypes.Value
+		want       []string
+		wantError  string
+	}{
+		"aliases-unfiltered": {
+			dataSource: "migadu_aliases",
+			want:       []string{"promo", "sales", "summer", "support"},
+		},
+		"aliases-local-part-regex": {
+			dataSource: "migadu_aliases",
+			filters:    map[string]tftypes.Value{"local_part_regex": tftypes.NewValue(tftypes.String, "^s")},
+			want:       []string{"sales", "summer", "support"},
+		},
+		"aliases-destination": {
+			dataSource: "migadu_aliases",
+			filters:    map[string]tftypes.Value{"destination": tftypes.NewValue(tftypes.String, "bob@example.com")},
+			want:       []string{"sales", "support"},
+		},
+		"aliases-is-internal": {
+			dataSource: "migadu_aliases",
+			filters:    map[string]tftypes.Value{"is_internal": tftypes.NewValue(tftypes.Bool, false)},
+			want:       []string{"promo", "sales", "summer"},
+		},
+		"aliases-expirable": {
+			dataSource: "migadu_aliases",
+			filters:    map[string]tftypes.Value{"expirable": tftypes.NewValue(tftypes.Bool, true)},
+			want:       []string{"promo", "summer"},
+		},
+		"aliases-expires-before": {
+			dataSource: "migadu_aliases",
+			filters:    map[string]tftypes.Value{"expires_before": tftypes.NewValue(tftypes.String, "2025-06-01")},
+			want:       []string{"promo"},
+		},
+		"aliases-combined": {
+			dataSource: "migadu_aliases",
+			filters: map[string]tftypes.Value{
+				"local_part_regex": tftypes.NewValue(tftypes.String, "^s"),
+				"destination":      tftypes.NewValue(tftypes.String, "carol@example.com"),
+			},
+			want: []string{"summer"},
+		},
+		"aliases-invalid-regex": {
+			dataSource: "migadu_aliases",
+			filters:    map[string]tftypes.Value{"local_part_regex": tftypes.NewValue(tftypes.String, "(")},
+			wantError:  "Invalid Regular Expression",
+		},
+		"aliases-invalid-date": {
+			dataSource: "migadu_aliases",
+			filters:    map[string]tftypes.Value{"expires_before": tftypes.NewValue(tftypes.String, "01.06.2025")},
+			wantError:  "Invalid Date Filter",
+		},
+		"mailboxes-may-send": {
+			dataSource: "migadu_mailboxes",
+			filters:    map[string]tftypes.Value{"may_send": tftypes.NewValue(tftypes.Bool, true)},
+			want:       []string{"alice", "intern"},
+		},
+		"mailboxes-may-receive": {
+			dataSource: "migadu_mailboxes",
+			filters:    map[string]tftypes.Value{"may_receive": tftypes.NewValue(tftypes.Bool, false)},
+			want:       []string{"intern"},
+		},
+		"mailboxes-is-internal": {
+			dataSource: "migadu_mailboxes",
+			filters:    map[string]tftypes.Value{"is_internal": tftypes.NewValue(tftypes.Bool, true)},
+			want:       []string{"bob"},
+		},
+		"mailboxes-expires-before": {
+			dataSource: "migadu_mailboxes",
+			filters:    map[string]tftypes.Value{"expires_before": tftypes.NewValue(tftypes.String, "2025-03-02")},
+			want:       []string{"intern"},
+		},
+		"mailboxes-local-part-regex": {
+			dataSource: "migadu_mailboxes",
+			filters:    map[string]tftypes.Value{"local_part_regex": tftypes.NewValue(tftypes.String, "^(alice|bob)$")},
+			want:       []string{"alice", "bob"},
+		},
+		"rewrite-rules-name-regex": {
+			dataSource: "migadu_rewrite_rules",
+			filters:    map[string]tftypes.Value{"name_regex": tftypes.NewValue(tftypes.String, "^support")},
+			want:       []string{"support-team"},
+		},
+		"rewrite-rules-destination": {
+			dataSource: "migadu_rewrite_rules",
+			filters:    map[string]tftypes.Value{"destination": tftypes.NewValue(tftypes.String, "ALICE@example.com")},
+			want:       []string{"sales-team"},
+		},
+		"rewrite-rules-no-match": {
+			dataSource: "migadu_rewrite_rules",
+			filters:    map[string]tftypes.Value{"destination": tftypes.NewValue(tftypes.String, "nobody@example.com")},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dataSourceType := schemaResponse.DataSourceSchemas[testCase.dataSource].ValueType()
+			attributes := map[string]tftypes.Value{
+				"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+			}
+			for filter, value := range testCase.filters {
+				attributes[filter] = value
+			}
+			response, err := providerServer.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+				TypeName: testCase.dataSource,
+				Config:   dynamicValue(t, dataSourceType, objectValue(dataSourceType, attributes)),
+			})
+			if err != nil {
+				t.Fatalf("ReadDataSource error: %s", err)
+			}
+			assertDiagnosticSummary(t, testCase.wantError, response.Diagnostics)
+			if testCase.wantError != "" {
+				return
+			}
+
+			state, err := response.State.Unmarshal(dataSourceType)
+			if err != nil {
+				t.Fatalf("Could not read state: %s", err)
+			}
+			assert.Equal(t, testCase.want, filteredNames(t, state), "names")
+		})
+	}
+}
+
+// filteredNames returns the local parts or names of all objects returned by a plural data source.
+func filteredNames(t *testing.T, state tftypes.Value) []string {
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		t.Fatalf("Could not read attributes: %s", err)
+	}
+
+	var names []string
+	for _, list := range []string{"aliases", "mailboxes", "rewrites"} {
+		value, ok := attributes[list]
+		if !ok || value.IsNull() {
+			continue
+		}
+		var objects []tftypes.Value
+		if err := value.As(&objects); err != nil {
+			t.Fatalf("Could not read %s: %s", list, err)
+		}
+		for _, object := range objects {
+			var objectAttributes map[string]tftypes.Value
+			if err := object.As(&objectAttributes); err != nil {
+				t.Fatalf("Could not read object: %s", err)
+			}
+			key := "local_part"
+			if list == "rewrites" {
+				key = "name"
+			}
+			var name string
+			if err := objectAttributes[key].As(&name); err != nil {
+				t.Fatalf("Could not read %s: %s", key, err)
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -6,15 +6,163 @@
 package provider
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/metio/migadu-client.go/client"
+	"net/http"
+	"sort"
+	"strings"
 )
 
+const (
+	apiOperationCreate = "create"
+	apiOperationRead   = "read"
+	apiOperationUpdate = "update"
+	apiOperationDelete = "delete"
+)
+
+// apiAttributes maps the name of a field in a request to the Migadu API to the attribute that contains its value.
+type apiAttributes func(field string) (path.Path, bool)
+
+// rootAttributes maps API fields to top-level attributes. Fields missing in the given map have no attribute.
+func rootAttributes(fields map[string]string) apiAttributes {
+	return func(field string) (path.Path, bool) {
+		attribute, ok := fields[field]
+		if !ok {
+			return path.Empty(), false
+		}
+		return path.Root(attribute), true
+	}
+}
+
+// nestedAttributes maps API fields to attributes nested below the given path. Fields missing in the given map are
+// mapped to the given path itself.
+func nestedAttributes(parent path.Path, fields map[string]string) apiAttributes {
+	return func(field string) (path.Path, bool) {
+		if attribute, ok := fields[field]; ok {
+			return parent.AtName(attribute), true
+		}
+		return parent, true
+	}
+}
+
 func standardAPIErrorDetail(err error) string {
 	return "While calling the API, an unexpected error was returned in the response. " +
 		"Please contact the provider developer if you are unsure how to resolve the error.\n\n" +
 		"Error: " + err.Error()
 }
 
+// apiErrorDiagnostic returns a diagnostic for an error returned while calling the Migadu API to create, read, update,
+// or delete an object. Well known status codes get a detail that explains how to resolve the error, and errors that
+// name a field of the request are attached to the matching attribute. Attributes can be nil in case the error is not
+// related to any configuration.
+func apiErrorDiagnostic(summary, object, operation string, err error, attributes apiAttributes) diag.Diagnostic {
+	var requestError *client.RequestError
+	if !errors.As(err, &requestError) {
+		return diag.NewErrorDiagnostic(summary, standardAPIErrorDetail(err))
+	}
+
+	field, message := apiErrorMessage(requestError.ResponseBody)
+	var detail string
+	switch requestError.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		detail = "The Migadu API rejected the credentials of the provider. " +
+			"Check that 'username' is the email address you use to sign in to the Migadu admin panel, not the address of a mailbox, " +
+			"and that 'token' is an API key that is allowed to " + operation + " objects. Read-only API keys cannot change objects."
+	case http.StatusNotFound:
+		if operation == apiOperationCreate {
+			detail = "The Migadu API could not find the domain of this " + object + ". " +
+				"Check that 'domain_name' is spelled correctly and that the domain was added to your Migadu account."
+			field = "domain_name"
+			if object == "identity" {
+				detail = "The Migadu API could not find the mailbox or the domain of this identity. " +
+					"Check that the mailbox exists and that 'domain_name' is spelled correctly and was added to your Migadu account."
+				// the mailbox is part of the request path and the more likely cause of the error
+				field = "mailbox"
+			}
+		} else {
+			detail = "The Migadu API could not find this " + object + ". " +
+				"It might have been deleted outside of Terraform or its domain might be missing from your Migadu account."
+		}
+	case http.StatusConflict:
+		detail = "The " + object + " conflicts with an existing object of the Migadu API, e.g. an alias, identity, or mailbox with the same address. " +
+			"Import the existing object or choose a different name."
+	case http.StatusUnprocessableEntity:
+		detail = "The Migadu API rejected the " + object + " as invalid"
+		if message != "" {
+			detail += ": " + message
+		} else {
+			detail += "."
+		}
+	case http.StatusTooManyRequests:
+		detail = "The rate limit of the Migadu API was hit. " +
+			"Wait a moment before trying again or reduce the number of concurrent operations, e.g. with 'terraform apply -parallelism=1' or a lower 'identities_concurrency' in the provider configuration."
+	default:
+		return diag.NewErrorDiagnostic(summary, standardAPIErrorDetail(err))
+	}
+	detail += "\n\nError: " + err.Error()
+
+	if attributes != nil && field != "" {
+		if attributePath, ok := attributes(field); ok {
+			return diag.NewAttributeErrorDiagnostic(attributePath, summary, detail)
+		}
+	}
+	return diag.NewErrorDiagnostic(summary, detail)
+}
+
+// apiErrorMessage extracts the first field and the message of an error returned by the Migadu API. Supported bodies
+// are {"error": "message"}, {"message": "message"}, and {"errors": {"field": ["message"]}}.
+func apiErrorMessage(body []byte) (string, string) {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", strings.TrimSpace(string(body))
+	}
+
+	var fieldErrors map[string]json.RawMessage
+	if err := json.Unmarshal(response["errors"], &fieldErrors); err == nil && len(fieldErrors) > 0 {
+		fields := make([]string, 0, len(fieldErrors))
+		for field := range fieldErrors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		messages := make([]string, 0, len(fields))
+		for _, field := range fields {
+			var fieldMessages []string
+			var fieldMessage string
+			if err := json.Unmarshal(fieldErrors[field], &fieldMessages); err == nil {
+				messages = append(messages, fmt.Sprintf("%s %s", field, strings.Join(fieldMessages, ", ")))
+			} else if err := json.Unmarshal(fieldErrors[field], &fieldMessage); err == nil {
+				messages = append(messages, fmt.Sprintf("%s %s", field, fieldMessage))
+			}
+		}
+		return fields[0], strings.Join(messages, "; ")
+	}
+
+	for _, key := range []string{"error", "message"} {
+		var message string
+		if err := json.Unmarshal(response[key], &message); err == nil && message != "" {
+			return "", message
+		}
+	}
+	return "", strings.TrimSpace(string(body))
+}
+
 func standardImportErrorDetail(format string, id string) string {
 	return fmt.Sprintf("Expected import identifier with format: '%s' Got: '%s'", format, id)
 }
+
+func standardUpgradeStateErrorDetail(err error) string {
+	return "While upgrading the state from a previous version of this provider, an unexpected error occurred. " +
+		"Please take a look at MIGRATIONS.md for manual migration steps or contact the provider developers.\n\n" +
+		"Error: " + err.Error()
+}
+
+func standardMoveStateErrorDetail(err error) string {
+	return "While moving the state of a renamed resource, an unexpected error occurred. " +
+		"Please take a look at MIGRATIONS.md for manual migration steps or contact the provider developers.\n\n" +
+		"Error: " + err.Error()
+}
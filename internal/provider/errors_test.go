@@ -0,0 +1,120 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestErrors_StatusCodes(t *testing.T) {
+	testCases := map[string]struct {
+		diagnostic diag.Diagnostic
+		summary    string
+		detail     string
+		path       path.Path
+	}{
+		"unexpected": {
+			diagnostic: provider.AliasCreateError(errors.New("connection refused")),
+			summary:    "Error Creating Alias",
+			detail:     "Please contact the provider developer",
+		},
+		"unexpected-status-code": {
+			diagnostic: provider.AliasCreateError(requestError(http.StatusInternalServerError, `{"error":"boom"}`)),
+			summary:    "Error Creating Alias",
+			detail:     "Please contact the provider developer",
+		},
+		"unauthorized": {
+			diagnostic: provider.MailboxReadError(requestError(http.StatusUnauthorized, "")),
+			summary:    "Error Reading Mailbox",
+			detail:     "rejected the credentials",
+		},
+		"forbidden": {
+			diagnostic: provider.MailboxUpdateError(requestError(http.StatusForbidden, "")),
+			summary:    "Error Updating Mailbox",
+			detail:     "Read-only API keys cannot change objects",
+		},
+		"not-found-create": {
+			diagnostic: provider.RewriteRuleCreateError(requestError(http.StatusNotFound, "")),
+			summary:    "Error Creating RewriteRule Rule",
+			detail:     "could not find the domain of this rewrite rule",
+			path:       path.Root("domain_name"),
+		},
+		"not-found-create-identity": {
+			diagnostic: provider.IdentityCreateError(requestError(http.StatusNotFound, "")),
+			summary:    "Error Creating Identity",
+			detail:     "could not find the mailbox or the domain of this identity",
+			path:       path.Root("local_part"),
+		},
+		"not-found-delete": {
+			diagnostic: provider.AliasDeleteError(requestError(http.StatusNotFound, "")),
+			summary:    "Error Deleting Alias",
+			detail:     "could not find this alias",
+		},
+		"conflict": {
+			diagnostic: provider.MailboxCreateError(requestError(http.StatusConflict, "")),
+			summary:    "Error Creating Mailbox",
+			detail:     "conflicts with an existing object",
+		},
+		"validation-message": {
+			diagnostic: provider.AliasCreateError(requestError(http.StatusUnprocessableEntity, `{"error":"Destinations are invalid"}`)),
+			summary:    "Error Creating Alias",
+			detail:     "rejected the alias as invalid: Destinations are invalid",
+		},
+		"validation-field": {
+			diagnostic: provider.AliasUpdateError(requestError(http.StatusUnprocessableEntity, `{"errors":{"expireable":["must be true"],"expires_on":["is missing"]}}`)),
+			summary:    "Error Updating Alias",
+			detail:     "expireable must be true; expires_on is missing",
+			path:       path.Root("expirable"),
+		},
+		"validation-renamed-field": {
+			diagnostic: provider.IdentityUpdateError(requestError(http.StatusUnprocessableEntity, `{"errors":{"local_part":"is taken"}}`)),
+			summary:    "Error Updating Identity",
+			detail:     "local_part is taken",
+			path:       path.Root("identity"),
+		},
+		"validation-unknown-field": {
+			diagnostic: provider.MailboxCreateError(requestError(http.StatusUnprocessableEntity, `{"errors":{"unknown":["is wrong"]}}`)),
+			summary:    "Error Creating Mailbox",
+			detail:     "unknown is wrong",
+		},
+		"validation-read": {
+			diagnostic: provider.MailboxReadError(requestError(http.StatusUnprocessableEntity, `{"errors":{"name":["is wrong"]}}`)),
+			summary:    "Error Reading Mailbox",
+			detail:     "name is wrong",
+		},
+		"rate-limit": {
+			diagnostic: provider.RewriteRuleDeleteError(requestError(http.StatusTooManyRequests, "")),
+			summary:    "Error Deleting RewriteRule Rule",
+			detail:     "rate limit",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, diag.SeverityError, testCase.diagnostic.Severity(), "severity")
+			assert.Equal(t, testCase.summary, testCase.diagnostic.Summary(), "summary")
+			assert.Contains(t, testCase.diagnostic.Detail(), testCase.detail, "detail")
+
+			withPath, ok := testCase.diagnostic.(diag.DiagnosticWithPath)
+			if len(testCase.path.Steps()) == 0 {
+				assert.False(t, ok, "unexpected path: %v", testCase.diagnostic)
+			} else if assert.True(t, ok, "missing path") {
+				assert.Equal(t, testCase.path, withPath.Path(), "path")
+			}
+		})
+	}
+}
+
+func requestError(statusCode int, body string) error {
+	return fmt.Errorf("CreateAlias: %w", &client.RequestError{StatusCode: statusCode, ResponseBody: []byte(body)})
+}
@@ -0,0 +1,145 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// HTTPTransportOptions configure how the provider connects to the Migadu API.
+type HTTPTransportOptions struct {
+	ProxyURL           string
+	CACertFile         string
+	CACertPEM          string
+	InsecureSkipVerify bool
+	UserAgent          string
+}
+
+// NewHTTPTransport creates a transport for the Migadu client. Proxies default to the HTTP_PROXY, HTTPS_PROXY, and
+// NO_PROXY environment variables, and additional CA certificates are trusted on top of the system pool.
+func NewHTTPTransport(options HTTPTransportOptions) (http.RoundTripper, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if !proxyURL.IsAbs() || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: '%s' is not an absolute URL", options.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if options.CACertFile != "" || options.CACertPEM != "" || options.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: options.InsecureSkipVerify, //nolint:gosec // opt-in for local test stand-ins
+		}
+	}
+
+	if options.CACertFile != "" || options.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if options.CACertFile != "" {
+			pem, err := os.ReadFile(options.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, errors.New("CA certificate file does not contain any PEM encoded certificate")
+			}
+		}
+		if options.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(options.CACertPEM)) {
+			return nil, errors.New("CA certificate does not contain any PEM encoded certificate")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	return &UserAgentTransport{Next: &RateLimitTransport{Next: transport}, UserAgent: options.UserAgent}, nil
+}
+
+// rateLimitRetries is the number of times a request is repeated after the Migadu API answered with 429.
+const rateLimitRetries = 3
+
+// maxRetryAfter limits how long a single Retry-After header can delay a request.
+const maxRetryAfter = 30 * time.Second
+
+// RateLimitTransport repeats GET requests that hit the rate limit of the Migadu API after waiting for the duration
+// of the Retry-After header, or one second if the header is missing. Other methods are never repeated, and the wait
+// ends early once the context of the request is canceled.
+type RateLimitTransport struct {
+	Next http.RoundTripper
+}
+
+func (t *RateLimitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := t.Next.RoundTrip(request)
+		if err != nil || response.StatusCode != http.StatusTooManyRequests || request.Method != http.MethodGet || attempt == rateLimitRetries {
+			return response, err
+		}
+
+		wait := retryAfter(response.Header.Get("Retry-After"), time.Now())
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryAfter parses the value of a Retry-After header which is either a number of seconds or an HTTP date.
+func retryAfter(value string, now time.Time) time.Duration {
+	wait := time.Second
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = date.Sub(now)
+	}
+	return min(max(wait, 0), maxRetryAfter)
+}
+
+// UserAgentTransport sets the User-Agent header of every request.
+type UserAgentTransport struct {
+	Next      http.RoundTripper
+	UserAgent string
+}
+
+func (t *UserAgentTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if t.UserAgent != "" {
+		request = request.Clone(request.Context())
+		request.Header.Set("User-Agent", t.UserAgent)
+	}
+	return t.Next.RoundTrip(request)
+}
+
+// userAgent returns the User-Agent header of the provider.
+func userAgent(providerVersion string, terraformVersion string) string {
+	if providerVersion == "" {
+		providerVersion = "dev"
+	}
+	agent := fmt.Sprintf("terraform-provider-migadu/%s (+https://registry.terraform.io/providers/metio/migadu)", providerVersion)
+	if terraformVersion != "" {
+		agent += " Terraform/" + terraformVersion
+	}
+	return agent
+}
@@ -0,0 +1,198 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"encoding/pem"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewHTTPTransport_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		proxied = append(proxied, request.URL.String())
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	transport, err := provider.NewHTTPTransport(provider.HTTPTransportOptions{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport error: %s", err)
+	}
+	response, err := (&http.Client{Transport: transport}).Get("http://api.migadu.invalid/v1/domains")
+	if err != nil {
+		t.Fatalf("Request error: %s", err)
+	}
+	_ = response.Body.Close()
+
+	assert.Equal(t, []string{"http://api.migadu.invalid/v1/domains"}, proxied, "proxied requests")
+}
+
+func TestNewHTTPTransport_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	directory := t.TempDir()
+	certificateFile := writeFile(t, directory, "ca.pem", certificate)
+	invalidFile := writeFile(t, directory, "invalid.pem", "not a certificate")
+
+	testCases := map[string]struct {
+		options      provider.HTTPTransportOptions
+		wantError    string
+		wantUnsigned bool
+	}{
+		"system-pool": {
+			options:      provider.HTTPTransportOptions{},
+			wantUnsigned: true,
+		},
+		"ca-cert-pem": {
+			options: provider.HTTPTransportOptions{CACertPEM: certificate},
+		},
+		"ca-cert-file": {
+			options: provider.HTTPTransportOptions{CACertFile: certificateFile},
+		},
+		"insecure-skip-verify": {
+			options: provider.HTTPTransportOptions{InsecureSkipVerify: true},
+		},
+		"invalid-ca-cert-pem": {
+			options:   provider.HTTPTransportOptions{CACertPEM: "not a certificate"},
+			wantError: "CA certificate does not contain any PEM encoded certificate",
+		},
+		"invalid-ca-cert-file": {
+			options:   provider.HTTPTransportOptions{CACertFile: invalidFile},
+			wantError: "CA certificate file does not contain any PEM encoded certificate",
+		},
+		"missing-ca-cert-file": {
+			options:   provider.HTTPTransportOptions{CACertFile: filepath.Join(directory, "missing.pem")},
+			wantError: "cannot read CA certificate file",
+		},
+		"invalid-proxy-url": {
+			options:   provider.HTTPTransportOptions{ProxyURL: "proxy.example.com"},
+			wantError: "invalid proxy URL",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			transport, err := provider.NewHTTPTransport(testCase.options)
+			if testCase.wantError != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), testCase.wantError, "error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewHTTPTransport error: %s", err)
+			}
+
+			response, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if testCase.wantUnsigned {
+				assert.ErrorContains(t, err, "certificate", "error")
+				return
+			}
+			if err != nil {
+				t.Fatalf("Request error: %s", err)
+			}
+			_ = response.Body.Close()
+			assert.Equal(t, http.StatusOK, response.StatusCode, "status code")
+		})
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	testCases := map[string]struct {
+		method       string
+		limited      int
+		wantRequests int
+		wantStatus   int
+	}{
+		"not-limited": {
+			method:       http.MethodGet,
+			limited:      0,
+			wantRequests: 1,
+			wantStatus:   http.StatusOK,
+		},
+		"retried": {
+			method:       http.MethodGet,
+			limited:      2,
+			wantRequests: 3,
+			wantStatus:   http.StatusOK,
+		},
+		"exhausted": {
+			method:       http.MethodGet,
+			limited:      10,
+			wantRequests: 4,
+			wantStatus:   http.StatusTooManyRequests,
+		},
+		"not-repeatable": {
+			method:       http.MethodPost,
+			limited:      1,
+			wantRequests: 1,
+			wantStatus:   http.StatusTooManyRequests,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				requests++
+				if requests <= testCase.limited {
+					writer.Header().Set("Retry-After", "0")
+					writer.WriteHeader(http.StatusTooManyRequests)
+				}
+			}))
+			defer server.Close()
+
+			request, err := http.NewRequest(testCase.method, server.URL, nil)
+			if err != nil {
+				t.Fatalf("Request error: %s", err)
+			}
+			transport := &provider.RateLimitTransport{Next: http.DefaultTransport}
+			response, err := (&http.Client{Transport: transport}).Do(request)
+			if err != nil {
+				t.Fatalf("Request error: %s", err)
+			}
+			_ = response.Body.Close()
+			assert.Equal(t, testCase.wantStatus, response.StatusCode, "status code")
+			assert.Equal(t, testCase.wantRequests, requests, "requests")
+		})
+	}
+}
+
+func TestMigaduProvider_Configure_UserAgent(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		userAgents = append(userAgents, request.UserAgent())
+		_, _ = writer.Write([]byte(`{"domains":[]}`))
+	}))
+	defer server.Close()
+
+	_, diagnostics := configureProviderServer(t, server.URL, map[string]tftypes.Value{
+		"username":             tftypes.NewValue(tftypes.String, "admin@example.com"),
+		"validate_credentials": tftypes.NewValue(tftypes.Bool, true),
+	})
+	assertNoDiagnostics(t, diagnostics)
+
+	if assert.Len(t, userAgents, 1, "requests") {
+		assert.True(t, strings.HasPrefix(userAgents[0], "terraform-provider-migadu/test "), "user agent: %s", userAgents[0])
+	}
+}
+
+func TestMigaduProvider_Configure_InvalidTransport(t *testing.T) {
+	_, diagnostics := configureProviderServer(t, "https://localhost", map[string]tftypes.Value{
+		"ca_cert_pem": tftypes.NewValue(tftypes.String, "not a certificate"),
+	})
+	assertDiagnosticSummary(t, "Invalid Migadu API HTTP Transport", diagnostics)
+}
@@ -0,0 +1,120 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// httpTracingRedactedFields contains the names of all JSON fields whose values are masked in traced bodies. Nested
+// fields with these names are masked as well.
+var httpTracingRedactedFields = []string{
+	"password",
+	"token",
+	"username",
+}
+
+// HTTPTracingTransport logs every HTTP exchange with the Migadu API at TRACE level. Sensitive fields in request and
+// response bodies as well as the credentials of the client are masked.
+type HTTPTracingTransport struct {
+	Next     http.RoundTripper
+	Username string
+	Token    string
+}
+
+func (t *HTTPTracingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	ctx = tflog.SetField(ctx, "http_method", request.Method)
+	ctx = tflog.SetField(ctx, "http_url", request.URL.String())
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, nonEmptyStrings(t.Username, t.Token)...)
+
+	if request.Body != nil && request.Body != http.NoBody {
+		body, err := io.ReadAll(request.Body)
+		_ = request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body))
+		ctx = tflog.SetField(ctx, "http_request_body", redactHTTPBody(body))
+	}
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	start := time.Now()
+	response, err := next.RoundTrip(request)
+	ctx = tflog.SetField(ctx, "http_latency_ms", time.Since(start).Milliseconds())
+	if err != nil {
+		tflog.Trace(ctx, "HTTP request failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return response, err
+	}
+
+	body, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	tflog.Trace(ctx, "HTTP exchange with Migadu API", map[string]interface{}{
+		"http_status":        response.StatusCode,
+		"http_response_body": redactHTTPBody(body),
+	})
+	return response, nil
+}
+
+// redactHTTPBody masks all sensitive fields of a JSON body. Bodies that are not JSON are returned as-is since the
+// Migadu API only uses JSON.
+func redactHTTPBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	redacted, err := json.Marshal(redactHTTPValue(value))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+func redactHTTPValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, field := range typed {
+			if slices.Contains(httpTracingRedactedFields, strings.ToLower(key)) {
+				typed[key] = "***"
+			} else {
+				typed[key] = redactHTTPValue(field)
+			}
+		}
+	case []any:
+		for index, element := range typed {
+			typed[index] = redactHTTPValue(element)
+		}
+	}
+	return value
+}
+
+func nonEmptyStrings(values ...string) []string {
+	var strs []string
+	for _, value := range values {
+		if value != "" {
+			strs = append(strs, value)
+		}
+	}
+	return strs
+}
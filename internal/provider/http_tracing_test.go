@@ -0,0 +1,94 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPTracingTransport(t *testing.T) {
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	migaduClient := tracingClient(t, server.URL, "some-user", "some-token")
+	migaduClient.HTTPClient.Transport = &provider.HTTPTracingTransport{
+		Username: migaduClient.Username,
+		Token:    migaduClient.Token,
+	}
+
+	created, err := migaduClient.CreateMailbox(ctx, "example.com", &model.Mailbox{
+		LocalPart:             "some",
+		Name:                  "Some Name",
+		Password:              "super-secret",
+		PasswordMethod:        "password",
+		PasswordRecoveryEmail: "some-user@example.org",
+	})
+	if err != nil {
+		t.Fatalf("CreateMailbox error: %s", err)
+	}
+	assert.Equal(t, "some@example.com", created.Address, "response body must still be readable")
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("Could not decode log: %s", err)
+	}
+	if !assert.Len(t, entries, 1, "log entries") {
+		return
+	}
+	entry := entries[0]
+	assert.Equal(t, "trace", entry["@level"], "level")
+	assert.Equal(t, http.MethodPost, entry["http_method"], "method")
+	assert.Equal(t, server.URL+"/domains/example.com/mailboxes", entry["http_url"], "url")
+	assert.EqualValues(t, http.StatusOK, entry["http_status"], "status")
+	assert.Contains(t, entry, "http_latency_ms", "latency")
+
+	requestBody, _ := entry["http_request_body"].(string)
+	assert.Contains(t, requestBody, `"password":"***"`, "request body")
+	assert.NotContains(t, requestBody, "super-secret", "request body")
+	assert.NotContains(t, requestBody, "some-user", "request body")
+
+	responseBody, _ := entry["http_response_body"].(string)
+	assert.Contains(t, responseBody, `"address":"some@example.com"`, "response body")
+	assert.False(t, strings.Contains(output.String(), "some-token"), "token")
+}
+
+func TestHTTPTracingTransport_Disabled(t *testing.T) {
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	_, err := tracingClient(t, server.URL, "some-user", "some-token").GetMailboxes(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("GetMailboxes error: %s", err)
+	}
+
+	assert.Empty(t, output.String(), "log")
+}
+
+func tracingClient(t *testing.T, endpoint, username, token string) *client.MigaduClient {
+	migaduClient, err := client.New(&endpoint, &username, &token, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Could not create client: %s", err)
+	}
+	return migaduClient
+}
@@ -11,10 +11,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"slices"
+	"strings"
 )
 
 var (
@@ -28,13 +32,15 @@ func NewIdentitiesDataSource() datasource.DataSource {
 
 type IdentitiesDataSource struct {
 	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
 }
 
 type IdentitiesDataSourceModel struct {
-	ID         custom_types.EmailAddressValue `tfsdk:"id"`
-	LocalPart  types.String                   `tfsdk:"local_part"`
-	DomainName custom_types.DomainNameValue   `tfsdk:"domain_name"`
-	Identities []IdentityModel                `tfsdk:"identities"`
+	ID                    custom_types.EmailAddressValue `tfsdk:"id"`
+	LocalPart             types.String                   `tfsdk:"local_part"`
+	DomainName            custom_types.DomainNameValue   `tfsdk:"domain_name"`
+	Identities            []IdentityModel                `tfsdk:"identities"`
+	IdentitiesByLocalPart map[string]IdentityModel       `tfsdk:"identities_by_local_part"`
 }
 
 type IdentityModel struct {
@@ -91,98 +97,19 @@ func (d *IdentitiesDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 				},
 			},
 			"identities": schema.ListNestedAttribute{
-				Description:         "The configured identities for the given 'domain_name' and 'local_part'.",
-				MarkdownDescription: "The configured identities for the given `domain_name` and `local_part`.",
+				Description:         "The configured identities for the given 'domain_name' and 'local_part' sorted by their local part.",
+				MarkdownDescription: "The configured identities for the given `domain_name` and `local_part` sorted by their local part.",
 				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"local_part": schema.StringAttribute{
-							Description:         "The local part of the identity.",
-							MarkdownDescription: "The local part of the identity.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"domain_name": schema.StringAttribute{
-							Description:         "The domain of the identity.",
-							MarkdownDescription: "The domain of the identity.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-							CustomType:          custom_types.DomainNameType{},
-						},
-						"address": schema.StringAttribute{
-							Description:         "The email address of the identity 'identity@domain_name' as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
-							MarkdownDescription: "The email address of the identity `identity@domain_name` as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-							CustomType:          custom_types.EmailAddressType{},
-						},
-						"name": schema.StringAttribute{
-							Description:         "The name of the identity.",
-							MarkdownDescription: "The name of the identity.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"may_send": schema.BoolAttribute{
-							Description:         "Whether the identity is allowed to send emails.",
-							MarkdownDescription: "Whether the identity is allowed to send emails.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"may_receive": schema.BoolAttribute{
-							Description:         "Whether the identity is allowed to receive emails.",
-							MarkdownDescription: "Whether the identity is allowed to receive emails.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"may_access_imap": schema.BoolAttribute{
-							Description:         "Whether the identity is allowed to use IMAP.",
-							MarkdownDescription: "Whether the identity is allowed to use IMAP.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"may_access_pop3": schema.BoolAttribute{
-							Description:         "Whether the identity is allowed to use POP3.",
-							MarkdownDescription: "Whether the identity is allowed to use POP3.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"may_access_manage_sieve": schema.BoolAttribute{
-							Description:         "Whether the identity is allowed to manage the mail sieve.",
-							MarkdownDescription: "Whether the identity is allowed to manage the mail sieve.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"footer_active": schema.BoolAttribute{
-							Description:         "Whether the footer of the identity is active.",
-							MarkdownDescription: "Whether the footer of the identity is active.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"footer_plain_body": schema.StringAttribute{
-							Description:         "The footer of the identity in 'text/plain' format.",
-							MarkdownDescription: "The footer of the identity in `text/plain` format.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"footer_html_body": schema.StringAttribute{
-							Description:         "The footer of the identity in 'text/html' format.",
-							MarkdownDescription: "The footer of the identity in `text/html` format.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-					},
+					Attributes: identityModelAttributes(),
+				},
+			},
+			"identities_by_local_part": schema.MapNestedAttribute{
+				Description:         "The configured identities for the given 'domain_name' and 'local_part' keyed by their local part.",
+				MarkdownDescription: "The configured identities for the given `domain_name` and `local_part` keyed by their local part.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: identityModelAttributes(),
 				},
 			},
 		},
@@ -194,12 +121,13 @@ func (d *IdentitiesDataSource) Configure(_ context.Context, request datasource.C
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.MigaduClient = providerData.MigaduClient
+		d.DomainScope = providerData.DomainScope
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -211,6 +139,11 @@ func (d *IdentitiesDataSource) Read(ctx context.Context, request datasource.Read
 		return
 	}
 
+	response.Diagnostics.Append(d.DomainScope.Diagnostics(path.Root("domain_name"), data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	identities, err := d.MigaduClient.GetIdentities(ctx, data.DomainName.ValueString(), data.LocalPart.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(IdentityReadError(err))
@@ -218,24 +151,131 @@ func (d *IdentitiesDataSource) Read(ctx context.Context, request datasource.Read
 	}
 
 	for _, identity := range identities.Identities {
-		model := IdentityModel{
-			LocalPart:            types.StringValue(identity.LocalPart),
-			DomainName:           custom_types.NewDomainNameValue(identity.DomainName),
-			Address:              custom_types.NewEmailAddressValue(identity.Address),
-			Name:                 types.StringValue(identity.Name),
-			MaySend:              types.BoolValue(identity.MaySend),
-			MayReceive:           types.BoolValue(identity.MayReceive),
-			MayAccessImap:        types.BoolValue(identity.MayAccessImap),
-			MayAccessPop3:        types.BoolValue(identity.MayAccessPop3),
-			MayAccessManageSieve: types.BoolValue(identity.MayAccessManageSieve),
-			FooterActive:         types.BoolValue(identity.FooterActive),
-			FooterPlainBody:      types.StringValue(identity.FooterPlainBody),
-			FooterHtmlBody:       types.StringValue(identity.FooterHtmlBody),
+		data.Identities = append(data.Identities, newIdentityModel(&identity))
+	}
+
+	slices.SortFunc(data.Identities, compareIdentityModels)
+	if data.Identities != nil {
+		data.IdentitiesByLocalPart = make(map[string]IdentityModel, len(data.Identities))
+		for _, identity := range data.Identities {
+			data.IdentitiesByLocalPart[identity.LocalPart.ValueString()] = identity
 		}
-		data.Identities = append(data.Identities, model)
 	}
 
 	data.ID = custom_types.NewEmailAddressValue(fmt.Sprintf("%s@%s", data.LocalPart.ValueString(), data.DomainName.ValueString()))
 
 	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
 }
+
+// identityModelAttributes returns the computed attributes of a single identity as used by the data sources.
+func identityModelAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"local_part": schema.StringAttribute{
+			Description:         "The local part of the identity.",
+			MarkdownDescription: "The local part of the identity.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"domain_name": schema.StringAttribute{
+			Description:         "The domain of the identity.",
+			MarkdownDescription: "The domain of the identity.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+			CustomType:          custom_types.DomainNameType{},
+		},
+		"address": schema.StringAttribute{
+			Description:         "The email address of the identity 'identity@domain_name' as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+			MarkdownDescription: "The email address of the identity `identity@domain_name` as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+			CustomType:          custom_types.EmailAddressType{},
+		},
+		"name": schema.StringAttribute{
+			Description:         "The name of the identity.",
+			MarkdownDescription: "The name of the identity.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"may_send": schema.BoolAttribute{
+			Description:         "Whether the identity is allowed to send emails.",
+			MarkdownDescription: "Whether the identity is allowed to send emails.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"may_receive": schema.BoolAttribute{
+			Description:         "Whether the identity is allowed to receive emails.",
+			MarkdownDescription: "Whether the identity is allowed to receive emails.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"may_access_imap": schema.BoolAttribute{
+			Description:         "Whether the identity is allowed to use IMAP.",
+			MarkdownDescription: "Whether the identity is allowed to use IMAP.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"may_access_pop3": schema.BoolAttribute{
+			Description:         "Whether the identity is allowed to use POP3.",
+			MarkdownDescription: "Whether the identity is allowed to use POP3.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"may_access_manage_sieve": schema.BoolAttribute{
+			Description:         "Whether the identity is allowed to manage the mail sieve.",
+			MarkdownDescription: "Whether the identity is allowed to manage the mail sieve.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"footer_active": schema.BoolAttribute{
+			Description:         "Whether the footer of the identity is active.",
+			MarkdownDescription: "Whether the footer of the identity is active.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"footer_plain_body": schema.StringAttribute{
+			Description:         "The footer of the identity in 'text/plain' format.",
+			MarkdownDescription: "The footer of the identity in `text/plain` format.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"footer_html_body": schema.StringAttribute{
+			Description:         "The footer of the identity in 'text/html' format.",
+			MarkdownDescription: "The footer of the identity in `text/html` format.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+	}
+}
+
+func compareIdentityModels(a, b IdentityModel) int {
+	return strings.Compare(a.LocalPart.ValueString(), b.LocalPart.ValueString())
+}
+
+func newIdentityModel(identity *model.Identity) IdentityModel {
+	return IdentityModel{
+		LocalPart:            types.StringValue(identity.LocalPart),
+		DomainName:           custom_types.NewDomainNameValue(identity.DomainName),
+		Address:              custom_types.NewEmailAddressValue(identity.Address),
+		Name:                 types.StringValue(identity.Name),
+		MaySend:              types.BoolValue(identity.MaySend),
+		MayReceive:           types.BoolValue(identity.MayReceive),
+		MayAccessImap:        types.BoolValue(identity.MayAccessImap),
+		MayAccessPop3:        types.BoolValue(identity.MayAccessPop3),
+		MayAccessManageSieve: types.BoolValue(identity.MayAccessManageSieve),
+		FooterActive:         types.BoolValue(identity.FooterActive),
+		FooterPlainBody:      types.StringValue(identity.FooterPlainBody),
+		FooterHtmlBody:       types.StringValue(identity.FooterHtmlBody),
+	}
+}
@@ -9,10 +9,13 @@ import (
 	"context"
 	"fmt"
 	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -112,18 +115,18 @@ func TestIdentitiesDataSource_API_Success(t *testing.T) {
 			},
 			want: model.Identities{
 				Identities: []model.Identity{
-					{
-						LocalPart:  "other",
-						DomainName: "example.com",
-						Address:    "other@example.com",
-						Name:       "Some Name",
-					},
 					{
 						LocalPart:  "another",
 						DomainName: "example.com",
 						Address:    "another@example.com",
 						Name:       "Another Name",
 					},
+					{
+						LocalPart:  "other",
+						DomainName: "example.com",
+						Address:    "other@example.com",
+						Name:       "Some Name",
+					},
 				},
 			},
 		},
@@ -309,3 +312,35 @@ func TestIdentitiesDataSource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentitiesDataSource_Keyed(t *testing.T) {
+	identity := func(localPart string) sandbox.Identity {
+		return sandbox.Identity{
+			Mailbox: "mailbox",
+			Identity: model.Identity{
+				LocalPart:  localPart,
+				DomainName: "example.com",
+				Address:    localPart + "@example.com",
+			},
+		}
+	}
+	server := httptest.NewServer(sandbox.New(sandbox.State{
+		Mailboxes: []model.Mailbox{
+			{LocalPart: "mailbox", DomainName: "example.com", Address: "mailbox@example.com"},
+		},
+		Identities: []sandbox.Identity{identity("support"), identity("billing"), identity("sales")},
+	}))
+	defer server.Close()
+
+	attributes := readDataSource(t, configuredProviderServerWith(t, server.URL, nil), "migadu_identities", map[string]tftypes.Value{
+		"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+		"local_part":  tftypes.NewValue(tftypes.String, "mailbox"),
+	})
+
+	assert.Equal(t, []string{"billing", "sales", "support"}, nestedStrings(t, attributes["identities"], "local_part"), "identities")
+	assert.Equal(t, map[string]string{
+		"billing": "billing@example.com",
+		"sales":   "sales@example.com",
+		"support": "support@example.com",
+	}, nestedStringsByKey(t, attributes["identities_by_local_part"], "address"), "identities_by_local_part")
+}
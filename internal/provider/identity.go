@@ -20,32 +20,44 @@ func CreateIdentityIDString(localPart, domainName, identity string) string {
 	return fmt.Sprintf("%s@%s/%s", localPart, domainName, identity)
 }
 
+// identityAttributes maps the API fields of an identity to the attributes of the identity resource. The mailbox is
+// part of the request path instead of its body and is only named by errors about a missing mailbox.
+var identityAttributes = rootAttributes(identityFields(map[string]string{
+	"local_part":  "identity",
+	"domain_name": "domain_name",
+	"mailbox":     "local_part",
+}))
+
+// identityFields adds all API fields shared by the identity resource and the identities of the mailbox resource to
+// the given fields.
+func identityFields(fields map[string]string) map[string]string {
+	fields["name"] = "name"
+	fields["may_send"] = "may_send"
+	fields["may_receive"] = "may_receive"
+	fields["may_access_imap"] = "may_access_imap"
+	fields["may_access_managesieve"] = "may_access_manage_sieve"
+	fields["password"] = "password"
+	fields["password_use"] = "password_use"
+	fields["footer_active"] = "footer_active"
+	fields["footer_plain_body"] = "footer_plain_body"
+	fields["footer_html_body"] = "footer_html_body"
+	return fields
+}
+
 func IdentityCreateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Creating Identity",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Creating Identity", "identity", apiOperationCreate, err, identityAttributes)
 }
 
 func IdentityReadError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Reading Identity",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Reading Identity", "identity", apiOperationRead, err, nil)
 }
 
 func IdentityUpdateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Updating Identity",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Updating Identity", "identity", apiOperationUpdate, err, identityAttributes)
 }
 
 func IdentityDeleteError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Deleting Identity",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Deleting Identity", "identity", apiOperationDelete, err, nil)
 }
 
 func IdentityImportError(id string) diag.Diagnostic {
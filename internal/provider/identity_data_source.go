@@ -11,10 +11,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
 	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"strings"
 )
 
 var (
@@ -27,7 +30,9 @@ func NewIdentityDataSource() datasource.DataSource {
 }
 
 type IdentityDataSource struct {
-	MigaduClient *client.MigaduClient
+	MigaduClient          *client.MigaduClient
+	DomainScope           DomainScope
+	IdentitiesConcurrency int
 }
 
 type IdentityDataSourceModel struct {
@@ -64,43 +69,49 @@ func (d *IdentityDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				Computed:            true,
 			},
 			"local_part": schema.StringAttribute{
-				Description:         "The local part of the mailbox that owns the identity.",
-				MarkdownDescription: "The local part of the mailbox that owns the identity.",
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
+				Description:         "The local part of the mailbox that owns the identity. Required unless 'address' is given, in which case the owning mailbox is looked up when omitted.",
+				MarkdownDescription: "The local part of the mailbox that owns the identity. Required unless `address` is given, in which case the owning mailbox is looked up when omitted.",
+				Required:            false,
+				Optional:            true,
+				Computed:            true,
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
 			"domain_name": schema.StringAttribute{
-				Description:         "The domain name of the mailbox/identity.",
-				MarkdownDescription: "The domain name of the mailbox/identity.",
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
+				Description:         "The domain name of the mailbox/identity. Required unless 'address' is given.",
+				MarkdownDescription: "The domain name of the mailbox/identity. Required unless `address` is given.",
+				Required:            false,
+				Optional:            true,
+				Computed:            true,
 				CustomType:          custom_types.DomainNameType{},
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("identity")),
 				},
 			},
 			"identity": schema.StringAttribute{
-				Description:         "The local part of the identity.",
-				MarkdownDescription: "The local part of the identity.",
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
+				Description:         "The local part of the identity. Required unless 'address' is given.",
+				MarkdownDescription: "The local part of the identity. Required unless `address` is given.",
+				Required:            false,
+				Optional:            true,
+				Computed:            true,
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("local_part"), path.MatchRoot("domain_name")),
 				},
 			},
 			"address": schema.StringAttribute{
-				Description:         "The email address of the identity 'identity@domain_name' as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
-				MarkdownDescription: "The email address of the identity `identity@domain_name` as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+				Description:         "The email address of the identity 'identity@domain_name'. Can be given instead of 'identity' and 'domain_name', otherwise contains the address as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+				MarkdownDescription: "The email address of the identity `identity@domain_name`. Can be given instead of `identity` and `domain_name`, otherwise contains the address as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
 				Required:            false,
-				Optional:            false,
+				Optional:            true,
 				Computed:            true,
 				CustomType:          custom_types.EmailAddressType{},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("identity")),
+					stringvalidator.ConflictsWith(path.MatchRoot("domain_name")),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description:         "The name of the identity.",
@@ -174,12 +185,14 @@ func (d *IdentityDataSource) Configure(_ context.Context, request datasource.Con
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.MigaduClient = providerData.MigaduClient
+		d.DomainScope = providerData.DomainScope
+		d.IdentitiesConcurrency = providerData.IdentitiesConcurrency
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -191,13 +204,30 @@ func (d *IdentityDataSource) Read(ctx context.Context, request datasource.ReadRe
 		return
 	}
 
+	domainPath := splitAddress(data.Address, &data.Identity, &data.DomainName)
+	response.Diagnostics.Append(d.DomainScope.Diagnostics(domainPath, data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if data.LocalPart.IsNull() {
+		localPart, diagnostic := d.findOwningMailbox(ctx, data.DomainName.ValueString(), data.Identity.ValueString())
+		if diagnostic != nil {
+			response.Diagnostics.Append(diagnostic)
+			return
+		}
+		data.LocalPart = types.StringValue(localPart)
+	}
+
 	identity, err := d.MigaduClient.GetIdentity(ctx, data.DomainName.ValueString(), data.LocalPart.ValueString(), data.Identity.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(IdentityReadError(err))
 		return
 	}
 
-	data.Address = custom_types.NewEmailAddressValue(identity.Address)
+	if data.Address.IsNull() {
+		data.Address = custom_types.NewEmailAddressValue(identity.Address)
+	}
 	data.Name = types.StringValue(identity.Name)
 	data.MaySend = types.BoolValue(identity.MaySend)
 	data.MayReceive = types.BoolValue(identity.MayReceive)
@@ -212,3 +242,35 @@ func (d *IdentityDataSource) Read(ctx context.Context, request datasource.ReadRe
 
 	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
 }
+
+// findOwningMailbox returns the local part of the mailbox that owns the identity with the given local part. The Migadu
+// API offers no way to look up an identity without its mailbox, therefore the identities of all mailboxes of the domain
+// are fetched.
+func (d *IdentityDataSource) findOwningMailbox(ctx context.Context, domainName string, identity string) (string, diag.Diagnostic) {
+	mailboxes, err := d.MigaduClient.GetMailboxes(ctx, domainName)
+	if err != nil {
+		return "", MailboxReadError(err)
+	}
+
+	localParts := make([]string, 0, len(mailboxes.Mailboxes))
+	for _, mailbox := range mailboxes.Mailboxes {
+		localParts = append(localParts, mailbox.LocalPart)
+	}
+	identities, err := getMailboxIdentities(ctx, d.MigaduClient, d.IdentitiesConcurrency, domainName, localParts)
+	if err != nil {
+		return "", IdentityReadError(err)
+	}
+
+	for index, mailboxIdentities := range identities {
+		for _, mailboxIdentity := range mailboxIdentities {
+			if strings.EqualFold(mailboxIdentity.LocalPart, identity) {
+				return localParts[index], nil
+			}
+		}
+	}
+	return "", diag.NewAttributeErrorDiagnostic(
+		path.Root("address"),
+		"Identity Not Found",
+		fmt.Sprintf("None of the mailboxes of the domain '%s' owns an identity with the local part '%s'.", domainName, identity),
+	)
+}
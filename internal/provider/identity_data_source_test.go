@@ -9,10 +9,14 @@ import (
 	"context"
 	"fmt"
 	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -224,21 +228,41 @@ func TestIdentityDataSource_Configuration_Errors(t *testing.T) {
 				local_part  = "test"
 				identity    = "test"
 			`,
-			ErrorRegex: `The argument "domain_name" is required, but no definition was found`,
+			ErrorRegex: `Attribute "domain_name" must be specified when "identity" is specified`,
 		},
 		"missing-local-part": {
 			Configuration: `
 				domain_name = "example.com"
 				identity    = "test"
 			`,
-			ErrorRegex: `The argument "local_part" is required, but no definition was found`,
+			ErrorRegex: `Attribute "local_part" must be specified when "identity" is specified`,
 		},
 		"missing-identity": {
 			Configuration: `
 				domain_name = "example.com"
 				local_part  = "test"
 			`,
-			ErrorRegex: `The argument "identity" is required, but no definition was found`,
+			ErrorRegex: `No attribute specified when one \(and only one\) of`,
+		},
+		"address-and-identity": {
+			Configuration: `
+				address  = "test@example.com"
+				identity = "test"
+			`,
+			ErrorRegex: `2 attributes specified when one \(and only one\) of`,
+		},
+		"address-and-domain-name": {
+			Configuration: `
+				address     = "test@example.com"
+				domain_name = "example.com"
+			`,
+			ErrorRegex: `Attribute "domain_name" cannot be specified when "address" is specified`,
+		},
+		"invalid-address": {
+			Configuration: `
+				address = "example.com"
+			`,
+			ErrorRegex: "An email must match the format 'local_part@domain'",
 		},
 	}
 	for name, testCase := range testCases {
@@ -259,3 +283,81 @@ func TestIdentityDataSource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentityDataSource_Address(t *testing.T) {
+	ctx := context.Background()
+	state := sandbox.State{}
+	for _, localPart := range []string{"alice", "bob", "carol"} {
+		state.Mailboxes = append(state.Mailboxes, model.Mailbox{
+			LocalPart:  localPart,
+			DomainName: "example.com",
+			Address:    localPart + "@example.com",
+		})
+		state.Identities = append(state.Identities, sandbox.Identity{
+			Mailbox: localPart,
+			Identity: model.Identity{
+				LocalPart:  localPart + "-identity",
+				DomainName: "example.com",
+				Address:    localPart + "-identity@example.com",
+				Name:       localPart,
+			},
+		})
+	}
+	server := httptest.NewServer(sandbox.New(state))
+	defer server.Close()
+	providerServer := configuredProviderServerWith(t, server.URL, nil)
+
+	testCases := map[string]struct {
+		config        map[string]tftypes.Value
+		wantLocalPart string
+		wantError     string
+	}{
+		"address": {
+			config: map[string]tftypes.Value{
+				"address": tftypes.NewValue(tftypes.String, "bob-identity@example.com"),
+			},
+			wantLocalPart: "bob",
+		},
+		"address-and-local-part": {
+			config: map[string]tftypes.Value{
+				"address":    tftypes.NewValue(tftypes.String, "carol-identity@example.com"),
+				"local_part": tftypes.NewValue(tftypes.String, "carol"),
+			},
+			wantLocalPart: "carol",
+		},
+		"unknown-identity": {
+			config: map[string]tftypes.Value{
+				"address": tftypes.NewValue(tftypes.String, "missing@example.com"),
+			},
+			wantError: "Identity Not Found",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if testCase.wantError != "" {
+				schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+				if err != nil {
+					t.Fatalf("GetProviderSchema error: %s", err)
+				}
+				dataSourceType := schemaResponse.DataSourceSchemas["migadu_identity"].ValueType()
+				response, err := providerServer.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+					TypeName: "migadu_identity",
+					Config:   dynamicValue(t, dataSourceType, objectValue(dataSourceType, testCase.config)),
+				})
+				if err != nil {
+					t.Fatalf("ReadDataSource error: %s", err)
+				}
+				assertDiagnosticSummary(t, testCase.wantError, response.Diagnostics)
+				return
+			}
+
+			attributes := readDataSource(t, providerServer, "migadu_identity", testCase.config)
+			identity := testCase.wantLocalPart + "-identity"
+			assert.Equal(t, tftypes.NewValue(tftypes.String, testCase.wantLocalPart), attributes["local_part"], "local_part")
+			assert.Equal(t, tftypes.NewValue(tftypes.String, identity), attributes["identity"], "identity")
+			assert.Equal(t, tftypes.NewValue(tftypes.String, "example.com"), attributes["domain_name"], "domain_name")
+			assert.Equal(t, tftypes.NewValue(tftypes.String, testCase.wantLocalPart+"@example.com/"+identity), attributes["id"], "id")
+			assert.Equal(t, tftypes.NewValue(tftypes.String, testCase.wantLocalPart), attributes["name"], "name")
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+)
+
+var (
+	_ list.ListResource              = (*IdentityListResource)(nil)
+	_ list.ListResourceWithConfigure = (*IdentityListResource)(nil)
+)
+
+func NewIdentityListResource() list.ListResource {
+	return &IdentityListResource{}
+}
+
+type IdentityListResource struct {
+	MigaduClient          *client.MigaduClient
+	DomainScope           DomainScope
+	IdentitiesConcurrency int
+}
+
+type IdentityListResourceModel struct {
+	LocalPart  types.String                 `tfsdk:"local_part"`
+	DomainName custom_types.DomainNameValue `tfsdk:"domain_name"`
+}
+
+func (r *IdentityListResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_identity"
+}
+
+func (r *IdentityListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, response *list.ListResourceSchemaResponse) {
+	response.Schema = schema.Schema{
+		Description:         "Lists all identities of a mailbox or of all mailboxes of a domain.",
+		MarkdownDescription: "Lists all identities of a mailbox or of all mailboxes of a domain.",
+		Attributes: map[string]schema.Attribute{
+			"local_part": schema.StringAttribute{
+				Description:         "The local part of the mailbox that owns the identities. Leave this attribute unset to list the identities of all mailboxes of the domain.",
+				MarkdownDescription: "The local part of the mailbox that owns the identities. Leave this attribute unset to list the identities of all mailboxes of the domain.",
+				Required:            false,
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"domain_name": schema.StringAttribute{
+				Description:         "The domain name of the mailbox that owns the identities.",
+				MarkdownDescription: "The domain name of the mailbox that owns the identities.",
+				Required:            true,
+				Optional:            false,
+				CustomType:          custom_types.DomainNameType{},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *IdentityListResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.DomainScope = providerData.DomainScope
+		r.IdentitiesConcurrency = providerData.IdentitiesConcurrency
+	} else {
+		response.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+	}
+}
+
+func (r *IdentityListResource) List(ctx context.Context, request list.ListRequest, stream *list.ListResultsStream) {
+	var config IdentityListResourceModel
+	diags := request.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	diags = r.DomainScope.Diagnostics(path.Root("domain_name"), config.DomainName)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	localParts := []string{config.LocalPart.ValueString()}
+	if config.LocalPart.IsNull() {
+		mailboxes, err := r.MigaduClient.GetMailboxes(ctx, config.DomainName.ValueString())
+		if err != nil {
+			stream.Results = list.ListResultsStreamDiagnostics(diag.Diagnostics{MailboxReadError(err)})
+			return
+		}
+		localParts = make([]string, 0, len(mailboxes.Mailboxes))
+		for _, mailbox := range mailboxes.Mailboxes {
+			localParts = append(localParts, mailbox.LocalPart)
+		}
+	}
+
+	identities, err := getMailboxIdentities(ctx, r.MigaduClient, r.IdentitiesConcurrency, config.DomainName.ValueString(), localParts)
+	if err != nil {
+		stream.Results = list.ListResultsStreamDiagnostics(diag.Diagnostics{IdentityReadError(err)})
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for index, mailboxIdentities := range identities {
+			localPart := types.StringValue(localParts[index])
+			for _, identity := range mailboxIdentities {
+				result := request.NewListResult(ctx)
+				result.DisplayName = identity.Address
+
+				result.Diagnostics.Append(result.Identity.Set(ctx, IdentityResourceIdentityModel{
+					LocalPart:  localPart,
+					DomainName: types.StringValue(config.DomainName.ValueString()),
+					Identity:   types.StringValue(identity.LocalPart),
+				})...)
+
+				if request.IncludeResource {
+					result.Diagnostics.Append(result.Resource.Set(ctx, newIdentityResourceModel(localPart, config.DomainName, identity))...)
+				}
+
+				if !push(result) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func newIdentityResourceModel(localPart types.String, domainName custom_types.DomainNameValue, identity model.Identity) IdentityResourceModel {
+	return IdentityResourceModel{
+		ID:                   types.StringValue(CreateIdentityIDString(localPart.ValueString(), domainName.ValueString(), identity.LocalPart)),
+		LocalPart:            localPart,
+		DomainName:           domainName,
+		Identity:             types.StringValue(identity.LocalPart),
+		Address:              custom_types.NewEmailAddressValue(identity.Address),
+		Name:                 types.StringValue(identity.Name),
+		MaySend:              types.BoolValue(identity.MaySend),
+		MayReceive:           types.BoolValue(identity.MayReceive),
+		MayAccessImap:        types.BoolValue(identity.MayAccessImap),
+		MayAccessPop3:        types.BoolValue(identity.MayAccessPop3),
+		MayAccessManageSieve: types.BoolValue(identity.MayAccessManageSieve),
+		Password:             types.StringNull(),
+		PasswordUse:          newPasswordUseValue(identity.PasswordUse),
+		FooterActive:         types.BoolValue(identity.FooterActive),
+		FooterPlainBody:      types.StringValue(identity.FooterPlainBody),
+		FooterHtmlBody:       types.StringValue(identity.FooterHtmlBody),
+	}
+}
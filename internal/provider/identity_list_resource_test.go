@@ -0,0 +1,163 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestIdentityListResource_Schema(t *testing.T) {
+	ctx := context.Background()
+	schemaRequest := list.ListResourceSchemaRequest{}
+	schemaResponse := &list.ListResourceSchemaResponse{}
+
+	provider.NewIdentityListResource().ListResourceConfigSchema(ctx, schemaRequest, schemaResponse)
+
+	if schemaResponse.Diagnostics.HasError() {
+		t.Fatalf("Schema method diagnostics: %+v", schemaResponse.Diagnostics)
+	}
+
+	diagnostics := schemaResponse.Schema.ValidateImplementation(ctx)
+	if diagnostics.HasError() {
+		t.Fatalf("Schema validation diagnostics: %+v", diagnostics)
+	}
+}
+
+func TestIdentityListResource_API_Success(t *testing.T) {
+	testCases := map[string]struct {
+		localPart string
+		domain    string
+		state     []model.Identity
+		want      []ListedResource
+	}{
+		"empty": {
+			localPart: "test",
+			domain:    "example.com",
+			state:     []model.Identity{},
+			want:      nil,
+		},
+		"multiple": {
+			localPart: "test",
+			domain:    "example.com",
+			state: []model.Identity{
+				{
+					LocalPart:   "some",
+					DomainName:  "example.com",
+					Address:     "some@example.com",
+					Name:        "Some Identity",
+					PasswordUse: "custom",
+				},
+				{
+					LocalPart:  "another",
+					DomainName: "example.com",
+					Address:    "another@example.com",
+					Name:       "Another Identity",
+				},
+			},
+			want: []ListedResource{
+				{
+					DisplayName: "some@example.com",
+					ID:          "test@example.com/some",
+					Identity:    map[string]string{"local_part": "test", "domain_name": "example.com", "identity": "some"},
+				},
+				{
+					DisplayName: "another@example.com",
+					ID:          "test@example.com/another",
+					Identity:    map[string]string{"local_part": "test", "domain_name": "example.com", "identity": "another"},
+				},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{Identities: testCase.state}))
+			defer server.Close()
+
+			listed, diagnostics := listResources(t, server.URL, "migadu_identity", map[string]string{
+				"local_part":  testCase.localPart,
+				"domain_name": testCase.domain,
+			})
+
+			assertNoDiagnostics(t, diagnostics)
+			assert.ElementsMatch(t, testCase.want, listed, "Listed")
+		})
+	}
+}
+
+func TestIdentityListResource_API_Success_AllMailboxes(t *testing.T) {
+	server := httptest.NewServer(sandbox.New(sandbox.State{
+		Mailboxes: []model.Mailbox{
+			{LocalPart: "alice", DomainName: "example.com", Address: "alice@example.com"},
+			{LocalPart: "bob", DomainName: "example.com", Address: "bob@example.com"},
+			{LocalPart: "carol", DomainName: "example.com", Address: "carol@example.com"},
+		},
+		Identities: []sandbox.Identity{
+			{Mailbox: "alice", Identity: model.Identity{LocalPart: "alice-work", DomainName: "example.com", Address: "alice-work@example.com"}},
+			{Mailbox: "bob", Identity: model.Identity{LocalPart: "bob-work", DomainName: "example.com", Address: "bob-work@example.com"}},
+			{Mailbox: "bob", Identity: model.Identity{LocalPart: "sales", DomainName: "example.com", Address: "sales@example.com"}},
+		},
+	}))
+	defer server.Close()
+
+	listed, diagnostics := listResources(t, server.URL, "migadu_identity", map[string]string{
+		"domain_name": "example.com",
+	})
+
+	assertNoDiagnostics(t, diagnostics)
+	assert.ElementsMatch(t, []ListedResource{
+		{
+			DisplayName: "alice-work@example.com",
+			ID:          "alice@example.com/alice-work",
+			Identity:    map[string]string{"local_part": "alice", "domain_name": "example.com", "identity": "alice-work"},
+		},
+		{
+			DisplayName: "bob-work@example.com",
+			ID:          "bob@example.com/bob-work",
+			Identity:    map[string]string{"local_part": "bob", "domain_name": "example.com", "identity": "bob-work"},
+		},
+		{
+			DisplayName: "sales@example.com",
+			ID:          "bob@example.com/sales",
+			Identity:    map[string]string{"local_part": "bob", "domain_name": "example.com", "identity": "sales"},
+		},
+	}, listed, "Listed")
+}
+
+func TestIdentityListResource_API_Errors(t *testing.T) {
+	testCases := map[string]APIErrorTestCase{
+		"error-404": {
+			StatusCode: http.StatusNotFound,
+			ErrorRegex: "GetIdentities: status: 404",
+		},
+		"error-500": {
+			StatusCode: http.StatusInternalServerError,
+			ErrorRegex: "GetIdentities: status: 500",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{StatusCode: testCase.StatusCode}))
+			defer server.Close()
+
+			listed, diagnostics := listResources(t, server.URL, "migadu_identity", map[string]string{"local_part": "test", "domain_name": "example.com"})
+
+			assert.Empty(t, listed, "Listed")
+			if len(diagnostics) != 1 || !regexp.MustCompile(testCase.ErrorRegex).MatchString(diagnostics[0].Detail) {
+				t.Fatalf("Expected error matching %s, got: %+v", testCase.ErrorRegex, diagnostics)
+			}
+		})
+	}
+}
@@ -12,12 +12,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/metio/migadu-client.go/client"
 	"github.com/metio/migadu-client.go/model"
@@ -30,7 +32,9 @@ import (
 var (
 	_ resource.Resource                = (*IdentityResource)(nil)
 	_ resource.ResourceWithConfigure   = (*IdentityResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*IdentityResource)(nil)
 	_ resource.ResourceWithImportState = (*IdentityResource)(nil)
+	_ resource.ResourceWithIdentity    = (*IdentityResource)(nil)
 )
 
 func NewIdentityResource() resource.Resource {
@@ -39,6 +43,10 @@ func NewIdentityResource() resource.Resource {
 
 type IdentityResource struct {
 	MigaduClient *client.MigaduClient
+	ReadOnly     bool
+	DomainScope  DomainScope
+	Policy       Policy
+	AuditLog     *AuditLog
 }
 
 type IdentityResourceModel struct {
@@ -60,6 +68,12 @@ type IdentityResourceModel struct {
 	FooterHtmlBody       types.String                   `tfsdk:"footer_html_body"`
 }
 
+type IdentityResourceIdentityModel struct {
+	LocalPart  types.String `tfsdk:"local_part"`
+	DomainName types.String `tfsdk:"domain_name"`
+	Identity   types.String `tfsdk:"identity"`
+}
+
 func (r *IdentityResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
 	response.TypeName = request.ProviderTypeName + "_identity"
 }
@@ -221,21 +235,49 @@ func (r *IdentityResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 		},
 	}
 }
+
+func (r *IdentityResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, response *resource.IdentitySchemaResponse) {
+	response.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"local_part": identityschema.StringAttribute{
+				Description:       "The local part of the mailbox that owns the identity.",
+				RequiredForImport: true,
+			},
+			"domain_name": identityschema.StringAttribute{
+				Description:       "The domain name of the mailbox that owns the identity.",
+				RequiredForImport: true,
+			},
+			"identity": identityschema.StringAttribute{
+				Description:       "The local part of the identity.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
 func (r *IdentityResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if request.ProviderData == nil {
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		r.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.ReadOnly = providerData.ReadOnly
+		r.DomainScope = providerData.DomainScope
+		r.Policy = providerData.Policy
+		r.AuditLog = providerData.AuditLog
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
 
+func (r *IdentityResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	response.Diagnostics.Append(resourcePlanDiagnostics(ctx, r.ReadOnly, r.DomainScope, r.Policy, "migadu_identity", "Identity", request)...)
+}
+
 func (r *IdentityResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 	var plan IdentityResourceModel
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
@@ -264,6 +306,7 @@ func (r *IdentityResource) Create(ctx context.Context, request resource.CreateRe
 
 	createdIdentity, err := r.MigaduClient.CreateIdentity(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), identity)
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_identity", AuditOperationCreate, CreateIdentityID(plan.LocalPart, plan.DomainName, plan.Identity), tftypes.Value{}, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(IdentityCreateError(err))
 		return
 	}
@@ -280,7 +323,13 @@ func (r *IdentityResource) Create(ctx context.Context, request resource.CreateRe
 	plan.FooterPlainBody = types.StringValue(createdIdentity.FooterPlainBody)
 	plan.FooterHtmlBody = types.StringValue(createdIdentity.FooterHtmlBody)
 
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_identity", AuditOperationCreate, CreateIdentityID(plan.LocalPart, plan.DomainName, plan.Identity), tftypes.Value{}, auditState(ctx, request.Plan, plan), nil)...)
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, IdentityResourceIdentityModel{
+		LocalPart:  plan.LocalPart,
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+		Identity:   plan.Identity,
+	})...)
 }
 
 func (r *IdentityResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -316,6 +365,11 @@ func (r *IdentityResource) Read(ctx context.Context, request resource.ReadReques
 	state.FooterHtmlBody = types.StringValue(identity.FooterHtmlBody)
 
 	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, IdentityResourceIdentityModel{
+		LocalPart:  state.LocalPart,
+		DomainName: types.StringValue(state.DomainName.ValueString()),
+		Identity:   state.Identity,
+	})...)
 }
 
 func (r *IdentityResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
@@ -345,6 +399,7 @@ func (r *IdentityResource) Update(ctx context.Context, request resource.UpdateRe
 
 	updatedIdentity, err := r.MigaduClient.UpdateIdentity(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), plan.Identity.ValueString(), identity)
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_identity", AuditOperationUpdate, CreateIdentityID(plan.LocalPart, plan.DomainName, plan.Identity), request.State.Raw, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(IdentityUpdateError(err))
 		return
 	}
@@ -361,7 +416,13 @@ func (r *IdentityResource) Update(ctx context.Context, request resource.UpdateRe
 	plan.FooterPlainBody = types.StringValue(updatedIdentity.FooterPlainBody)
 	plan.FooterHtmlBody = types.StringValue(updatedIdentity.FooterHtmlBody)
 
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_identity", AuditOperationUpdate, CreateIdentityID(plan.LocalPart, plan.DomainName, plan.Identity), request.State.Raw, auditState(ctx, request.Plan, plan), nil)...)
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, IdentityResourceIdentityModel{
+		LocalPart:  plan.LocalPart,
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+		Identity:   plan.Identity,
+	})...)
 }
 
 func (r *IdentityResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -372,6 +433,7 @@ func (r *IdentityResource) Delete(ctx context.Context, request resource.DeleteRe
 	}
 
 	_, err := r.MigaduClient.DeleteIdentity(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString(), state.Identity.ValueString())
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_identity", AuditOperationDelete, CreateIdentityID(state.LocalPart, state.DomainName, state.Identity), request.State.Raw, tftypes.Value{}, err)...)
 	if err != nil {
 		response.Diagnostics.Append(IdentityDeleteError(err))
 		return
@@ -379,23 +441,42 @@ func (r *IdentityResource) Delete(ctx context.Context, request resource.DeleteRe
 }
 
 func (r *IdentityResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
-	idParts := strings.Split(request.ID, "@")
+	var localPart, domainName, identity string
 
-	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-		response.Diagnostics.Append(IdentityImportError(request.ID))
-		return
-	}
+	if request.ID != "" {
+		idParts := strings.Split(request.ID, "@")
 
-	localPart := idParts[0]
-	domainPart := strings.Split(idParts[1], "/")
+		if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+			response.Diagnostics.Append(IdentityImportError(request.ID))
+			return
+		}
 
-	if len(domainPart) != 2 || domainPart[0] == "" || domainPart[1] == "" {
-		response.Diagnostics.Append(IdentityImportError(request.ID))
-		return
-	}
+		localPart = idParts[0]
+		domainPart := strings.Split(idParts[1], "/")
 
-	domainName := domainPart[0]
-	identity := domainPart[1]
+		if len(domainPart) != 2 || domainPart[0] == "" || domainPart[1] == "" {
+			response.Diagnostics.Append(IdentityImportError(request.ID))
+			return
+		}
+
+		domainName = domainPart[0]
+		identity = domainPart[1]
+	} else {
+		var resourceIdentity IdentityResourceIdentityModel
+		response.Diagnostics.Append(request.Identity.Get(ctx, &resourceIdentity)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		localPart = resourceIdentity.LocalPart.ValueString()
+		domainName = resourceIdentity.DomainName.ValueString()
+		identity = resourceIdentity.Identity.ValueString()
+
+		if localPart == "" || domainName == "" || identity == "" {
+			response.Diagnostics.Append(IdentityImportError(CreateIdentityIDString(localPart, domainName, identity)))
+			return
+		}
+	}
 
 	tflog.Trace(ctx, "parsed import ID", map[string]interface{}{
 		"local_part":  localPart,
@@ -403,8 +484,7 @@ func (r *IdentityResource) ImportState(ctx context.Context, request resource.Imp
 		"identity":    identity,
 	})
 
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), request, response)
-
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), CreateIdentityIDString(localPart, domainName, identity))...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("local_part"), localPart)...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("domain_name"), domainName)...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("identity"), identity)...)
@@ -615,3 +615,33 @@ func TestIdentityResource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentityResource_Identity(t *testing.T) {
+	state := &simulator.State{
+		Identities: []model.Identity{
+			{
+				LocalPart:  "someone",
+				DomainName: "example.com",
+				Address:    "someone@example.com",
+				Name:       "Some Identity",
+			},
+		},
+	}
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	testResourceIdentity(t, server.URL, ResourceIdentityTestCase{
+		TypeName: "migadu_identity",
+		ImportID: "test@example.com/someone",
+		Identity: map[string]string{
+			"local_part":  "test",
+			"domain_name": "example.com",
+			"identity":    "someone",
+		},
+		ChangedIdentity: map[string]string{
+			"local_part":  "test",
+			"domain_name": "example.com",
+			"identity":    "other",
+		},
+	})
+}
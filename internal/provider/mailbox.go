@@ -8,6 +8,7 @@ package provider
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
 )
@@ -20,32 +21,74 @@ func CreateMailboxIDString(localPart, domainName string) string {
 	return fmt.Sprintf("%s@%s", localPart, domainName)
 }
 
-func MailboxCreateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Creating Mailbox",
-		standardAPIErrorDetail(err),
+// mailboxAttributes maps the API fields of a mailbox to the attributes of the mailbox resource.
+var mailboxAttributes = rootAttributes(map[string]string{
+	"local_part":              "local_part",
+	"domain_name":             "domain_name",
+	"name":                    "name",
+	"is_internal":             "is_internal",
+	"may_send":                "may_send",
+	"may_receive":             "may_receive",
+	"may_access_imap":         "may_access_imap",
+	"may_access_pop3":         "may_access_pop3",
+	"may_access_managesieve":  "may_access_manage_sieve",
+	"password":                "password",
+	"password_recovery_email": "password_recovery_email",
+	"password_method":         "password_method",
+	"spam_action":             "spam_action",
+	"spam_aggressiveness":     "spam_aggressiveness",
+	"expireable":              "expirable",
+	"expires_on":              "expires_on",
+	"remove_upon_expiry":      "remove_upon_expiry",
+	"sender_denylist":         "sender_denylist",
+	"sender_allowlist":        "sender_allowlist",
+	"recipient_denylist":      "recipient_denylist",
+	"delegations":             "delegations",
+	"autorespond_active":      "auto_respond_active",
+	"autorespond_subject":     "auto_respond_subject",
+	"autorespond_body":        "auto_respond_body",
+	"autorespond_expires_on":  "auto_respond_expires_on",
+	"footer_active":           "footer_active",
+	"footer_plain_body":       "footer_plain_body",
+	"footer_html_body":        "footer_html_body",
+})
+
+// mailboxIdentityAttributes maps the API fields of an identity to the attributes of the identity with the given local
+// part in the identities of a mailbox.
+func mailboxIdentityAttributes(identity string) apiAttributes {
+	return nestedAttributes(path.Root("identities").AtMapKey(identity), identityFields(map[string]string{
+		"may_access_pop3": "may_access_pop3",
+	}))
+}
+
+// mailboxAliasAttributes maps all API fields of an alias to the aliases of a mailbox.
+var mailboxAliasAttributes = nestedAttributes(path.Root("aliases"), nil)
+
+// MailboxAliasConflictError reports an alias in the aliases of a mailbox that already exists in the Migadu API and is
+// therefore not taken over.
+func MailboxAliasConflictError(alias, domainName string) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		path.Root("aliases"),
+		"Alias Already Exists",
+		fmt.Sprintf("The alias '%s' already exists and is not managed by this mailbox. "+
+			"Remove the alias from the Migadu API, manage it with the 'migadu_alias' resource, or choose a different name.", CreateMailboxIDString(alias, domainName)),
 	)
 }
 
+func MailboxCreateError(err error) diag.Diagnostic {
+	return apiErrorDiagnostic("Error Creating Mailbox", "mailbox", apiOperationCreate, err, mailboxAttributes)
+}
+
 func MailboxReadError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Reading Mailbox",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Reading Mailbox", "mailbox", apiOperationRead, err, nil)
 }
 
 func MailboxUpdateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Updating Mailbox",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Updating Mailbox", "mailbox", apiOperationUpdate, err, mailboxAttributes)
 }
 
 func MailboxDeleteError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Deleting Mailbox",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Deleting Mailbox", "mailbox", apiOperationDelete, err, nil)
 }
 
 func MailboxImportError(id string) diag.Diagnostic {
@@ -54,3 +97,10 @@ func MailboxImportError(id string) diag.Diagnostic {
 		standardImportErrorDetail("local_part@domain_name", id),
 	)
 }
+
+func MailboxUpgradeStateError(err error) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Error Upgrading Mailbox State",
+		standardUpgradeStateErrorDetail(err),
+	)
+}
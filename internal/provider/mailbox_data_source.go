@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
@@ -28,6 +29,7 @@ func NewMailboxDataSource() datasource.DataSource {
 
 type MailboxDataSource struct {
 	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
 }
 
 type MailboxDataSourceModel struct {
@@ -79,33 +81,39 @@ func (d *MailboxDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 				CustomType:          custom_types.EmailAddressType{},
 			},
 			"local_part": schema.StringAttribute{
-				Description:         "The local part of the mailbox.",
-				MarkdownDescription: "The local part of the mailbox.",
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
+				Description:         "The local part of the mailbox. Required unless 'address' is given.",
+				MarkdownDescription: "The local part of the mailbox. Required unless `address` is given.",
+				Required:            false,
+				Optional:            true,
+				Computed:            true,
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("domain_name")),
 				},
 			},
 			"domain_name": schema.StringAttribute{
-				Description:         "The domain name of the mailbox.",
-				MarkdownDescription: "The domain name of the mailbox.",
-				Required:            true,
-				Optional:            false,
-				Computed:            false,
+				Description:         "The domain name of the mailbox. Required unless 'address' is given.",
+				MarkdownDescription: "The domain name of the mailbox. Required unless `address` is given.",
+				Required:            false,
+				Optional:            true,
+				Computed:            true,
 				CustomType:          custom_types.DomainNameType{},
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("local_part")),
 				},
 			},
 			"address": schema.StringAttribute{
-				Description:         "The email address of the mailbox 'local_part@domain_name' as returned by the Migadu API. This might be different from the 'id' attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
-				MarkdownDescription: "The email address of the mailbox `local_part@domain_name` as returned by the Migadu API. This might be different from the `id` attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
+				Description:         "The email address of the mailbox 'local_part@domain_name'. Can be given instead of 'local_part' and 'domain_name', otherwise contains the address as returned by the Migadu API. This might be different from the 'id' attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
+				MarkdownDescription: "The email address of the mailbox `local_part@domain_name`. Can be given instead of `local_part` and `domain_name`, otherwise contains the address as returned by the Migadu API. This might be different from the `id` attribute in case you are using international domain names. The Migadu API always returns the punycode version of a domain.",
 				Required:            false,
-				Optional:            false,
+				Optional:            true,
 				Computed:            true,
 				CustomType:          custom_types.EmailAddressType{},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("local_part")),
+					stringvalidator.ConflictsWith(path.MatchRoot("domain_name")),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description:         "The name of the mailbox.",
@@ -305,12 +313,13 @@ func (d *MailboxDataSource) Configure(_ context.Context, request datasource.Conf
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.MigaduClient = providerData.MigaduClient
+		d.DomainScope = providerData.DomainScope
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -322,6 +331,12 @@ func (d *MailboxDataSource) Read(ctx context.Context, request datasource.ReadReq
 		return
 	}
 
+	domainPath := splitAddress(data.Address, &data.LocalPart, &data.DomainName)
+	response.Diagnostics.Append(d.DomainScope.Diagnostics(domainPath, data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	mailbox, err := d.MigaduClient.GetMailbox(ctx, data.DomainName.ValueString(), data.LocalPart.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(MailboxReadError(err))
@@ -353,7 +368,9 @@ func (d *MailboxDataSource) Read(ctx context.Context, request datasource.ReadReq
 	}
 
 	data.ID = custom_types.NewEmailAddressValue(fmt.Sprintf("%s@%s", data.LocalPart.ValueString(), data.DomainName.ValueString()))
-	data.Address = custom_types.NewEmailAddressValue(mailbox.Address)
+	if data.Address.IsNull() {
+		data.Address = custom_types.NewEmailAddressValue(mailbox.Address)
+	}
 	data.Name = types.StringValue(mailbox.Name)
 	data.IsInternal = types.BoolValue(mailbox.IsInternal)
 	data.MaySend = types.BoolValue(mailbox.MaySend)
@@ -0,0 +1,179 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+)
+
+var (
+	_ list.ListResource              = (*MailboxListResource)(nil)
+	_ list.ListResourceWithConfigure = (*MailboxListResource)(nil)
+)
+
+func NewMailboxListResource() list.ListResource {
+	return &MailboxListResource{}
+}
+
+type MailboxListResource struct {
+	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
+}
+
+type MailboxListResourceModel struct {
+	DomainName custom_types.DomainNameValue `tfsdk:"domain_name"`
+}
+
+func (r *MailboxListResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_mailbox"
+}
+
+func (r *MailboxListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, response *list.ListResourceSchemaResponse) {
+	response.Schema = schema.Schema{
+		Description:         "Lists all mailboxes of a domain.",
+		MarkdownDescription: "Lists all mailboxes of a domain.",
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				Description:         "The domain name of the mailboxes.",
+				MarkdownDescription: "The domain name of the mailboxes.",
+				Required:            true,
+				Optional:            false,
+				CustomType:          custom_types.DomainNameType{},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *MailboxListResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.DomainScope = providerData.DomainScope
+	} else {
+		response.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+	}
+}
+
+func (r *MailboxListResource) List(ctx context.Context, request list.ListRequest, stream *list.ListResultsStream) {
+	var config MailboxListResourceModel
+	diags := request.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	diags = r.DomainScope.Diagnostics(path.Root("domain_name"), config.DomainName)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	mailboxes, err := r.MigaduClient.GetMailboxes(ctx, config.DomainName.ValueString())
+	if err != nil {
+		stream.Results = list.ListResultsStreamDiagnostics(diag.Diagnostics{MailboxReadError(err)})
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for _, mailbox := range mailboxes.Mailboxes {
+			result := request.NewListResult(ctx)
+			result.DisplayName = mailbox.Address
+
+			result.Diagnostics.Append(result.Identity.Set(ctx, MailboxResourceIdentityModel{
+				LocalPart:  types.StringValue(mailbox.LocalPart),
+				DomainName: types.StringValue(config.DomainName.ValueString()),
+			})...)
+
+			if request.IncludeResource {
+				state, diags := newMailboxResourceModel(ctx, config.DomainName, mailbox)
+				result.Diagnostics.Append(diags...)
+				result.Diagnostics.Append(result.Resource.Set(ctx, state)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+func newMailboxResourceModel(ctx context.Context, domainName custom_types.DomainNameValue, mailbox model.Mailbox) (MailboxResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	senderDenyList, d := custom_types.NewEmailAddressSetValueFrom(ctx, mailbox.SenderDenyList)
+	diags.Append(d...)
+	senderAllowList, d := custom_types.NewEmailAddressSetValueFrom(ctx, mailbox.SenderAllowList)
+	diags.Append(d...)
+	recipientDenyList, d := custom_types.NewEmailAddressSetValueFrom(ctx, mailbox.RecipientDenyList)
+	diags.Append(d...)
+	delegations, d := custom_types.NewEmailAddressSetValueFrom(ctx, mailbox.Delegations)
+	diags.Append(d...)
+
+	return MailboxResourceModel{
+		ID:                    custom_types.NewEmailAddressValue(CreateMailboxIDString(mailbox.LocalPart, domainName.ValueString())),
+		LocalPart:             types.StringValue(mailbox.LocalPart),
+		DomainName:            domainName,
+		Address:               custom_types.NewEmailAddressValue(mailbox.Address),
+		Name:                  types.StringValue(mailbox.Name),
+		IsInternal:            types.BoolValue(mailbox.IsInternal),
+		MaySend:               types.BoolValue(mailbox.MaySend),
+		MayReceive:            types.BoolValue(mailbox.MayReceive),
+		MayAccessImap:         types.BoolValue(mailbox.MayAccessImap),
+		MayAccessPop3:         types.BoolValue(mailbox.MayAccessPop3),
+		MayAccessManageSieve:  types.BoolValue(mailbox.MayAccessManageSieve),
+		Password:              types.StringNull(),
+		PasswordRecoveryEmail: custom_types.NewEmailAddressValue(mailbox.PasswordRecoveryEmail),
+		PasswordMethod:        passwordMethodValue(mailbox.PasswordMethod),
+		SpamAction:            types.StringValue(mailbox.SpamAction),
+		SpamAggressiveness:    types.StringValue(mailbox.SpamAggressiveness),
+		Expirable:             types.BoolValue(mailbox.Expirable),
+		ExpiresOn:             types.StringValue(mailbox.ExpiresOn),
+		RemoveUponExpiry:      types.BoolValue(mailbox.RemoveUponExpiry),
+		SenderDenyList:        senderDenyList,
+		SenderAllowList:       senderAllowList,
+		RecipientDenyList:     recipientDenyList,
+		Delegations:           delegations,
+		AutoRespondActive:     types.BoolValue(mailbox.AutoRespondActive),
+		AutoRespondSubject:    types.StringValue(mailbox.AutoRespondSubject),
+		AutoRespondBody:       types.StringValue(mailbox.AutoRespondBody),
+		AutoRespondExpiresOn:  types.StringValue(mailbox.AutoRespondExpiresOn),
+		FooterActive:          types.BoolValue(mailbox.FooterActive),
+		FooterPlainBody:       types.StringValue(mailbox.FooterPlainBody),
+		FooterHtmlBody:        types.StringValue(mailbox.FooterHtmlBody),
+		Identities:            types.MapNull(MailboxIdentityObjectType()),
+		Aliases:               types.SetNull(types.StringType),
+	}, diags
+}
+
+// passwordMethodValue returns the password method as reported by the Migadu API or null in case the API did not return
+// one, since the password of a listed mailbox is unknown.
+func passwordMethodValue(passwordMethod string) types.String {
+	if passwordMethod == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(passwordMethod)
+}
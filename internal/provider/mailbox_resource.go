@@ -7,29 +7,41 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/metio/migadu-client.go/client"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_validators"
 	"net/http"
+	"slices"
 	"strings"
 )
 
 var (
-	_ resource.Resource                = (*MailboxResource)(nil)
-	_ resource.ResourceWithConfigure   = (*MailboxResource)(nil)
-	_ resource.ResourceWithImportState = (*MailboxResource)(nil)
+	_ resource.Resource                 = (*MailboxResource)(nil)
+	_ resource.ResourceWithConfigure    = (*MailboxResource)(nil)
+	_ resource.ResourceWithModifyPlan   = (*MailboxResource)(nil)
+	_ resource.ResourceWithImportState  = (*MailboxResource)(nil)
+	_ resource.ResourceWithIdentity     = (*MailboxResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*MailboxResource)(nil)
 )
 
 func NewMailboxResource() resource.Resource {
@@ -38,6 +50,11 @@ func NewMailboxResource() resource.Resource {
 
 type MailboxResource struct {
 	MigaduClient *client.MigaduClient
+	ReadOnly     bool
+	DomainScope  DomainScope
+	Policy       Policy
+	AuditLog     *AuditLog
+	ReadCache    *ReadCache
 }
 
 type MailboxResourceModel struct {
@@ -71,6 +88,47 @@ type MailboxResourceModel struct {
 	FooterActive          types.Bool                        `tfsdk:"footer_active"`
 	FooterPlainBody       types.String                      `tfsdk:"footer_plain_body"`
 	FooterHtmlBody        types.String                      `tfsdk:"footer_html_body"`
+	Identities            types.Map                         `tfsdk:"identities"`
+	Aliases               types.Set                         `tfsdk:"aliases"`
+}
+
+type MailboxResourceIdentityModel struct {
+	LocalPart  types.String `tfsdk:"local_part"`
+	DomainName types.String `tfsdk:"domain_name"`
+}
+
+type MailboxIdentityModel struct {
+	Address              custom_types.EmailAddressValue `tfsdk:"address"`
+	Name                 types.String                   `tfsdk:"name"`
+	MaySend              types.Bool                     `tfsdk:"may_send"`
+	MayReceive           types.Bool                     `tfsdk:"may_receive"`
+	MayAccessImap        types.Bool                     `tfsdk:"may_access_imap"`
+	MayAccessPop3        types.Bool                     `tfsdk:"may_access_pop3"`
+	MayAccessManageSieve types.Bool                     `tfsdk:"may_access_manage_sieve"`
+	Password             types.String                   `tfsdk:"password"`
+	PasswordUse          types.String                   `tfsdk:"password_use"`
+	FooterActive         types.Bool                     `tfsdk:"footer_active"`
+	FooterPlainBody      types.String                   `tfsdk:"footer_plain_body"`
+	FooterHtmlBody       types.String                   `tfsdk:"footer_html_body"`
+}
+
+func MailboxIdentityObjectType() types.ObjectType {
+	return types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"address":                 custom_types.EmailAddressType{},
+			"name":                    types.StringType,
+			"may_send":                types.BoolType,
+			"may_receive":             types.BoolType,
+			"may_access_imap":         types.BoolType,
+			"may_access_pop3":         types.BoolType,
+			"may_access_manage_sieve": types.BoolType,
+			"password":                types.StringType,
+			"password_use":            types.StringType,
+			"footer_active":           types.BoolType,
+			"footer_plain_body":       types.StringType,
+			"footer_html_body":        types.StringType,
+		},
+	}
 }
 
 func (r *MailboxResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -81,6 +139,7 @@ func (r *MailboxResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	response.Schema = schema.Schema{
 		Description:         "Provides a mailbox.",
 		MarkdownDescription: "Provides a mailbox.",
+		Version:             1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description:         "Contains the value 'local_part@domain_name'.",
@@ -350,24 +409,173 @@ func (r *MailboxResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Optional:            true,
 				Computed:            true,
 			},
+			"identities": schema.MapNestedAttribute{
+				Description:         "The identities of this mailbox keyed by the local part of each identity. Identities in this map are created, updated, and deleted together with the mailbox. Removing this attribute deletes the identities it previously managed. Leave this attribute unset in case you manage identities with the 'migadu_identity' resource instead.",
+				MarkdownDescription: "The identities of this mailbox keyed by the local part of each identity. Identities in this map are created, updated, and deleted together with the mailbox. Removing this attribute deletes the identities it previously managed. Leave this attribute unset in case you manage identities with the `migadu_identity` resource instead.",
+				Required:            false,
+				Optional:            true,
+				Computed:            false,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"address": schema.StringAttribute{
+							Description:         "Contains the email address of the identity 'identity@domain_name' as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+							MarkdownDescription: "Contains the email address of the identity `identity@domain_name` as returned by the Migadu API. The Migadu API always returns the punycode version of a domain.",
+							Required:            false,
+							Optional:            false,
+							Computed:            true,
+							CustomType:          custom_types.EmailAddressType{},
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseNonNullStateForUnknown(),
+							},
+						},
+						"name": schema.StringAttribute{
+							Description:         "The name of the identity.",
+							MarkdownDescription: "The name of the identity.",
+							Required:            true,
+							Optional:            false,
+							Computed:            false,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"may_send": schema.BoolAttribute{
+							Description:         "Whether the identity is allowed to send emails.",
+							MarkdownDescription: "Whether the identity is allowed to send emails.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+						"may_receive": schema.BoolAttribute{
+							Description:         "Whether the identity is allowed to receive emails.",
+							MarkdownDescription: "Whether the identity is allowed to receive emails.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+						"may_access_imap": schema.BoolAttribute{
+							Description:         "Whether the identity is allowed to use IMAP.",
+							MarkdownDescription: "Whether the identity is allowed to use IMAP.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+						"may_access_pop3": schema.BoolAttribute{
+							Description:         "Whether the identity is allowed to use POP3.",
+							MarkdownDescription: "Whether the identity is allowed to use POP3.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+						"may_access_manage_sieve": schema.BoolAttribute{
+							Description:         "Whether the identity is allowed to manage the mail sieve.",
+							MarkdownDescription: "Whether the identity is allowed to manage the mail sieve.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+						"password": schema.StringAttribute{
+							Description:         "The password of the identity.",
+							MarkdownDescription: "The password of the identity.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+							Sensitive:           true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"password_use": schema.StringAttribute{
+							Description:         "Configures the password use of the identity. Use 'none' if you just need to be able to send using a specific 'From' identity, but still authenticate with the mailbox address and password. Use 'mailbox' if you want an alternative address but linked to the same mailbox using the same password. Use 'custom' if you need an application specific password.",
+							MarkdownDescription: "Configures the password use of the identity. Use `none` if you just need to be able to send using a specific `From` identity, but still authenticate with the mailbox address and password. Use `mailbox` if you want an alternative address but linked to the same mailbox using the same password. Use `custom` if you need an application specific password.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString("none"),
+							Validators: []validator.String{
+								custom_validators.PasswordUse(),
+							},
+						},
+						"footer_active": schema.BoolAttribute{
+							Description:         "Whether the footer of the identity is active.",
+							MarkdownDescription: "Whether the footer of the identity is active.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+						"footer_plain_body": schema.StringAttribute{
+							Description:         "The footer of the identity in 'text/plain' format.",
+							MarkdownDescription: "The footer of the identity in `text/plain` format.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+						"footer_html_body": schema.StringAttribute{
+							Description:         "The footer of the identity in 'text/html' format.",
+							MarkdownDescription: "The footer of the identity in `text/html` format.",
+							Required:            false,
+							Optional:            true,
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"aliases": schema.SetAttribute{
+				Description:         "The local parts of aliases that have this mailbox as their only destination. Aliases in this set are created and deleted together with the mailbox. Removing this attribute deletes the aliases it previously managed. Aliases that already exist outside of Terraform are reported as a conflict. Aliases that received additional destinations outside of Terraform are reported as drift and reset to this mailbox. Leave this attribute unset in case you manage aliases with the 'migadu_alias' resource instead.",
+				MarkdownDescription: "The local parts of aliases that have this mailbox as their only destination. Aliases in this set are created and deleted together with the mailbox. Removing this attribute deletes the aliases it previously managed. Aliases that already exist outside of Terraform are reported as a conflict. Aliases that received additional destinations outside of Terraform are reported as drift and reset to this mailbox. Leave this attribute unset in case you manage aliases with the `migadu_alias` resource instead.",
+				Required:            false,
+				Optional:            true,
+				Computed:            false,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+		},
+	}
+}
+
+func (r *MailboxResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, response *resource.IdentitySchemaResponse) {
+	response.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"local_part": identityschema.StringAttribute{
+				Description:       "The local part of the mailbox.",
+				RequiredForImport: true,
+			},
+			"domain_name": identityschema.StringAttribute{
+				Description:       "The domain name of the mailbox.",
+				RequiredForImport: true,
+			},
 		},
 	}
 }
+
 func (r *MailboxResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if request.ProviderData == nil {
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		r.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.ReadOnly = providerData.ReadOnly
+		r.DomainScope = providerData.DomainScope
+		r.Policy = providerData.Policy
+		r.AuditLog = providerData.AuditLog
+		r.ReadCache = providerData.ReadCache
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
 
+func (r *MailboxResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	response.Diagnostics.Append(resourcePlanDiagnostics(ctx, r.ReadOnly, r.DomainScope, r.Policy, "migadu_mailbox", "Mailbox", request)...)
+}
+
 func (r *MailboxResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 	var plan MailboxResourceModel
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
@@ -430,6 +638,22 @@ func (r *MailboxResource) Create(ctx context.Context, request resource.CreateReq
 		}
 	}
 
+	var identities map[string]MailboxIdentityModel
+	if !plan.Identities.IsNull() {
+		response.Diagnostics.Append(plan.Identities.ElementsAs(ctx, &identities, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var aliases []string
+	if !plan.Aliases.IsNull() {
+		response.Diagnostics.Append(plan.Aliases.ElementsAs(ctx, &aliases, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	mailbox := &model.Mailbox{
 		LocalPart:             plan.LocalPart.ValueString(),
 		Name:                  plan.Name.ValueString(),
@@ -461,7 +685,9 @@ func (r *MailboxResource) Create(ctx context.Context, request resource.CreateReq
 	}
 
 	createdMailbox, err := r.MigaduClient.CreateMailbox(ctx, plan.DomainName.ValueString(), mailbox)
+	r.ReadCache.Invalidate(plan.DomainName.ValueString())
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_mailbox", AuditOperationCreate, CreateMailboxID(plan.LocalPart, plan.DomainName), tftypes.Value{}, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(MailboxCreateError(err))
 		return
 	}
@@ -490,7 +716,31 @@ func (r *MailboxResource) Create(ctx context.Context, request resource.CreateReq
 	plan.FooterPlainBody = types.StringValue(createdMailbox.FooterPlainBody)
 	plan.FooterHtmlBody = types.StringValue(createdMailbox.FooterHtmlBody)
 
+	// identities and aliases of the mailbox are recorded by applyIdentities and applyAliases
+	appliedMailbox := plan
+	appliedMailbox.Identities = types.MapNull(MailboxIdentityObjectType())
+	appliedMailbox.Aliases = types.SetNull(types.StringType)
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_mailbox", AuditOperationCreate, CreateMailboxID(plan.LocalPart, plan.DomainName), tftypes.Value{}, auditState(ctx, request.Plan, appliedMailbox), nil)...)
+
+	if !plan.Identities.IsNull() {
+		createdIdentities, diags := r.applyIdentities(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), identities, nil)
+		response.Diagnostics.Append(diags...)
+		plan.Identities, diags = types.MapValueFrom(ctx, MailboxIdentityObjectType(), createdIdentities)
+		response.Diagnostics.Append(diags...)
+	}
+
+	if !plan.Aliases.IsNull() {
+		createdAliases, diags := r.applyAliases(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), aliases, nil, nil)
+		response.Diagnostics.Append(diags...)
+		plan.Aliases, diags = types.SetValueFrom(ctx, types.StringType, createdAliases)
+		response.Diagnostics.Append(diags...)
+	}
+
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, MailboxResourceIdentityModel{
+		LocalPart:  plan.LocalPart,
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+	})...)
 }
 
 func (r *MailboxResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -500,7 +750,7 @@ func (r *MailboxResource) Read(ctx context.Context, request resource.ReadRequest
 		return
 	}
 
-	mailbox, err := r.MigaduClient.GetMailbox(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString())
+	mailbox, err := r.ReadCache.GetMailbox(ctx, r.MigaduClient, state.DomainName.ValueString(), state.LocalPart.ValueString())
 	if err != nil {
 		var requestError *client.RequestError
 		if errors.As(err, &requestError) {
@@ -599,7 +849,79 @@ func (r *MailboxResource) Read(ctx context.Context, request resource.ReadRequest
 	state.FooterPlainBody = types.StringValue(mailbox.FooterPlainBody)
 	state.FooterHtmlBody = types.StringValue(mailbox.FooterHtmlBody)
 
+	if !state.Identities.IsNull() {
+		var priorIdentities map[string]MailboxIdentityModel
+		response.Diagnostics.Append(state.Identities.ElementsAs(ctx, &priorIdentities, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		identities, err := r.MigaduClient.GetIdentities(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString())
+		if err != nil {
+			response.Diagnostics.Append(IdentityReadError(err))
+			return
+		}
+
+		receivedIdentities := make(map[string]MailboxIdentityModel, len(identities.Identities))
+		for _, identity := range identities.Identities {
+			if prior, ok := priorIdentities[identity.LocalPart]; ok {
+				receivedIdentities[identity.LocalPart] = newMailboxIdentityModel(&identity, prior.Password, prior.PasswordUse)
+			} else {
+				receivedIdentities[identity.LocalPart] = newMailboxIdentityModel(&identity, types.StringNull(), newPasswordUseValue(identity.PasswordUse))
+			}
+		}
+
+		state.Identities, diags = types.MapValueFrom(ctx, MailboxIdentityObjectType(), receivedIdentities)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !state.Aliases.IsNull() {
+		var priorAliases []string
+		response.Diagnostics.Append(state.Aliases.ElementsAs(ctx, &priorAliases, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		aliases, err := r.ReadCache.GetAliases(ctx, r.MigaduClient, state.DomainName.ValueString())
+		if err != nil {
+			response.Diagnostics.Append(AliasReadError(err))
+			return
+		}
+
+		var receivedAliases []string
+		var driftedAliases []string
+		for _, alias := range aliases.Aliases {
+			if !slices.Contains(priorAliases, alias.LocalPart) {
+				continue
+			}
+			targetsMailbox, diags := aliasTargetsOnly(ctx, alias.Destinations, state.ID)
+			response.Diagnostics.Append(diags...)
+			if response.Diagnostics.HasError() {
+				return
+			}
+			if targetsMailbox {
+				receivedAliases = append(receivedAliases, alias.LocalPart)
+			} else {
+				driftedAliases = append(driftedAliases, alias.LocalPart)
+			}
+		}
+		response.Diagnostics.Append(setDriftedAliases(ctx, response.Private, driftedAliases)...)
+
+		state.Aliases, diags = types.SetValueFrom(ctx, types.StringType, receivedAliases)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, MailboxResourceIdentityModel{
+		LocalPart:  state.LocalPart,
+		DomainName: types.StringValue(state.DomainName.ValueString()),
+	})...)
 }
 
 func (r *MailboxResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
@@ -609,6 +931,12 @@ func (r *MailboxResource) Update(ctx context.Context, request resource.UpdateReq
 		return
 	}
 
+	var state MailboxResourceModel
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	var senderDenyList []string
 	if plan.SenderDenyList.IsUnknown() {
 		plan.SenderDenyList = custom_types.NewEmailAddressSetNull()
@@ -649,6 +977,22 @@ func (r *MailboxResource) Update(ctx context.Context, request resource.UpdateReq
 		}
 	}
 
+	var identities map[string]MailboxIdentityModel
+	if !plan.Identities.IsNull() {
+		response.Diagnostics.Append(plan.Identities.ElementsAs(ctx, &identities, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var aliases []string
+	if !plan.Aliases.IsNull() {
+		response.Diagnostics.Append(plan.Aliases.ElementsAs(ctx, &aliases, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	mailbox := &model.Mailbox{
 		Name:                  plan.Name.ValueString(),
 		IsInternal:            plan.IsInternal.ValueBool(),
@@ -678,7 +1022,9 @@ func (r *MailboxResource) Update(ctx context.Context, request resource.UpdateReq
 	}
 
 	updatedMailbox, err := r.MigaduClient.UpdateMailbox(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), mailbox)
+	r.ReadCache.Invalidate(plan.DomainName.ValueString())
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_mailbox", AuditOperationUpdate, CreateMailboxID(plan.LocalPart, plan.DomainName), request.State.Raw, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(MailboxUpdateError(err))
 		return
 	}
@@ -707,7 +1053,60 @@ func (r *MailboxResource) Update(ctx context.Context, request resource.UpdateReq
 	plan.FooterPlainBody = types.StringValue(updatedMailbox.FooterPlainBody)
 	plan.FooterHtmlBody = types.StringValue(updatedMailbox.FooterHtmlBody)
 
+	// identities and aliases of the mailbox are recorded by applyIdentities and applyAliases
+	appliedMailbox := plan
+	appliedMailbox.Identities = state.Identities
+	appliedMailbox.Aliases = state.Aliases
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_mailbox", AuditOperationUpdate, CreateMailboxID(plan.LocalPart, plan.DomainName), request.State.Raw, auditState(ctx, request.Plan, appliedMailbox), nil)...)
+
+	if !plan.Identities.IsNull() || !state.Identities.IsNull() {
+		var priorIdentities map[string]MailboxIdentityModel
+		if !state.Identities.IsNull() {
+			response.Diagnostics.Append(state.Identities.ElementsAs(ctx, &priorIdentities, false)...)
+			if response.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		// identities removed from the configuration are deleted just like in Delete, so that they are not orphaned
+		updatedIdentities, diags := r.applyIdentities(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), identities, priorIdentities)
+		response.Diagnostics.Append(diags...)
+		if !plan.Identities.IsNull() || len(updatedIdentities) > 0 {
+			plan.Identities, diags = types.MapValueFrom(ctx, MailboxIdentityObjectType(), updatedIdentities)
+			response.Diagnostics.Append(diags...)
+		}
+	}
+
+	if !plan.Aliases.IsNull() || !state.Aliases.IsNull() {
+		var priorAliases []string
+		if !state.Aliases.IsNull() {
+			response.Diagnostics.Append(state.Aliases.ElementsAs(ctx, &priorAliases, false)...)
+			if response.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		driftedAliases, diags := getDriftedAliases(ctx, request.Private)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		// aliases removed from the configuration are deleted just like in Delete, so that they are not orphaned
+		updatedAliases, diags := r.applyAliases(ctx, plan.DomainName.ValueString(), plan.LocalPart.ValueString(), aliases, priorAliases, driftedAliases)
+		response.Diagnostics.Append(diags...)
+		if !plan.Aliases.IsNull() || len(updatedAliases) > 0 {
+			plan.Aliases, diags = types.SetValueFrom(ctx, types.StringType, updatedAliases)
+			response.Diagnostics.Append(diags...)
+		}
+		response.Diagnostics.Append(setDriftedAliases(ctx, response.Private, nil)...)
+	}
+
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, MailboxResourceIdentityModel{
+		LocalPart:  plan.LocalPart,
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+	})...)
 }
 
 func (r *MailboxResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -717,7 +1116,37 @@ func (r *MailboxResource) Delete(ctx context.Context, request resource.DeleteReq
 		return
 	}
 
+	if !state.Identities.IsNull() {
+		var identities map[string]MailboxIdentityModel
+		response.Diagnostics.Append(state.Identities.ElementsAs(ctx, &identities, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		_, diags := r.applyIdentities(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString(), nil, identities)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !state.Aliases.IsNull() {
+		var aliases []string
+		response.Diagnostics.Append(state.Aliases.ElementsAs(ctx, &aliases, false)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		_, diags := r.applyAliases(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString(), nil, aliases, nil)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	_, err := r.MigaduClient.DeleteMailbox(ctx, state.DomainName.ValueString(), state.LocalPart.ValueString())
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_mailbox", AuditOperationDelete, CreateMailboxID(state.LocalPart, state.DomainName), request.State.Raw, tftypes.Value{}, err)...)
+	r.ReadCache.Invalidate(state.DomainName.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(MailboxDeleteError(err))
 		return
@@ -725,22 +1154,322 @@ func (r *MailboxResource) Delete(ctx context.Context, request resource.DeleteReq
 }
 
 func (r *MailboxResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
-	idParts := strings.Split(request.ID, "@")
+	var localPart, domainName string
 
-	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-		response.Diagnostics.Append(MailboxImportError(request.ID))
-		return
+	if request.ID != "" {
+		idParts := strings.Split(request.ID, "@")
+
+		if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+			response.Diagnostics.Append(MailboxImportError(request.ID))
+			return
+		}
+
+		localPart = idParts[0]
+		domainName = idParts[1]
+	} else {
+		var identity MailboxResourceIdentityModel
+		response.Diagnostics.Append(request.Identity.Get(ctx, &identity)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		localPart = identity.LocalPart.ValueString()
+		domainName = identity.DomainName.ValueString()
+
+		if localPart == "" || domainName == "" {
+			response.Diagnostics.Append(MailboxImportError(CreateMailboxIDString(localPart, domainName)))
+			return
+		}
 	}
 
-	localPart := idParts[0]
-	domainName := idParts[1]
 	tflog.Trace(ctx, "parsed import ID", map[string]interface{}{
 		"local_part":  localPart,
 		"domain_name": domainName,
 	})
 
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), request, response)
-
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), CreateMailboxIDString(localPart, domainName))...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("local_part"), localPart)...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("domain_name"), domainName)...)
 }
+
+func (r *MailboxResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// version 0 covers the schemas before and after the breaking changes of 2023.8.23, see MIGRATIONS.md
+		0: {
+			StateUpgrader: func(ctx context.Context, request resource.UpgradeStateRequest, response *resource.UpgradeStateResponse) {
+				upgradedState, err := convertRawState(ctx, r, request.RawState, []string{
+					"delegations_punycode",
+					"identities", // used to contain the addresses of all identities which are now managed in nested attributes
+					"identities_punycode",
+					"recipient_denylist_punycode",
+					"sender_allowlist_punycode",
+					"sender_denylist_punycode",
+				}, []string{
+					"delegations",
+					"recipient_denylist",
+					"sender_allowlist",
+					"sender_denylist",
+				})
+				if err != nil {
+					response.Diagnostics.Append(MailboxUpgradeStateError(err))
+					return
+				}
+				response.State.Raw = upgradedState
+			},
+		},
+	}
+}
+
+// applyIdentities creates, updates, and deletes the identities of a mailbox so that they match the planned identities.
+// Identities that are only part of the prior identities are deleted. The returned map contains all identities that
+// exist after this call, even in case some API calls failed.
+func (r *MailboxResource) applyIdentities(ctx context.Context, domainName string, localPart string, planned map[string]MailboxIdentityModel, prior map[string]MailboxIdentityModel) (map[string]MailboxIdentityModel, diag.Diagnostics) {
+	defer r.ReadCache.Invalidate(domainName)
+
+	var diags diag.Diagnostics
+	applied := make(map[string]MailboxIdentityModel, len(planned))
+
+	for id, identity := range prior {
+		if _, ok := planned[id]; ok {
+			continue
+		}
+
+		_, err := r.MigaduClient.DeleteIdentity(ctx, domainName, localPart, id)
+		diags.Append(r.AuditLog.Record("migadu_identity", AuditOperationDelete, CreateIdentityIDString(localPart, domainName, id), auditMailboxIdentity(ctx, identity), tftypes.Value{}, err)...)
+		if err != nil {
+			var requestError *client.RequestError
+			if errors.As(err, &requestError) && requestError.StatusCode == http.StatusNotFound {
+				continue
+			}
+			diags.Append(IdentityDeleteError(err))
+			applied[id] = identity
+		}
+	}
+
+	for id, identity := range planned {
+		if identity.Password.IsUnknown() {
+			identity.Password = types.StringNull()
+		}
+
+		request := &model.Identity{
+			LocalPart:            id,
+			Name:                 identity.Name.ValueString(),
+			MaySend:              identity.MaySend.ValueBool(),
+			MayReceive:           identity.MayReceive.ValueBool(),
+			MayAccessImap:        identity.MayAccessImap.ValueBool(),
+			MayAccessPop3:        identity.MayAccessPop3.ValueBool(),
+			MayAccessManageSieve: identity.MayAccessManageSieve.ValueBool(),
+			Password:             identity.Password.ValueString(),
+			PasswordUse:          identity.PasswordUse.ValueString(),
+			FooterActive:         identity.FooterActive.ValueBool(),
+			FooterPlainBody:      identity.FooterPlainBody.ValueString(),
+			FooterHtmlBody:       identity.FooterHtmlBody.ValueString(),
+		}
+
+		if priorIdentity, ok := prior[id]; ok {
+			updatedIdentity, err := r.MigaduClient.UpdateIdentity(ctx, domainName, localPart, id, request)
+			if err != nil {
+				diags.Append(r.AuditLog.Record("migadu_identity", AuditOperationUpdate, CreateIdentityIDString(localPart, domainName, id), auditMailboxIdentity(ctx, priorIdentity), tftypes.Value{}, err)...)
+				diags.Append(apiErrorDiagnostic("Error Updating Identity", "identity", apiOperationUpdate, err, mailboxIdentityAttributes(id)))
+				applied[id] = priorIdentity
+				continue
+			}
+			applied[id] = newMailboxIdentityModel(updatedIdentity, identity.Password, identity.PasswordUse)
+			diags.Append(r.AuditLog.Record("migadu_identity", AuditOperationUpdate, CreateIdentityIDString(localPart, domainName, id), auditMailboxIdentity(ctx, priorIdentity), auditMailboxIdentity(ctx, applied[id]), nil)...)
+		} else {
+			createdIdentity, err := r.MigaduClient.CreateIdentity(ctx, domainName, localPart, request)
+			if err != nil {
+				diags.Append(r.AuditLog.Record("migadu_identity", AuditOperationCreate, CreateIdentityIDString(localPart, domainName, id), tftypes.Value{}, tftypes.Value{}, err)...)
+				diags.Append(apiErrorDiagnostic("Error Creating Identity", "identity", apiOperationCreate, err, mailboxIdentityAttributes(id)))
+				continue
+			}
+			applied[id] = newMailboxIdentityModel(createdIdentity, identity.Password, identity.PasswordUse)
+			diags.Append(r.AuditLog.Record("migadu_identity", AuditOperationCreate, CreateIdentityIDString(localPart, domainName, id), tftypes.Value{}, auditMailboxIdentity(ctx, applied[id]), nil)...)
+		}
+	}
+
+	return applied, diags
+}
+
+// applyAliases creates and deletes the aliases of a mailbox so that they match the planned aliases. Drifted aliases
+// that point to the mailbox among other destinations are reset to only point to the mailbox. Any other alias that
+// already exists is reported as a conflict instead of being taken over. The returned slice contains all aliases that
+// exist after this call, even in case some API calls failed.
+func (r *MailboxResource) applyAliases(ctx context.Context, domainName string, localPart string, planned []string, prior []string, drifted []string) ([]string, diag.Diagnostics) {
+	defer r.ReadCache.Invalidate(domainName)
+
+	var diags diag.Diagnostics
+	var applied []string
+	mailboxAddress := CreateMailboxIDString(localPart, domainName)
+
+	for _, alias := range prior {
+		if slices.Contains(planned, alias) {
+			continue
+		}
+
+		_, err := r.MigaduClient.DeleteAlias(ctx, domainName, alias)
+		diags.Append(r.AuditLog.Record("migadu_alias", AuditOperationDelete, CreateAliasIDString(alias, domainName), auditMailboxAlias(alias, domainName, []string{mailboxAddress}), tftypes.Value{}, err)...)
+		if err != nil {
+			var requestError *client.RequestError
+			if errors.As(err, &requestError) && requestError.StatusCode == http.StatusNotFound {
+				continue
+			}
+			diags.Append(AliasDeleteError(err))
+			applied = append(applied, alias)
+		}
+	}
+
+	for _, alias := range planned {
+		if slices.Contains(prior, alias) {
+			applied = append(applied, alias)
+			continue
+		}
+
+		request := &model.Alias{
+			LocalPart:    alias,
+			Destinations: []string{mailboxAddress},
+		}
+
+		existingAlias, err := r.MigaduClient.GetAlias(ctx, domainName, alias)
+		if err != nil {
+			var requestError *client.RequestError
+			if !errors.As(err, &requestError) || requestError.StatusCode != http.StatusNotFound {
+				diags.Append(AliasReadError(err))
+				continue
+			}
+		}
+
+		if existingAlias != nil && !slices.Contains(drifted, alias) {
+			diags.Append(MailboxAliasConflictError(alias, domainName))
+			continue
+		}
+
+		if existingAlias != nil {
+			updatedAlias, err := r.MigaduClient.UpdateAlias(ctx, domainName, alias, request)
+			before := auditMailboxAlias(alias, domainName, existingAlias.Destinations)
+			if err != nil {
+				diags.Append(r.AuditLog.Record("migadu_alias", AuditOperationUpdate, CreateAliasIDString(alias, domainName), before, tftypes.Value{}, err)...)
+				diags.Append(apiErrorDiagnostic("Error Updating Alias", "alias", apiOperationUpdate, err, mailboxAliasAttributes))
+				continue
+			}
+			diags.Append(r.AuditLog.Record("migadu_alias", AuditOperationUpdate, CreateAliasIDString(alias, domainName), before, auditMailboxAlias(alias, domainName, updatedAlias.Destinations), nil)...)
+		} else {
+			createdAlias, err := r.MigaduClient.CreateAlias(ctx, domainName, request)
+			if err != nil {
+				diags.Append(r.AuditLog.Record("migadu_alias", AuditOperationCreate, CreateAliasIDString(alias, domainName), tftypes.Value{}, tftypes.Value{}, err)...)
+				diags.Append(apiErrorDiagnostic("Error Creating Alias", "alias", apiOperationCreate, err, mailboxAliasAttributes))
+				continue
+			}
+			diags.Append(r.AuditLog.Record("migadu_alias", AuditOperationCreate, CreateAliasIDString(alias, domainName), tftypes.Value{}, auditMailboxAlias(alias, domainName, createdAlias.Destinations), nil)...)
+		}
+		applied = append(applied, alias)
+	}
+
+	return applied, diags
+}
+
+// auditMailboxIdentity converts an identity of a mailbox into a raw value for the audit log.
+func auditMailboxIdentity(ctx context.Context, identity MailboxIdentityModel) tftypes.Value {
+	object, diags := types.ObjectValueFrom(ctx, MailboxIdentityObjectType().AttrTypes, identity)
+	if diags.HasError() {
+		return tftypes.Value{}
+	}
+	value, err := object.ToTerraformValue(ctx)
+	if err != nil {
+		return tftypes.Value{}
+	}
+	return value
+}
+
+// auditMailboxAlias converts an alias of a mailbox into a raw value for the audit log that uses the same attribute
+// names as the 'migadu_alias' resource.
+func auditMailboxAlias(localPart string, domainName string, destinations []string) tftypes.Value {
+	values := make([]tftypes.Value, 0, len(destinations))
+	for _, destination := range destinations {
+		values = append(values, tftypes.NewValue(tftypes.String, destination))
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"local_part":   tftypes.String,
+		"domain_name":  tftypes.String,
+		"destinations": tftypes.Set{ElementType: tftypes.String},
+	}}, map[string]tftypes.Value{
+		"local_part":   tftypes.NewValue(tftypes.String, localPart),
+		"domain_name":  tftypes.NewValue(tftypes.String, domainName),
+		"destinations": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, values),
+	})
+}
+
+// driftedAliasesKey is the key in the private state of a mailbox that contains the aliases which were removed from
+// its state because they received additional destinations outside of Terraform.
+const driftedAliasesKey = "drifted_aliases"
+
+// privateState is implemented by the private state of requests and responses of the plugin framework.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// setDriftedAliases remembers the given aliases in the private state, so that a later apply can reset them to only
+// point to the mailbox again. An empty slice removes the key.
+func setDriftedAliases(ctx context.Context, private privateState, aliases []string) diag.Diagnostics {
+	if len(aliases) == 0 {
+		return private.SetKey(ctx, driftedAliasesKey, nil)
+	}
+	value, err := json.Marshal(aliases)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Error Saving Private State", "Could not encode the drifted aliases of the mailbox: "+err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, driftedAliasesKey, value)
+}
+
+// getDriftedAliases returns the aliases that were remembered with setDriftedAliases.
+func getDriftedAliases(ctx context.Context, private privateState) ([]string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, driftedAliasesKey)
+	if diags.HasError() || len(value) == 0 {
+		return nil, diags
+	}
+	var aliases []string
+	if err := json.Unmarshal(value, &aliases); err != nil {
+		diags.AddError("Error Reading Private State", "Could not decode the drifted aliases of the mailbox: "+err.Error())
+	}
+	return aliases, diags
+}
+
+// aliasTargetsOnly checks whether the given destinations of an alias consist of just the given mailbox address.
+func aliasTargetsOnly(ctx context.Context, destinations []string, mailboxAddress custom_types.EmailAddressValue) (bool, diag.Diagnostics) {
+	receivedDestinations, diags := custom_types.NewEmailAddressSetValueFrom(ctx, destinations)
+	if diags.HasError() {
+		return false, diags
+	}
+	expectedDestinations, diags := custom_types.NewEmailAddressSetValueFrom(ctx, []string{mailboxAddress.ValueString()})
+	if diags.HasError() {
+		return false, diags
+	}
+	return expectedDestinations.SetSemanticEquals(ctx, receivedDestinations)
+}
+
+func newMailboxIdentityModel(identity *model.Identity, password types.String, passwordUse types.String) MailboxIdentityModel {
+	return MailboxIdentityModel{
+		Address:              custom_types.NewEmailAddressValue(identity.Address),
+		Name:                 types.StringValue(identity.Name),
+		MaySend:              types.BoolValue(identity.MaySend),
+		MayReceive:           types.BoolValue(identity.MayReceive),
+		MayAccessImap:        types.BoolValue(identity.MayAccessImap),
+		MayAccessPop3:        types.BoolValue(identity.MayAccessPop3),
+		MayAccessManageSieve: types.BoolValue(identity.MayAccessManageSieve),
+		Password:             password,
+		PasswordUse:          passwordUse,
+		FooterActive:         types.BoolValue(identity.FooterActive),
+		FooterPlainBody:      types.StringValue(identity.FooterPlainBody),
+		FooterHtmlBody:       types.StringValue(identity.FooterHtmlBody),
+	}
+}
+
+func newPasswordUseValue(passwordUse string) types.String {
+	if passwordUse == "" {
+		return types.StringValue("none")
+	}
+	return types.StringValue(passwordUse)
+}
@@ -6,10 +6,15 @@
 package provider_test
 
 import (
+	"context"
 	"fmt"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -17,6 +22,23 @@ import (
 	"testing"
 )
 
+func TestMailboxResource_Schema(t *testing.T) {
+	ctx := context.Background()
+	schemaRequest := fwresource.SchemaRequest{}
+	schemaResponse := &fwresource.SchemaResponse{}
+
+	provider.NewMailboxResource().Schema(ctx, schemaRequest, schemaResponse)
+
+	if schemaResponse.Diagnostics.HasError() {
+		t.Fatalf("Schema method diagnostics: %+v", schemaResponse.Diagnostics)
+	}
+
+	diagnostics := schemaResponse.Schema.ValidateImplementation(ctx)
+	if diagnostics.HasError() {
+		t.Fatalf("Schema validation diagnostics: %+v", diagnostics)
+	}
+}
+
 func TestMailboxResource_API_Success_Using_Password(t *testing.T) {
 	testCases := map[string]ResourceTestCase[model.Mailbox]{
 		"change-name": {
@@ -271,6 +293,278 @@ func TestMailboxResource_API_Success_Using_RecoveryEmail(t *testing.T) {
 	}
 }
 
+func TestMailboxResource_API_Success_With_Identities(t *testing.T) {
+	state := &simulator.State{}
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "test"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						identities  = {
+							first = {
+								name = "First Identity"
+							}
+							second = {
+								name         = "Second Identity"
+								password_use = "custom"
+								password     = "other"
+							}
+						}
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.%", "2"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.first.name", "First Identity"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.first.address", "first@example.com"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.first.password_use", "none"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.second.name", "Second Identity"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.second.password_use", "custom"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.second.password", "other"),
+				),
+			},
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "test"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						identities  = {
+							first = {
+								name = "Renamed Identity"
+							}
+							third = {
+								name = "Third Identity"
+							}
+						}
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.%", "2"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.first.name", "Renamed Identity"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.third.name", "Third Identity"),
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "identities.third.address", "third@example.com"),
+					resource.TestCheckNoResourceAttr("migadu_mailbox.test", "identities.second.name"),
+				),
+			},
+			{
+				PreConfig: func() {
+					state.Identities = append(state.Identities, model.Identity{
+						LocalPart:  "unmanaged",
+						DomainName: "example.com",
+						Address:    "unmanaged@example.com",
+						Name:       "Unmanaged Identity",
+					})
+				},
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "test"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						identities  = {
+							first = {
+								name = "Renamed Identity"
+							}
+							third = {
+								name = "Third Identity"
+							}
+						}
+					}
+				`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "test"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("migadu_mailbox.test", "identities.%"),
+					func(_ *terraform.State) error {
+						for _, identity := range state.Identities {
+							if identity.LocalPart == "first" || identity.LocalPart == "third" {
+								return fmt.Errorf("expected identity %s to be deleted", identity.LocalPart)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestMailboxResource_API_Success_With_Aliases(t *testing.T) {
+	state := &simulator.State{}
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "firstname.lastname"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						aliases     = ["flastname", "fl"]
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "aliases.#", "2"),
+					resource.TestCheckTypeSetElemAttr("migadu_mailbox.test", "aliases.*", "flastname"),
+					resource.TestCheckTypeSetElemAttr("migadu_mailbox.test", "aliases.*", "fl"),
+				),
+			},
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "firstname.lastname"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						aliases     = ["flastname", "first"]
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "aliases.#", "2"),
+					resource.TestCheckTypeSetElemAttr("migadu_mailbox.test", "aliases.*", "flastname"),
+					resource.TestCheckTypeSetElemAttr("migadu_mailbox.test", "aliases.*", "first"),
+					func(_ *terraform.State) error {
+						if len(state.Aliases) != 2 {
+							return fmt.Errorf("expected 2 aliases, got: %d", len(state.Aliases))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					for index, alias := range state.Aliases {
+						if alias.LocalPart == "first" {
+							state.Aliases[index].Destinations = append(alias.Destinations, "someone@example.com")
+						}
+					}
+				},
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "firstname.lastname"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						aliases     = ["flastname", "first"]
+					}
+				`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "firstname.lastname"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						aliases     = ["flastname", "first"]
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("migadu_mailbox.test", "aliases.#", "2"),
+					func(_ *terraform.State) error {
+						for _, alias := range state.Aliases {
+							if len(alias.Destinations) != 1 || alias.Destinations[0] != "firstname.lastname@example.com" {
+								return fmt.Errorf("expected alias %s to only target the mailbox, got: %v", alias.LocalPart, alias.Destinations)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "firstname.lastname"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+					}
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("migadu_mailbox.test", "aliases.#"),
+					func(_ *terraform.State) error {
+						if len(state.Aliases) != 0 {
+							return fmt.Errorf("expected 0 aliases, got: %d", len(state.Aliases))
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestMailboxResource_API_Alias_Conflict(t *testing.T) {
+	state := &simulator.State{
+		Aliases: []model.Alias{
+			{
+				LocalPart:    "shared",
+				DomainName:   "example.com",
+				Address:      "shared@example.com",
+				Destinations: []string{"firstname.lastname@example.com", "someone@example.com"},
+			},
+		},
+	}
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "firstname.lastname"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+						aliases     = ["shared"]
+					}
+				`,
+				ExpectError: regexp.MustCompile("Alias Already Exists"),
+			},
+			{
+				PreConfig: func() {
+					assert.Equal(t, []string{"firstname.lastname@example.com", "someone@example.com"}, state.Aliases[0].Destinations, "existing alias must keep its destinations")
+				},
+				Config: providerConfig(server.URL) + `
+					resource "migadu_mailbox" "test" {
+						local_part  = "firstname.lastname"
+						domain_name = "example.com"
+						password    = "secret"
+						name        = "Some Name"
+					}
+				`,
+			},
+		},
+	})
+}
+
 func TestMailboxResource_API_Errors(t *testing.T) {
 	testCases := map[string]APIErrorTestCase{
 		"error-400": {
@@ -563,3 +857,110 @@ func TestMailboxResource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestMailboxResource_UpgradeState(t *testing.T) {
+	testCases := map[string]struct {
+		rawState        string
+		wantDelegations []string
+		wantDenyList    []string
+	}{
+		"before-2023.8.23": {
+			rawState: `{
+				"id": "test@example.com",
+				"local_part": "test",
+				"domain_name": "example.com",
+				"address": "test@example.com",
+				"name": "Some Name",
+				"password": "secret",
+				"password_method": "password",
+				"delegations": ["other@hoß.de", "other@hoß.de"],
+				"delegations_punycode": ["other@xn--ho-hia.de", "other@xn--ho-hia.de"],
+				"identities": ["identity@example.com"],
+				"identities_punycode": ["identity@example.com"],
+				"sender_denylist": ["spam@example.com"],
+				"sender_denylist_punycode": ["spam@example.com"],
+				"sender_allowlist": [],
+				"sender_allowlist_punycode": [],
+				"recipient_denylist": [],
+				"recipient_denylist_punycode": []
+			}`,
+			wantDelegations: []string{"other@hoß.de"},
+			wantDenyList:    []string{"spam@example.com"},
+		},
+		"after-2023.8.23": {
+			rawState: `{
+				"id": "test@example.com",
+				"local_part": "test",
+				"domain_name": "example.com",
+				"address": "test@example.com",
+				"name": "Some Name",
+				"password": "secret",
+				"password_method": "password",
+				"delegations": ["other@example.com"],
+				"sender_denylist": [],
+				"sender_allowlist": [],
+				"recipient_denylist": []
+			}`,
+			wantDelegations: []string{"other@example.com"},
+			wantDenyList:    []string{},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			state := upgradeState(t, provider.NewMailboxResource(), 0, testCase.rawState)
+
+			var mailbox provider.MailboxResourceModel
+			diagnostics := state.Get(ctx, &mailbox)
+			if diagnostics.HasError() {
+				t.Fatalf("Could not read upgraded state: %+v", diagnostics)
+			}
+
+			var delegations []string
+			diagnostics = mailbox.Delegations.ElementsAs(ctx, &delegations, false)
+			if diagnostics.HasError() {
+				t.Fatalf("Could not read delegations: %+v", diagnostics)
+			}
+
+			var senderDenyList []string
+			diagnostics = mailbox.SenderDenyList.ElementsAs(ctx, &senderDenyList, false)
+			if diagnostics.HasError() {
+				t.Fatalf("Could not read sender denylist: %+v", diagnostics)
+			}
+
+			assert.Equal(t, "Some Name", mailbox.Name.ValueString(), "Name")
+			assert.ElementsMatch(t, testCase.wantDelegations, delegations, "Delegations")
+			assert.ElementsMatch(t, testCase.wantDenyList, senderDenyList, "SenderDenyList")
+			assert.True(t, mailbox.Identities.IsNull(), "Identities")
+			assert.True(t, mailbox.Aliases.IsNull(), "Aliases")
+		})
+	}
+}
+
+func TestMailboxResource_Identity(t *testing.T) {
+	state := &simulator.State{
+		Mailboxes: []model.Mailbox{
+			{
+				LocalPart:  "test",
+				DomainName: "example.com",
+				Address:    "test@example.com",
+				Name:       "Some Name",
+			},
+		},
+	}
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	testResourceIdentity(t, server.URL, ResourceIdentityTestCase{
+		TypeName: "migadu_mailbox",
+		ImportID: "test@example.com",
+		Identity: map[string]string{
+			"local_part":  "test",
+			"domain_name": "example.com",
+		},
+		ChangedIdentity: map[string]string{
+			"local_part":  "test",
+			"domain_name": "example.org",
+		},
+	})
+}
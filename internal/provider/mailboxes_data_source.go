@@ -11,10 +11,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"slices"
+	"strings"
+	"sync"
 )
 
 var (
@@ -27,13 +32,22 @@ func NewMailboxesDataSource() datasource.DataSource {
 }
 
 type MailboxesDataSource struct {
-	migaduClient *client.MigaduClient
+	migaduClient          *client.MigaduClient
+	domainScope           DomainScope
+	identitiesConcurrency int
 }
 
 type MailboxesDataSourceModel struct {
-	ID         custom_types.DomainNameValue `tfsdk:"id"`
-	DomainName custom_types.DomainNameValue `tfsdk:"domain_name"`
-	Mailboxes  []MailboxModel               `tfsdk:"mailboxes"`
+	ID                custom_types.DomainNameValue `tfsdk:"id"`
+	DomainName        custom_types.DomainNameValue `tfsdk:"domain_name"`
+	IncludeIdentities types.Bool                   `tfsdk:"include_identities"`
+	LocalPartRegex    types.String                 `tfsdk:"local_part_regex"`
+	IsInternal        types.Bool                   `tfsdk:"is_internal"`
+	Expirable         types.Bool                   `tfsdk:"expirable"`
+	ExpiresBefore     types.String                 `tfsdk:"expires_before"`
+	MaySend           types.Bool                   `tfsdk:"may_send"`
+	MayReceive        types.Bool                   `tfsdk:"may_receive"`
+	Mailboxes         []MailboxModel               `tfsdk:"mailboxes"`
 }
 
 type MailboxModel struct {
@@ -64,6 +78,7 @@ type MailboxModel struct {
 	FooterPlainBody       types.String                      `tfsdk:"footer_plain_body"`
 	FooterHtmlBody        types.String                      `tfsdk:"footer_html_body"`
 	Delegations           custom_types.EmailAddressSetValue `tfsdk:"delegations"`
+	Identities            []IdentityModel                   `tfsdk:"identities"`
 }
 
 func (d *MailboxesDataSource) Metadata(_ context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -94,6 +109,19 @@ func (d *MailboxesDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"include_identities": schema.BoolAttribute{
+				Description:         "Whether the identities of each mailbox are fetched as well. This requires one additional request per mailbox of which at most 'identities_concurrency' of the provider configuration are made at the same time. Defaults to 'false'.",
+				MarkdownDescription: "Whether the identities of each mailbox are fetched as well. This requires one additional request per mailbox of which at most `identities_concurrency` of the provider configuration are made at the same time. Defaults to `false`.",
+				Required:            false,
+				Optional:            true,
+				Computed:            false,
+			},
+			"local_part_regex": regexFilterAttribute("Only return mailboxes whose local part matches this regular expression."),
+			"is_internal":      boolFilterAttribute("Only return mailboxes that are internal or not internal."),
+			"expirable":        boolFilterAttribute("Only return mailboxes that are expirable or not expirable."),
+			"expires_before":   expiresBeforeFilterAttribute("mailboxes"),
+			"may_send":         boolFilterAttribute("Only return mailboxes that are allowed or not allowed to send emails."),
+			"may_receive":      boolFilterAttribute("Only return mailboxes that are allowed or not allowed to receive emails."),
 			"mailboxes": schema.ListNestedAttribute{
 				Description:         "The configured mailboxes for the given 'domain_name'.",
 				MarkdownDescription: "The configured mailboxes for the given `domain_name`.",
@@ -314,6 +342,16 @@ func (d *MailboxesDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 								},
 							},
 						},
+						"identities": schema.ListNestedAttribute{
+							Description:         "The identities of this mailbox. Only available if 'include_identities' is set to 'true'.",
+							MarkdownDescription: "The identities of this mailbox. Only available if `include_identities` is set to `true`.",
+							Required:            false,
+							Optional:            false,
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: identityModelAttributes(),
+							},
+						},
 					},
 				},
 			},
@@ -326,12 +364,14 @@ func (d *MailboxesDataSource) Configure(_ context.Context, request datasource.Co
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.migaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.migaduClient = providerData.MigaduClient
+		d.domainScope = providerData.DomainScope
+		d.identitiesConcurrency = providerData.IdentitiesConcurrency
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -343,6 +383,17 @@ func (d *MailboxesDataSource) Read(ctx context.Context, request datasource.ReadR
 		return
 	}
 
+	response.Diagnostics.Append(d.domainScope.Diagnostics(path.Root("domain_name"), data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	localPartRegex := regexFilter(path.Root("local_part_regex"), data.LocalPartRegex, &response.Diagnostics)
+	expiresBefore := dateFilter(path.Root("expires_before"), data.ExpiresBefore, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	mailboxes, err := d.migaduClient.GetMailboxes(ctx, data.DomainName.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(MailboxReadError(err))
@@ -350,6 +401,15 @@ func (d *MailboxesDataSource) Read(ctx context.Context, request datasource.ReadR
 	}
 
 	for _, mailbox := range mailboxes.Mailboxes {
+		if !matchesRegex(localPartRegex, mailbox.LocalPart) ||
+			!matchesBool(data.IsInternal, mailbox.IsInternal) ||
+			!matchesBool(data.Expirable, mailbox.Expirable) ||
+			!matchesExpiresBefore(expiresBefore, mailbox.Expirable, mailbox.ExpiresOn) ||
+			!matchesBool(data.MaySend, mailbox.MaySend) ||
+			!matchesBool(data.MayReceive, mailbox.MayReceive) {
+			continue
+		}
+
 		senderDenyList, diags := custom_types.NewEmailAddressSetValueFrom(ctx, mailbox.SenderDenyList)
 		response.Diagnostics.Append(diags...)
 		if response.Diagnostics.HasError() {
@@ -406,7 +466,84 @@ func (d *MailboxesDataSource) Read(ctx context.Context, request datasource.ReadR
 		data.Mailboxes = append(data.Mailboxes, model)
 	}
 
+	if data.IncludeIdentities.ValueBool() {
+		localParts := make([]string, 0, len(data.Mailboxes))
+		for _, mailbox := range data.Mailboxes {
+			localParts = append(localParts, mailbox.LocalPart.ValueString())
+		}
+		identities, err := getMailboxIdentities(ctx, d.migaduClient, d.identitiesConcurrency, data.DomainName.ValueString(), localParts)
+		if err != nil {
+			response.Diagnostics.Append(IdentityReadError(err))
+			return
+		}
+		for index := range data.Mailboxes {
+			data.Mailboxes[index].Identities = make([]IdentityModel, 0, len(identities[index]))
+			for _, identity := range identities[index] {
+				data.Mailboxes[index].Identities = append(data.Mailboxes[index].Identities, newIdentityModel(&identity))
+			}
+		}
+	}
+
 	data.ID = data.DomainName
 
 	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
 }
+
+// defaultIdentitiesConcurrency is the maximum number of concurrent requests for the identities of mailboxes unless
+// the provider configuration sets 'identities_concurrency'.
+const defaultIdentitiesConcurrency = 4
+
+// getMailboxIdentities fetches the identities of the given mailboxes with at most the given number of concurrent
+// requests, or defaultIdentitiesConcurrency if the number is not positive. Requests that hit the rate limit of the
+// Migadu API are repeated by the RateLimitTransport of the client. The returned slice contains the identities of each
+// mailbox, sorted by their local part, in the same order as the given local parts. No further requests are started once
+// the context is canceled or one of the requests failed.
+func getMailboxIdentities(ctx context.Context, migaduClient *client.MigaduClient, concurrency int, domainName string, localParts []string) ([][]model.Identity, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]model.Identity, len(localParts))
+	if concurrency < 1 {
+		concurrency = defaultIdentitiesConcurrency
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var group sync.WaitGroup
+	var once sync.Once
+	var firstError error
+
+loop:
+	for index, localPart := range localParts {
+		select {
+		case <-ctx.Done():
+			break loop
+		case semaphore <- struct{}{}:
+		}
+
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			defer func() { <-semaphore }()
+
+			identities, err := migaduClient.GetIdentities(ctx, domainName, localPart)
+			if err != nil {
+				once.Do(func() {
+					firstError = err
+					cancel()
+				})
+				return
+			}
+			results[index] = slices.SortedFunc(slices.Values(identities.Identities), func(a, b model.Identity) int {
+				return strings.Compare(a.LocalPart, b.LocalPart)
+			})
+		}()
+	}
+	group.Wait()
+
+	if firstError != nil {
+		return nil, firstError
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -9,14 +9,21 @@ import (
 	"context"
 	"fmt"
 	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestMailboxesDataSource_Schema(t *testing.T) {
@@ -272,3 +279,175 @@ func TestMailboxesDataSource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestMailboxesDataSource_IncludeIdentities(t *testing.T) {
+	ctx := context.Background()
+	state := sandbox.State{}
+	for index := range 10 {
+		localPart := fmt.Sprintf("mailbox-%d", index)
+		state.Mailboxes = append(state.Mailboxes, model.Mailbox{
+			LocalPart:  localPart,
+			DomainName: "example.com",
+			Address:    localPart + "@example.com",
+		})
+		state.Identities = append(state.Identities, sandbox.Identity{
+			Mailbox: localPart,
+			Identity: model.Identity{
+				LocalPart:  localPart + "-identity",
+				DomainName: "example.com",
+				Address:    localPart + "-identity@example.com",
+			},
+		})
+	}
+
+	testCases := map[string]struct {
+		includeIdentities tftypes.Value
+		concurrency       tftypes.Value
+		failingMailbox    string
+		failures          int
+		wantRequests      int
+		wantConcurrency   int
+		wantIdentities    bool
+		want              string
+	}{
+		"excluded": {
+			includeIdentities: tftypes.NewValue(tftypes.Bool, nil),
+			concurrency:       tftypes.NewValue(tftypes.Number, nil),
+			wantRequests:      1,
+			wantConcurrency:   4,
+		},
+		"included": {
+			includeIdentities: tftypes.NewValue(tftypes.Bool, true),
+			concurrency:       tftypes.NewValue(tftypes.Number, nil),
+			wantRequests:      11,
+			wantConcurrency:   4,
+			wantIdentities:    true,
+		},
+		"configured-concurrency": {
+			includeIdentities: tftypes.NewValue(tftypes.Bool, true),
+			concurrency:       tftypes.NewValue(tftypes.Number, 2),
+			wantRequests:      11,
+			wantConcurrency:   2,
+			wantIdentities:    true,
+		},
+		"rate-limited-once": {
+			includeIdentities: tftypes.NewValue(tftypes.Bool, true),
+			concurrency:       tftypes.NewValue(tftypes.Number, nil),
+			failingMailbox:    "mailbox-0",
+			failures:          1,
+			wantRequests:      12,
+			wantConcurrency:   4,
+			wantIdentities:    true,
+		},
+		"rate-limited": {
+			includeIdentities: tftypes.NewValue(tftypes.Bool, true),
+			concurrency:       tftypes.NewValue(tftypes.Number, nil),
+			failingMailbox:    "mailbox-0",
+			failures:          10,
+			wantConcurrency:   4,
+			want:              "Error Reading Identity",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := sandbox.New(state)
+			var mutex sync.Mutex
+			var requests, failures, inFlight, maxInFlight int
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				mutex.Lock()
+				requests++
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mutex.Unlock()
+				defer func() {
+					mutex.Lock()
+					inFlight--
+					mutex.Unlock()
+				}()
+
+				if strings.HasSuffix(request.URL.Path, "/identities") {
+					time.Sleep(10 * time.Millisecond)
+				}
+				if testCase.failingMailbox != "" && strings.Contains(request.URL.Path, "/"+testCase.failingMailbox+"/") {
+					mutex.Lock()
+					failures++
+					failing := failures <= testCase.failures
+					mutex.Unlock()
+					if failing {
+						writer.Header().Set("Retry-After", "0")
+						writer.WriteHeader(http.StatusTooManyRequests)
+						return
+					}
+				}
+				api.ServeHTTP(writer, request)
+			}))
+			defer server.Close()
+
+			providerServer := configuredProviderServerWith(t, server.URL, map[string]tftypes.Value{
+				"identities_concurrency": testCase.concurrency,
+			})
+			schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatalf("GetProviderSchema error: %s", err)
+			}
+			dataSourceType := schemaResponse.DataSourceSchemas["migadu_mailboxes"].ValueType()
+			config := objectValue(dataSourceType, map[string]tftypes.Value{
+				"domain_name":        tftypes.NewValue(tftypes.String, "example.com"),
+				"include_identities": testCase.includeIdentities,
+			})
+			response, err := providerServer.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+				TypeName: "migadu_mailboxes",
+				Config:   dynamicValue(t, dataSourceType, config),
+			})
+			if err != nil {
+				t.Fatalf("ReadDataSource error: %s", err)
+			}
+			assertDiagnosticSummary(t, testCase.want, response.Diagnostics)
+			assert.LessOrEqual(t, maxInFlight, testCase.wantConcurrency, "concurrent requests")
+			if testCase.want != "" {
+				return
+			}
+			assert.Equal(t, testCase.wantRequests, requests, "requests")
+			if testCase.wantIdentities {
+				assert.Greater(t, maxInFlight, 1, "concurrent requests")
+			}
+
+			result, err := response.State.Unmarshal(dataSourceType)
+			if err != nil {
+				t.Fatalf("Could not read state: %s", err)
+			}
+			var attributes map[string]tftypes.Value
+			if err := result.As(&attributes); err != nil {
+				t.Fatalf("Could not read attributes: %s", err)
+			}
+			var mailboxes []tftypes.Value
+			if err := attributes["mailboxes"].As(&mailboxes); err != nil {
+				t.Fatalf("Could not read mailboxes: %s", err)
+			}
+			if !assert.Len(t, mailboxes, 10, "mailboxes") {
+				return
+			}
+			for index, mailbox := range mailboxes {
+				var mailboxAttributes map[string]tftypes.Value
+				if err := mailbox.As(&mailboxAttributes); err != nil {
+					t.Fatalf("Could not read mailbox: %s", err)
+				}
+				if !testCase.wantIdentities {
+					assert.True(t, mailboxAttributes["identities"].IsNull(), "identities")
+					continue
+				}
+				var identities []tftypes.Value
+				if err := mailboxAttributes["identities"].As(&identities); err != nil {
+					t.Fatalf("Could not read identities: %s", err)
+				}
+				if assert.Len(t, identities, 1, "identities") {
+					var identityAttributes map[string]tftypes.Value
+					if err := identities[0].As(&identityAttributes); err != nil {
+						t.Fatalf("Could not read identity: %s", err)
+					}
+					assert.Equal(t, tftypes.NewValue(tftypes.String, fmt.Sprintf("mailbox-%d-identity", index)), identityAttributes["local_part"], "local part")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"golang.org/x/net/idna"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	PolicySeverityError   = "error"
+	PolicySeverityWarning = "warning"
+)
+
+// Policy contains organizational rules that are evaluated against each planned resource.
+type Policy struct {
+	Rules []PolicyRule
+}
+
+// PolicyRule is a single rule of a policy. An empty list of resource types applies the rule to all resource types.
+type PolicyRule struct {
+	ResourceTypes []string
+	Severity      string
+	Check         PolicyCheck
+}
+
+// PolicyCheck returns all violations of a rule for a single planned resource. Both the plan and the configuration are
+// given as their top-level attribute values.
+type PolicyCheck interface {
+	Violations(resourceType string, plan map[string]tftypes.Value, config map[string]tftypes.Value) []PolicyViolation
+}
+
+// PolicyViolation describes why a planned resource does not follow a rule.
+type PolicyViolation struct {
+	Path    path.Path
+	Message string
+}
+
+// RequiredValueCheck requires a top-level attribute to have a specific value.
+type RequiredValueCheck struct {
+	Attribute string
+	Value     string
+}
+
+// ForbiddenDestinationDomainsCheck forbids destinations in any of the given normalized domains.
+type ForbiddenDestinationDomainsCheck struct {
+	Domains []string
+}
+
+// LocalPartPatternCheck requires all local parts to match a pattern.
+type LocalPartPatternCheck struct {
+	Pattern *regexp.Regexp
+}
+
+// MaxDestinationsCheck limits the number of destinations.
+type MaxDestinationsCheck struct {
+	Maximum int64
+}
+
+// newPolicy converts the policy block of the provider configuration into a Policy and adds an error for each invalid
+// or unknown rule.
+func newPolicy(ctx context.Context, model *MigaduPolicyModel, diagnostics *diag.Diagnostics) Policy {
+	policy := Policy{}
+	if model == nil {
+		return policy
+	}
+
+	for index, rule := range model.RequiredValues {
+		rulePath := path.Root("policy").AtName("required_value").AtListIndex(index)
+		if policyRuleUnknown(rulePath, diagnostics, rule.ResourceTypes, rule.Severity, rule.Attribute, rule.Value) {
+			continue
+		}
+		policy.Rules = append(policy.Rules, PolicyRule{
+			ResourceTypes: policyResourceTypes(ctx, rule.ResourceTypes, diagnostics),
+			Severity:      rule.Severity.ValueString(),
+			Check: RequiredValueCheck{
+				Attribute: rule.Attribute.ValueString(),
+				Value:     rule.Value.ValueString(),
+			},
+		})
+	}
+
+	for index, rule := range model.ForbiddenDestinationDomains {
+		rulePath := path.Root("policy").AtName("forbidden_destination_domains").AtListIndex(index)
+		if policyRuleUnknown(rulePath, diagnostics, rule.ResourceTypes, rule.Severity, rule.Domains) {
+			continue
+		}
+		var domains []custom_types.DomainNameValue
+		diagnostics.Append(rule.Domains.ElementsAs(ctx, &domains, false)...)
+		policy.Rules = append(policy.Rules, PolicyRule{
+			ResourceTypes: policyResourceTypes(ctx, rule.ResourceTypes, diagnostics),
+			Severity:      rule.Severity.ValueString(),
+			Check: ForbiddenDestinationDomainsCheck{
+				Domains: normalizeDomains(rulePath.AtName("domains"), domains, diagnostics),
+			},
+		})
+	}
+
+	for index, rule := range model.LocalPartPatterns {
+		rulePath := path.Root("policy").AtName("local_part_pattern").AtListIndex(index)
+		if policyRuleUnknown(rulePath, diagnostics, rule.ResourceTypes, rule.Severity, rule.Pattern) {
+			continue
+		}
+		pattern, err := regexp.Compile(rule.Pattern.ValueString())
+		if err != nil {
+			diagnostics.AddAttributeError(
+				rulePath.AtName("pattern"),
+				"Invalid Migadu Policy",
+				"The supplied pattern cannot be compiled into a regular expression: "+err.Error(),
+			)
+			continue
+		}
+		policy.Rules = append(policy.Rules, PolicyRule{
+			ResourceTypes: policyResourceTypes(ctx, rule.ResourceTypes, diagnostics),
+			Severity:      rule.Severity.ValueString(),
+			Check: LocalPartPatternCheck{
+				Pattern: pattern,
+			},
+		})
+	}
+
+	for index, rule := range model.MaxDestinations {
+		rulePath := path.Root("policy").AtName("max_destinations").AtListIndex(index)
+		if policyRuleUnknown(rulePath, diagnostics, rule.ResourceTypes, rule.Severity, rule.Maximum) {
+			continue
+		}
+		policy.Rules = append(policy.Rules, PolicyRule{
+			ResourceTypes: policyResourceTypes(ctx, rule.ResourceTypes, diagnostics),
+			Severity:      rule.Severity.ValueString(),
+			Check: MaxDestinationsCheck{
+				Maximum: rule.Maximum.ValueInt64(),
+			},
+		})
+	}
+
+	return policy
+}
+
+// policyRuleUnknown adds an error and returns true in case any of the given values of a rule is unknown.
+func policyRuleUnknown(rulePath path.Path, diagnostics *diag.Diagnostics, values ...attr.Value) bool {
+	for _, value := range values {
+		if value.IsUnknown() {
+			diagnostics.AddAttributeError(
+				rulePath,
+				"Unknown Migadu Policy",
+				"The provider cannot evaluate its policy as there is an unknown configuration value in one of its rules. "+
+					"Either target apply the source of the value first or set the value statically in the configuration.",
+			)
+			return true
+		}
+	}
+	return false
+}
+
+// policyResourceTypes returns the resource types of a rule.
+func policyResourceTypes(ctx context.Context, resourceTypes types.Set, diagnostics *diag.Diagnostics) []string {
+	var values []string
+	if !resourceTypes.IsNull() {
+		diagnostics.Append(resourceTypes.ElementsAs(ctx, &values, false)...)
+	}
+	return values
+}
+
+// planDiagnostics evaluates all rules of the policy against the planned resource of the given type. Deletions are
+// never checked.
+func (p Policy) planDiagnostics(resourceType string, request resource.ModifyPlanRequest) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	if len(p.Rules) == 0 || request.Plan.Raw.IsNull() || !request.Plan.Raw.IsKnown() {
+		return diagnostics
+	}
+
+	var plan, config map[string]tftypes.Value
+	if err := request.Plan.Raw.As(&plan); err != nil {
+		return diagnostics
+	}
+	if !request.Config.Raw.IsNull() && request.Config.Raw.IsKnown() {
+		_ = request.Config.Raw.As(&config)
+	}
+
+	for _, rule := range p.Rules {
+		if len(rule.ResourceTypes) > 0 && !slices.Contains(rule.ResourceTypes, resourceType) {
+			continue
+		}
+		for _, violation := range rule.Check.Violations(resourceType, plan, config) {
+			summary := "Policy Violation"
+			detail := violation.Message + "\n\nThis rule is part of the 'policy' of the provider configuration."
+			if rule.Severity == PolicySeverityWarning {
+				diagnostics.AddAttributeWarning(violation.Path, summary, detail)
+			} else {
+				diagnostics.AddAttributeError(violation.Path, summary, detail)
+			}
+		}
+	}
+	return diagnostics
+}
+
+func (c RequiredValueCheck) Violations(_ string, plan map[string]tftypes.Value, config map[string]tftypes.Value) []PolicyViolation {
+	value, ok := plan[c.Attribute]
+	if !ok {
+		return nil
+	}
+
+	actual := "null"
+	if !value.IsKnown() {
+		// values computed by the Migadu API are only violations if they are not configured at all
+		if configValue, ok := config[c.Attribute]; !ok || !configValue.IsNull() {
+			return nil
+		}
+		actual = "not set"
+	} else if !value.IsNull() {
+		actual = policyValueString(value)
+	}
+
+	if actual == c.Value {
+		return nil
+	}
+	return []PolicyViolation{{
+		Path:    path.Root(c.Attribute),
+		Message: fmt.Sprintf("The attribute '%s' must be '%s' but is '%s'.", c.Attribute, c.Value, actual),
+	}}
+}
+
+func (c ForbiddenDestinationDomainsCheck) Violations(_ string, plan map[string]tftypes.Value, _ map[string]tftypes.Value) []PolicyViolation {
+	var violations []PolicyViolation
+	for _, destination := range policyStrings(plan["destinations"]) {
+		at := strings.LastIndex(destination, "@")
+		if at < 0 {
+			continue
+		}
+		domain, err := idna.ToASCII(strings.ToLower(strings.TrimSpace(destination[at+1:])))
+		if err != nil {
+			continue
+		}
+		if slices.Contains(c.Domains, domain) {
+			violations = append(violations, PolicyViolation{
+				Path:    path.Root("destinations"),
+				Message: fmt.Sprintf("The destination '%s' uses the forbidden domain '%s'.", destination, destination[at+1:]),
+			})
+		}
+	}
+	return violations
+}
+
+func (c LocalPartPatternCheck) Violations(resourceType string, plan map[string]tftypes.Value, _ map[string]tftypes.Value) []PolicyViolation {
+	var violations []PolicyViolation
+	check := func(attributePath path.Path, localPart string) {
+		if !c.Pattern.MatchString(localPart) {
+			violations = append(violations, PolicyViolation{
+				Path:    attributePath,
+				Message: fmt.Sprintf("The local part '%s' does not match the pattern '%s'.", localPart, c.Pattern.String()),
+			})
+		}
+	}
+
+	switch resourceType {
+	case "migadu_alias":
+		for _, localPart := range policyStrings(plan["local_part"]) {
+			check(path.Root("local_part"), localPart)
+		}
+	case "migadu_identity":
+		for _, localPart := range policyStrings(plan["identity"]) {
+			check(path.Root("identity"), localPart)
+		}
+	case "migadu_mailbox":
+		for _, localPart := range policyStrings(plan["local_part"]) {
+			check(path.Root("local_part"), localPart)
+		}
+		for _, localPart := range policyStrings(plan["identities"]) {
+			check(path.Root("identities").AtMapKey(localPart), localPart)
+		}
+		for _, localPart := range policyStrings(plan["aliases"]) {
+			check(path.Root("aliases"), localPart)
+		}
+	}
+	return violations
+}
+
+func (c MaxDestinationsCheck) Violations(_ string, plan map[string]tftypes.Value, _ map[string]tftypes.Value) []PolicyViolation {
+	destinations := policyStrings(plan["destinations"])
+	if int64(len(destinations)) <= c.Maximum {
+		return nil
+	}
+	return []PolicyViolation{{
+		Path:    path.Root("destinations"),
+		Message: fmt.Sprintf("At most %d destinations are allowed but %d are configured.", c.Maximum, len(destinations)),
+	}}
+}
+
+// policyValueString converts a known primitive value into the string representation used in policies.
+func policyValueString(value tftypes.Value) string {
+	switch {
+	case value.Type().Is(tftypes.String):
+		var str string
+		_ = value.As(&str)
+		return str
+	case value.Type().Is(tftypes.Bool):
+		var boolean bool
+		_ = value.As(&boolean)
+		return strconv.FormatBool(boolean)
+	case value.Type().Is(tftypes.Number):
+		number := new(big.Float)
+		_ = value.As(&number)
+		return number.Text('f', -1)
+	default:
+		return value.String()
+	}
+}
+
+// policyStrings returns all known strings of a string value, the elements of a set or list of strings, or the keys of
+// a map in sorted order.
+func policyStrings(value tftypes.Value) []string {
+	if value.Type() == nil || !value.IsKnown() || value.IsNull() {
+		return nil
+	}
+
+	var strs []string
+	switch {
+	case value.Type().Is(tftypes.String):
+		var str string
+		_ = value.As(&str)
+		strs = append(strs, str)
+	case value.Type().Is(tftypes.Set{}), value.Type().Is(tftypes.List{}):
+		var elements []tftypes.Value
+		_ = value.As(&elements)
+		for _, element := range elements {
+			if element.IsKnown() && !element.IsNull() && element.Type().Is(tftypes.String) {
+				var str string
+				_ = element.As(&str)
+				strs = append(strs, str)
+			}
+		}
+	case value.Type().Is(tftypes.Map{}):
+		var elements map[string]tftypes.Value
+		_ = value.As(&elements)
+		for key := range elements {
+			strs = append(strs, key)
+		}
+	}
+	sort.Strings(strs)
+	return strs
+}
@@ -0,0 +1,355 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicy_Alias(t *testing.T) {
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		rules        map[string][]map[string]tftypes.Value
+		localPart    string
+		destinations []string
+		want         []tfprotov6.DiagnosticSeverity
+	}{
+		"no-policy": {
+			localPart:    "Some-1",
+			destinations: []string{"some@gmail.com", "other@example.com"},
+		},
+		"required-value": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute": tftypes.NewValue(tftypes.String, "is_internal"),
+					"value":     tftypes.NewValue(tftypes.String, "true"),
+				}},
+			},
+			localPart:    "some",
+			destinations: []string{"other@example.com"},
+			want:         []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError},
+		},
+		"required-value-warning": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute": tftypes.NewValue(tftypes.String, "is_internal"),
+					"value":     tftypes.NewValue(tftypes.String, "true"),
+					"severity":  tftypes.NewValue(tftypes.String, "warning"),
+				}},
+			},
+			localPart:    "some",
+			destinations: []string{"other@example.com"},
+			want:         []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityWarning},
+		},
+		"required-value-other-resource-type": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute":      tftypes.NewValue(tftypes.String, "is_internal"),
+					"value":          tftypes.NewValue(tftypes.String, "true"),
+					"resource_types": stringSet([]string{"migadu_mailbox"}),
+				}},
+			},
+			localPart:    "some",
+			destinations: []string{"other@example.com"},
+		},
+		"required-value-unknown-attribute": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute": tftypes.NewValue(tftypes.String, "spam_action"),
+					"value":     tftypes.NewValue(tftypes.String, "folder"),
+				}},
+			},
+			localPart:    "some",
+			destinations: []string{"other@example.com"},
+		},
+		"forbidden-destination-domains": {
+			rules: map[string][]map[string]tftypes.Value{
+				"forbidden_destination_domains": {{
+					"domains": stringSet([]string{"gmail.com", "hoß.de"}),
+				}},
+			},
+			localPart:    "some",
+			destinations: []string{"some@GMAIL.com", "other@xn--ho-hia.de", "another@example.com"},
+			want:         []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError, tfprotov6.DiagnosticSeverityError},
+		},
+		"local-part-pattern": {
+			rules: map[string][]map[string]tftypes.Value{
+				"local_part_pattern": {{
+					"pattern": tftypes.NewValue(tftypes.String, "^[a-z.]+$"),
+				}},
+			},
+			localPart:    "Some-1",
+			destinations: []string{"other@example.com"},
+			want:         []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError},
+		},
+		"local-part-pattern-match": {
+			rules: map[string][]map[string]tftypes.Value{
+				"local_part_pattern": {{
+					"pattern": tftypes.NewValue(tftypes.String, "^[a-z.]+$"),
+				}},
+			},
+			localPart:    "some.one",
+			destinations: []string{"other@example.com"},
+		},
+		"max-destinations": {
+			rules: map[string][]map[string]tftypes.Value{
+				"max_destinations": {{
+					"maximum": tftypes.NewValue(tftypes.Number, 1),
+				}},
+			},
+			localPart:    "some",
+			destinations: []string{"some@example.com", "other@example.com"},
+			want:         []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := planPolicySeverities(t, server.URL, testCase.rules, "migadu_alias", func(resourceType tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"local_part":   tftypes.NewValue(tftypes.String, testCase.localPart),
+					"domain_name":  tftypes.NewValue(tftypes.String, "example.com"),
+					"destinations": stringSet(testCase.destinations),
+					"is_internal":  tftypes.NewValue(tftypes.Bool, false),
+				}
+			})
+			assert.Equal(t, testCase.want, got, "severities")
+		})
+	}
+}
+
+func TestPolicy_Mailbox(t *testing.T) {
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		rules map[string][]map[string]tftypes.Value
+		want  []tfprotov6.DiagnosticSeverity
+	}{
+		"no-policy": {},
+		"required-value": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute": tftypes.NewValue(tftypes.String, "may_access_pop3"),
+					"value":     tftypes.NewValue(tftypes.String, "false"),
+				}},
+			},
+			want: []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError},
+		},
+		"required-value-computed-not-set": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute": tftypes.NewValue(tftypes.String, "spam_action"),
+					"value":     tftypes.NewValue(tftypes.String, "folder"),
+					"severity":  tftypes.NewValue(tftypes.String, "warning"),
+				}},
+			},
+			want: []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityWarning},
+		},
+		"required-value-matching-resource-type": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute":      tftypes.NewValue(tftypes.String, "may_access_pop3"),
+					"value":          tftypes.NewValue(tftypes.String, "false"),
+					"resource_types": stringSet([]string{"migadu_alias", "migadu_mailbox"}),
+				}},
+			},
+			want: []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError},
+		},
+		"required-value-other-resource-type": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute":      tftypes.NewValue(tftypes.String, "may_access_pop3"),
+					"value":          tftypes.NewValue(tftypes.String, "false"),
+					"resource_types": stringSet([]string{"migadu_identity"}),
+				}},
+			},
+		},
+		"required-value-satisfied": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute": tftypes.NewValue(tftypes.String, "may_access_imap"),
+					"value":     tftypes.NewValue(tftypes.String, "true"),
+				}},
+			},
+		},
+		"local-part-pattern": {
+			rules: map[string][]map[string]tftypes.Value{
+				"local_part_pattern": {{
+					"pattern": tftypes.NewValue(tftypes.String, "^[a-z.]+$"),
+				}},
+			},
+			// local part, identity, and alias of the mailbox are checked
+			want: []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError, tfprotov6.DiagnosticSeverityError, tfprotov6.DiagnosticSeverityError},
+		},
+		"local-part-pattern-match": {
+			rules: map[string][]map[string]tftypes.Value{
+				"local_part_pattern": {{
+					"pattern": tftypes.NewValue(tftypes.String, "^[a-zA-Z0-9_.-]+$"),
+				}},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := planPolicySeverities(t, server.URL, testCase.rules, "migadu_mailbox", func(resourceType tftypes.Object) map[string]tftypes.Value {
+				identitiesType := resourceType.AttributeTypes["identities"].(tftypes.Map)
+				return map[string]tftypes.Value{
+					"local_part":      tftypes.NewValue(tftypes.String, "Some-1"),
+					"domain_name":     tftypes.NewValue(tftypes.String, "example.com"),
+					"name":            tftypes.NewValue(tftypes.String, "Some Name"),
+					"password":        tftypes.NewValue(tftypes.String, "secret"),
+					"may_access_imap": tftypes.NewValue(tftypes.Bool, true),
+					"may_access_pop3": tftypes.NewValue(tftypes.Bool, true),
+					"identities": tftypes.NewValue(identitiesType, map[string]tftypes.Value{
+						"Other_2": objectValue(identitiesType.ElementType, map[string]tftypes.Value{
+							"name": tftypes.NewValue(tftypes.String, "Other Name"),
+						}),
+					}),
+					"aliases": stringSet([]string{"some.one", "Another-3"}),
+				}
+			})
+			assert.Equal(t, testCase.want, got, "severities")
+		})
+	}
+}
+
+func TestPolicy_Identity(t *testing.T) {
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		rules map[string][]map[string]tftypes.Value
+		want  []tfprotov6.DiagnosticSeverity
+	}{
+		"no-policy": {},
+		"required-value": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute": tftypes.NewValue(tftypes.String, "password_use"),
+					"value":     tftypes.NewValue(tftypes.String, "mailbox"),
+				}},
+			},
+			want: []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError},
+		},
+		"required-value-matching-resource-type": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute":      tftypes.NewValue(tftypes.String, "may_send"),
+					"value":          tftypes.NewValue(tftypes.String, "false"),
+					"resource_types": stringSet([]string{"migadu_identity"}),
+					"severity":       tftypes.NewValue(tftypes.String, "warning"),
+				}},
+			},
+			want: []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityWarning},
+		},
+		"required-value-other-resource-type": {
+			rules: map[string][]map[string]tftypes.Value{
+				"required_value": {{
+					"attribute":      tftypes.NewValue(tftypes.String, "may_send"),
+					"value":          tftypes.NewValue(tftypes.String, "false"),
+					"resource_types": stringSet([]string{"migadu_mailbox"}),
+				}},
+			},
+		},
+		"local-part-pattern": {
+			rules: map[string][]map[string]tftypes.Value{
+				"local_part_pattern": {{
+					"pattern": tftypes.NewValue(tftypes.String, "^[a-z]+$"),
+				}},
+			},
+			// only the identity is checked, the mailbox is checked by its own resource
+			want: []tfprotov6.DiagnosticSeverity{tfprotov6.DiagnosticSeverityError},
+		},
+		"local-part-pattern-match": {
+			rules: map[string][]map[string]tftypes.Value{
+				"local_part_pattern": {{
+					"pattern": tftypes.NewValue(tftypes.String, "^[a-zA-Z0-9_.-]+$"),
+				}},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := planPolicySeverities(t, server.URL, testCase.rules, "migadu_identity", func(_ tftypes.Object) map[string]tftypes.Value {
+				return map[string]tftypes.Value{
+					"local_part":   tftypes.NewValue(tftypes.String, "some"),
+					"domain_name":  tftypes.NewValue(tftypes.String, "example.com"),
+					"identity":     tftypes.NewValue(tftypes.String, "Other-1"),
+					"name":         tftypes.NewValue(tftypes.String, "Other Name"),
+					"may_send":     tftypes.NewValue(tftypes.Bool, true),
+					"password_use": tftypes.NewValue(tftypes.String, "custom"),
+					"password":     tftypes.NewValue(tftypes.String, "secret"),
+				}
+			})
+			assert.Equal(t, testCase.want, got, "severities")
+		})
+	}
+}
+
+// planPolicySeverities plans the creation of a resource of the given type with the given policy rules and returns the
+// severities of all reported policy violations. All attributes not returned by the given function are null.
+func planPolicySeverities(t *testing.T, endpoint string, rules map[string][]map[string]tftypes.Value, typeName string, attributes func(resourceType tftypes.Object) map[string]tftypes.Value) []tfprotov6.DiagnosticSeverity {
+	ctx := context.Background()
+	providerServer := configuredProviderServerWith(t, endpoint, map[string]tftypes.Value{
+		"policy": policyValue(t, endpoint, rules),
+	})
+	schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+
+	resourceType := schemaResponse.ResourceSchemas[typeName].ValueType().(tftypes.Object)
+	proposed := objectValue(resourceType, attributes(resourceType))
+	response, err := providerServer.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       dynamicValue(t, resourceType, tftypes.NewValue(resourceType, nil)),
+		ProposedNewState: dynamicValue(t, resourceType, proposed),
+		Config:           dynamicValue(t, resourceType, proposed),
+	})
+	if err != nil {
+		t.Fatalf("PlanResourceChange error: %s", err)
+	}
+
+	var got []tfprotov6.DiagnosticSeverity
+	for _, diagnostic := range response.Diagnostics {
+		assert.Equal(t, "Policy Violation", diagnostic.Summary, "summary (detail: %s)", diagnostic.Detail)
+		got = append(got, diagnostic.Severity)
+	}
+	return got
+}
+
+// policyValue creates the policy block of the provider configuration with the given rules. All other attributes of
+// each rule are null.
+func policyValue(t *testing.T, endpoint string, rules map[string][]map[string]tftypes.Value) tftypes.Value {
+	schemaResponse, err := configuredProviderServer(t, endpoint).GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+
+	policyType := schemaResponse.Provider.ValueType().(tftypes.Object).AttributeTypes["policy"].(tftypes.Object)
+	if rules == nil {
+		return tftypes.NewValue(policyType, nil)
+	}
+
+	blocks := make(map[string]tftypes.Value, len(policyType.AttributeTypes))
+	for name, blockType := range policyType.AttributeTypes {
+		ruleType := blockType.(tftypes.List).ElementType
+		values := make([]tftypes.Value, 0, len(rules[name]))
+		for _, rule := range rules[name] {
+			values = append(values, objectValue(ruleType, rule))
+		}
+		blocks[name] = tftypes.NewValue(blockType, values)
+	}
+	return tftypes.NewValue(policyType, blocks)
+}
@@ -8,38 +8,112 @@ package provider
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 var (
-	_ provider.Provider = (*MigaduProvider)(nil)
+	_ provider.Provider                  = (*MigaduProvider)(nil)
+	_ provider.ProviderWithListResources = (*MigaduProvider)(nil)
 )
 
-type MigaduProvider struct{}
+type MigaduProvider struct {
+	// version is the version of the provider, set during the release build.
+	version string
+}
 
 type MigaduProviderModel struct {
 	Endpoint types.String `tfsdk:"endpoint"`
 	Token    types.String `tfsdk:"token"`
 	Username types.String `tfsdk:"username"`
 	Timeout  types.Int64  `tfsdk:"timeout"`
+
+	IdentitiesConcurrency types.Int64 `tfsdk:"identities_concurrency"`
+
+	TokenFile       types.String `tfsdk:"token_file"`
+	TokenCommand    types.String `tfsdk:"token_command"`
+	Profile         types.String `tfsdk:"profile"`
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+
+	ValidateCredentials types.Bool `tfsdk:"validate_credentials"`
+	ReadOnly            types.Bool `tfsdk:"read_only"`
+
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+
+	AuditLogPath types.String `tfsdk:"audit_log_path"`
+	LogHTTP      types.Bool   `tfsdk:"log_http"`
+	ReadCache    types.Bool   `tfsdk:"read_cache"`
+
+	AllowedDomains types.Set `tfsdk:"allowed_domains"`
+	DeniedDomains  types.Set `tfsdk:"denied_domains"`
+
+	Policy *MigaduPolicyModel `tfsdk:"policy"`
+}
+
+type MigaduPolicyModel struct {
+	RequiredValues              []MigaduRequiredValueRuleModel               `tfsdk:"required_value"`
+	ForbiddenDestinationDomains []MigaduForbiddenDestinationDomainsRuleModel `tfsdk:"forbidden_destination_domains"`
+	LocalPartPatterns           []MigaduLocalPartPatternRuleModel            `tfsdk:"local_part_pattern"`
+	MaxDestinations             []MigaduMaxDestinationsRuleModel             `tfsdk:"max_destinations"`
+}
+
+type MigaduRequiredValueRuleModel struct {
+	ResourceTypes types.Set    `tfsdk:"resource_types"`
+	Severity      types.String `tfsdk:"severity"`
+	Attribute     types.String `tfsdk:"attribute"`
+	Value         types.String `tfsdk:"value"`
+}
+
+type MigaduForbiddenDestinationDomainsRuleModel struct {
+	ResourceTypes types.Set    `tfsdk:"resource_types"`
+	Severity      types.String `tfsdk:"severity"`
+	Domains       types.Set    `tfsdk:"domains"`
+}
+
+type MigaduLocalPartPatternRuleModel struct {
+	ResourceTypes types.Set    `tfsdk:"resource_types"`
+	Severity      types.String `tfsdk:"severity"`
+	Pattern       types.String `tfsdk:"pattern"`
+}
+
+type MigaduMaxDestinationsRuleModel struct {
+	ResourceTypes types.Set    `tfsdk:"resource_types"`
+	Severity      types.String `tfsdk:"severity"`
+	Maximum       types.Int64  `tfsdk:"maximum"`
 }
 
-func New() provider.Provider {
-	return &MigaduProvider{}
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &MigaduProvider{
+			version: version,
+		}
+	}
 }
 
 func (p *MigaduProvider) Metadata(_ context.Context, _ provider.MetadataRequest, response *provider.MetadataResponse) {
 	response.TypeName = "migadu"
+	response.Version = p.version
 }
 
 func (p *MigaduProvider) Schema(_ context.Context, _ provider.SchemaRequest, response *provider.SchemaResponse) {
@@ -48,13 +122,13 @@ func (p *MigaduProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 		MarkdownDescription: "Provider for the [Migadu](https://www.migadu.com/api/) API. Requires Terraform 1.0 or later.",
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				Description:         "The API endpoint to use. Can be specified with the 'MIGADU_ENDPOINT' environment variable. Defaults to 'https://api.migadu.com/v1/'. Take a look at https://www.migadu.com/api/#api-requests for more information.",
-				MarkdownDescription: "The API endpoint to use. Can be specified with the `MIGADU_ENDPOINT` environment variable. Defaults to `https://api.migadu.com/v1/`. Take a look at https://www.migadu.com/api/#api-requests for more information.",
+				Description:         "The API endpoint to use. Can be specified with the 'MIGADU_ENDPOINT' environment variable. Defaults to 'https://api.migadu.com/v1/'. Take a look at https://www.migadu.com/api/#api-requests for more information. Use 'memory://' to work against an in-process sandbox that keeps its state for the lifetime of the provider process, or 'file://state.json' to keep the sandbox state in a JSON file. Terraform starts a new provider process for plan and for apply, therefore the state of 'memory://' does not carry over from plan to apply or between runs. Use 'file://' for anything beyond a single Terraform operation. Sandboxes do not require a username or token.",
+				MarkdownDescription: "The API endpoint to use. Can be specified with the `MIGADU_ENDPOINT` environment variable. Defaults to `https://api.migadu.com/v1/`. Take a look at https://www.migadu.com/api/#api-requests for more information. Use `memory://` to work against an in-process sandbox that keeps its state for the lifetime of the provider process, or `file://state.json` to keep the sandbox state in a JSON file. Terraform starts a new provider process for plan and for apply, therefore the state of `memory://` does not carry over from plan to apply or between runs. Use `file://` for anything beyond a single Terraform operation. Sandboxes do not require a username or token.",
 				Optional:            true,
 			},
 			"token": schema.StringAttribute{
-				Description:         "The API key to use. Can be specified with the 'MIGADU_TOKEN' environment variable. Take a look at https://www.migadu.com/api/#api-keys for more information.",
-				MarkdownDescription: "The API key to use. Can be specified with the `MIGADU_TOKEN` environment variable. Take a look at https://www.migadu.com/api/#api-keys for more information.",
+				Description:         "The API key to use. Can be specified with the 'MIGADU_TOKEN' environment variable. Take a look at https://www.migadu.com/api/#api-keys for more information. Credentials are resolved in the following order, using the first source that provides a value: 'username' and 'token', 'token_file', or 'token_command' of the provider configuration, the 'profile' of the provider configuration, the 'MIGADU_USERNAME' and 'MIGADU_TOKEN', 'MIGADU_TOKEN_FILE', or 'MIGADU_TOKEN_COMMAND' environment variables, the 'MIGADU_PROFILE' environment variable, and finally the 'default' profile of the shared credentials file.",
+				MarkdownDescription: "The API key to use. Can be specified with the `MIGADU_TOKEN` environment variable. Take a look at https://www.migadu.com/api/#api-keys for more information. Credentials are resolved in the following order, using the first source that provides a value: `username` and `token`, `token_file`, or `token_command` of the provider configuration, the `profile` of the provider configuration, the `MIGADU_USERNAME` and `MIGADU_TOKEN`, `MIGADU_TOKEN_FILE`, or `MIGADU_TOKEN_COMMAND` environment variables, the `MIGADU_PROFILE` environment variable, and finally the `default` profile of the shared credentials file.",
 				Optional:            true,
 				Sensitive:           true,
 			},
@@ -64,13 +138,186 @@ func (p *MigaduProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"token_file": schema.StringAttribute{
+				Description:         "The path of a file that contains the API key to use, e.g. a Kubernetes or Docker secret. Surrounding whitespace is ignored. Can be specified with the 'MIGADU_TOKEN_FILE' environment variable.",
+				MarkdownDescription: "The path of a file that contains the API key to use, e.g. a Kubernetes or Docker secret. Surrounding whitespace is ignored. Can be specified with the `MIGADU_TOKEN_FILE` environment variable.",
+				Optional:            true,
+			},
+			"token_command": schema.StringAttribute{
+				Description:         "A command that prints the API key to use, e.g. 'pass show migadu'. The command is executed with 'sh -c' ('cmd /C' on Windows) while configuring the provider and only if no earlier source provides a token. Can be specified with the 'MIGADU_TOKEN_COMMAND' environment variable.",
+				MarkdownDescription: "A command that prints the API key to use, e.g. `pass show migadu`. The command is executed with `sh -c` (`cmd /C` on Windows) while configuring the provider and only if no earlier source provides a token. Can be specified with the `MIGADU_TOKEN_COMMAND` environment variable.",
+				Optional:            true,
+			},
+			"profile": schema.StringAttribute{
+				Description:         "The profile of the shared credentials file to read the username and token from. Can be specified with the 'MIGADU_PROFILE' environment variable. The 'default' profile is used if no other source provides credentials.",
+				MarkdownDescription: "The profile of the shared credentials file to read the username and token from. Can be specified with the `MIGADU_PROFILE` environment variable. The `default` profile is used if no other source provides credentials.",
+				Optional:            true,
+			},
+			"credentials_file": schema.StringAttribute{
+				Description:         "The path of the shared credentials file. The file contains one section per profile with 'username' and 'token' keys, e.g. '[customer-a]'. Can be specified with the 'MIGADU_CREDENTIALS_FILE' environment variable. Defaults to '~/.config/migadu/credentials'.",
+				MarkdownDescription: "The path of the shared credentials file. The file contains one section per profile with `username` and `token` keys, e.g. `[customer-a]`. Can be specified with the `MIGADU_CREDENTIALS_FILE` environment variable. Defaults to `~/.config/migadu/credentials`.",
+				Optional:            true,
+			},
+			"validate_credentials": schema.BoolAttribute{
+				Description:         "Whether the provider makes a single authenticated call to the Migadu API while it is configured. The call must list the domains of the user, so mismatched usernames and tokens, tokens without access, and endpoints that are unreachable or not the Migadu API are then reported once instead of by every resource and data source. The endpoint must use https unless it points to the local machine. Sandbox endpoints are never validated. Can be specified with the 'MIGADU_VALIDATE_CREDENTIALS' environment variable. Defaults to 'false'.",
+				MarkdownDescription: "Whether the provider makes a single authenticated call to the Migadu API while it is configured. The call must list the domains of the user, so mismatched usernames and tokens, tokens without access, and endpoints that are unreachable or not the Migadu API are then reported once instead of by every resource and data source. The endpoint must use https unless it points to the local machine. Sandbox endpoints are never validated. Can be specified with the `MIGADU_VALIDATE_CREDENTIALS` environment variable. Defaults to `false`.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Description:         "The URL of the proxy to use for all requests to the Migadu API, e.g. 'http://proxy.example.com:3128'. Can be specified with the 'MIGADU_PROXY_URL' environment variable. Defaults to the proxy configured by the 'HTTPS_PROXY', 'HTTP_PROXY', and 'NO_PROXY' environment variables.",
+				MarkdownDescription: "The URL of the proxy to use for all requests to the Migadu API, e.g. `http://proxy.example.com:3128`. Can be specified with the `MIGADU_PROXY_URL` environment variable. Defaults to the proxy configured by the `HTTPS_PROXY`, `HTTP_PROXY`, and `NO_PROXY` environment variables.",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description:         "The path of a file containing PEM encoded CA certificates that are trusted in addition to the certificates of the operating system, e.g. the CA of an inspecting proxy. Can be specified with the 'MIGADU_CA_CERT_FILE' environment variable.",
+				MarkdownDescription: "The path of a file containing PEM encoded CA certificates that are trusted in addition to the certificates of the operating system, e.g. the CA of an inspecting proxy. Can be specified with the `MIGADU_CA_CERT_FILE` environment variable.",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description:         "PEM encoded CA certificates that are trusted in addition to the certificates of the operating system. Can be combined with 'ca_cert_file'. Can be specified with the 'MIGADU_CA_CERT_PEM' environment variable.",
+				MarkdownDescription: "PEM encoded CA certificates that are trusted in addition to the certificates of the operating system. Can be combined with `ca_cert_file`. Can be specified with the `MIGADU_CA_CERT_PEM` environment variable.",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description:         "Whether the TLS certificate of the Migadu API is not verified. Only use this for local test stand-ins of the Migadu API. Can be specified with the 'MIGADU_INSECURE_SKIP_VERIFY' environment variable. Defaults to 'false'.",
+				MarkdownDescription: "Whether the TLS certificate of the Migadu API is not verified. Only use this for local test stand-ins of the Migadu API. Can be specified with the `MIGADU_INSECURE_SKIP_VERIFY` environment variable. Defaults to `false`.",
+				Optional:            true,
+			},
 			"timeout": schema.Int64Attribute{
 				Description:         "The timeout to apply for HTTP requests in seconds. Can be specified with the 'MIGADU_TIMEOUT' environment variable. Defaults to '10'.",
 				MarkdownDescription: "The timeout to apply for HTTP requests in seconds. Can be specified with the `MIGADU_TIMEOUT` environment variable. Defaults to `10`.",
 				Optional:            true,
 			},
+			"identities_concurrency": schema.Int64Attribute{
+				Description:         "The maximum number of concurrent requests when the identities of several mailboxes are fetched, e.g. by the 'include_identities' option of the 'migadu_mailboxes' data source. Read requests that hit the rate limit of the Migadu API are repeated up to 3 times after waiting for the duration of its 'Retry-After' header. Can be specified with the 'MIGADU_IDENTITIES_CONCURRENCY' environment variable. Defaults to '4'.",
+				MarkdownDescription: "The maximum number of concurrent requests when the identities of several mailboxes are fetched, e.g. by the `include_identities` option of the `migadu_mailboxes` data source. Read requests that hit the rate limit of the Migadu API are repeated up to 3 times after waiting for the duration of its `Retry-After` header. Can be specified with the `MIGADU_IDENTITIES_CONCURRENCY` environment variable. Defaults to `4`.",
+				Optional:            true,
+			},
+			"allowed_domains": schema.SetAttribute{
+				Description:         "The domains that resources, data sources, and list resources are allowed to use. Plans using any other domain fail with an error. International domain names can be given in their unicode or punycode form. Can be specified as a comma separated list with the 'MIGADU_ALLOWED_DOMAINS' environment variable. Defaults to allowing all domains.",
+				MarkdownDescription: "The domains that resources, data sources, and list resources are allowed to use. Plans using any other domain fail with an error. International domain names can be given in their unicode or punycode form. Can be specified as a comma separated list with the `MIGADU_ALLOWED_DOMAINS` environment variable. Defaults to allowing all domains.",
+				Optional:            true,
+				ElementType:         custom_types.DomainNameType{},
+			},
+			"denied_domains": schema.SetAttribute{
+				Description:         "The domains that resources, data sources, and list resources are not allowed to use, even if they are part of 'allowed_domains'. International domain names can be given in their unicode or punycode form. Can be specified as a comma separated list with the 'MIGADU_DENIED_DOMAINS' environment variable.",
+				MarkdownDescription: "The domains that resources, data sources, and list resources are not allowed to use, even if they are part of `allowed_domains`. International domain names can be given in their unicode or punycode form. Can be specified as a comma separated list with the `MIGADU_DENIED_DOMAINS` environment variable.",
+				Optional:            true,
+				ElementType:         custom_types.DomainNameType{},
+			},
+			"audit_log_path": schema.StringAttribute{
+				Description:         "The path of a file that records every create, update, and delete of a resource as a line of JSON. Each line contains a timestamp, the operating system user running the provider, the resource type, ID, and operation, the values before and after the change with passwords and footers masked, and the status code of the API call. Identities and aliases managed through a mailbox are recorded with their own resource type and ID. Can be specified with the 'MIGADU_AUDIT_LOG_PATH' environment variable. Defaults to not writing an audit log.",
+				MarkdownDescription: "The path of a file that records every create, update, and delete of a resource as a line of JSON. Each line contains a timestamp, the operating system user running the provider, the resource type, ID, and operation, the values before and after the change with passwords and footers masked, and the status code of the API call. Identities and aliases managed through a mailbox are recorded with their own resource type and ID. Can be specified with the `MIGADU_AUDIT_LOG_PATH` environment variable. Defaults to not writing an audit log.",
+				Optional:            true,
+			},
+			"read_cache": schema.BoolAttribute{
+				Description:         "Whether aliases, mailboxes, and rewrite rules are read once per domain and then served from memory. The first read of a domain fetches all objects of that kind with a single request, which replaces one request per resource during refreshes of large domains. Any change to a resource of a domain discards the cached objects of that domain. Can be specified with the 'MIGADU_READ_CACHE' environment variable. Defaults to 'false'.",
+				MarkdownDescription: "Whether aliases, mailboxes, and rewrite rules are read once per domain and then served from memory. The first read of a domain fetches all objects of that kind with a single request, which replaces one request per resource during refreshes of large domains. Any change to a resource of a domain discards the cached objects of that domain. Can be specified with the `MIGADU_READ_CACHE` environment variable. Defaults to `false`.",
+				Optional:            true,
+			},
+			"log_http": schema.BoolAttribute{
+				Description:         "Whether every HTTP exchange with the Migadu API is logged at TRACE level, including method, URL, status, latency, and the request and response bodies. Passwords, the username, and the token are masked. Set 'TF_LOG_PROVIDER=TRACE' to see the logs. Can be specified with the 'MIGADU_LOG_HTTP' environment variable. Defaults to 'false'.",
+				MarkdownDescription: "Whether every HTTP exchange with the Migadu API is logged at TRACE level, including method, URL, status, latency, and the request and response bodies. Passwords, the username, and the token are masked. Set `TF_LOG_PROVIDER=TRACE` to see the logs. Can be specified with the `MIGADU_LOG_HTTP` environment variable. Defaults to `false`.",
+				Optional:            true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description:         "Whether the provider refuses to create, update, or delete any resources. Plans that would change a resource fail with an error while data sources and refreshes keep working. Can be specified with the 'MIGADU_READ_ONLY' environment variable. Defaults to 'false'.",
+				MarkdownDescription: "Whether the provider refuses to create, update, or delete any resources. Plans that would change a resource fail with an error while data sources and refreshes keep working. Can be specified with the `MIGADU_READ_ONLY` environment variable. Defaults to `false`.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"policy": schema.SingleNestedBlock{
+				Description:         "Organizational rules that are evaluated against every planned resource. Violations are reported as errors or warnings depending on the severity of each rule.",
+				MarkdownDescription: "Organizational rules that are evaluated against every planned resource. Violations are reported as errors or warnings depending on the severity of each rule.",
+				Blocks: map[string]schema.Block{
+					"required_value": schema.ListNestedBlock{
+						Description:         "Requires a top-level attribute to have a specific value, e.g. 'spam_action' must be 'folder'. Booleans and numbers are compared by their string representation.",
+						MarkdownDescription: "Requires a top-level attribute to have a specific value, e.g. `spam_action` must be `folder`. Booleans and numbers are compared by their string representation.",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: policyRuleAttributes(map[string]schema.Attribute{
+								"attribute": schema.StringAttribute{
+									Description:         "The name of the attribute to check. Resources without this attribute are ignored.",
+									MarkdownDescription: "The name of the attribute to check. Resources without this attribute are ignored.",
+									Required:            true,
+								},
+								"value": schema.StringAttribute{
+									Description:         "The required value of the attribute.",
+									MarkdownDescription: "The required value of the attribute.",
+									Required:            true,
+								},
+							}),
+						},
+					},
+					"forbidden_destination_domains": schema.ListNestedBlock{
+						Description:         "Forbids destinations in any of the given domains, e.g. to prevent forwarding to free-mail providers.",
+						MarkdownDescription: "Forbids destinations in any of the given domains, e.g. to prevent forwarding to free-mail providers.",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: policyRuleAttributes(map[string]schema.Attribute{
+								"domains": schema.SetAttribute{
+									Description:         "The forbidden domains. International domain names can be given in their unicode or punycode form.",
+									MarkdownDescription: "The forbidden domains. International domain names can be given in their unicode or punycode form.",
+									Required:            true,
+									ElementType:         custom_types.DomainNameType{},
+								},
+							}),
+						},
+					},
+					"local_part_pattern": schema.ListNestedBlock{
+						Description:         "Requires all local parts to match a regular expression. This includes the local parts of aliases, identities, and mailboxes as well as the identities and aliases of mailboxes.",
+						MarkdownDescription: "Requires all local parts to match a regular expression. This includes the local parts of aliases, identities, and mailboxes as well as the identities and aliases of mailboxes.",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: policyRuleAttributes(map[string]schema.Attribute{
+								"pattern": schema.StringAttribute{
+									Description:         "The regular expression in RE2 syntax, e.g. '^[a-z.]+$'.",
+									MarkdownDescription: "The regular expression in [RE2 syntax](https://github.com/google/re2/wiki/Syntax), e.g. `^[a-z.]+$`.",
+									Required:            true,
+								},
+							}),
+						},
+					},
+					"max_destinations": schema.ListNestedBlock{
+						Description:         "Limits the number of destinations of aliases and rewrite rules.",
+						MarkdownDescription: "Limits the number of destinations of aliases and rewrite rules.",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: policyRuleAttributes(map[string]schema.Attribute{
+								"maximum": schema.Int64Attribute{
+									Description:         "The maximum number of destinations.",
+									MarkdownDescription: "The maximum number of destinations.",
+									Required:            true,
+									Validators: []validator.Int64{
+										int64validator.AtLeast(1),
+									},
+								},
+							}),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// policyRuleAttributes adds the attributes shared by all policy rules to the given attributes.
+func policyRuleAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["resource_types"] = schema.SetAttribute{
+		Description:         "The resource types the rule applies to, e.g. 'migadu_alias'. Defaults to all resource types.",
+		MarkdownDescription: "The resource types the rule applies to, e.g. `migadu_alias`. Defaults to all resource types.",
+		Optional:            true,
+		ElementType:         types.StringType,
+		Validators: []validator.Set{
+			setvalidator.ValueStringsAre(stringvalidator.OneOf("migadu_alias", "migadu_identity", "migadu_mailbox", "migadu_rewrite_rule")),
+		},
+	}
+	attributes["severity"] = schema.StringAttribute{
+		Description:         "Whether violations are reported as 'error' or 'warning'. Defaults to 'error'.",
+		MarkdownDescription: "Whether violations are reported as `error` or `warning`. Defaults to `error`.",
+		Optional:            true,
+		Validators: []validator.String{
+			stringvalidator.OneOf(PolicySeverityError, PolicySeverityWarning),
 		},
 	}
+	return attributes
 }
 
 func (p *MigaduProvider) Configure(ctx context.Context, request provider.ConfigureRequest, response *provider.ConfigureResponse) {
@@ -82,6 +329,16 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 		return
 	}
 
+	// clients supporting deferred actions can skip all resources and data sources until the configuration is known,
+	// e.g. because the token is read from a resource created in the same run
+	if request.ClientCapabilities.DeferralAllowed && !request.Config.Raw.IsFullyKnown() {
+		tflog.Info(ctx, "Deferring Migadu client configuration because of unknown configuration values")
+		response.Deferred = &provider.Deferred{
+			Reason: provider.DeferredReasonProviderConfigUnknown,
+		}
+		return
+	}
+
 	if config.Endpoint.IsUnknown() {
 		response.Diagnostics.AddAttributeError(
 			path.Root("endpoint"),
@@ -109,6 +366,87 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 		)
 	}
 
+	if config.TokenFile.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("token_file"),
+			"Unknown Migadu API Token File",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the Migadu API token file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_TOKEN_FILE environment variable.",
+		)
+	}
+
+	if config.TokenCommand.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("token_command"),
+			"Unknown Migadu API Token Command",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the Migadu API token command. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_TOKEN_COMMAND environment variable.",
+		)
+	}
+
+	if config.Profile.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unknown Migadu Credentials Profile",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the credentials profile. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_PROFILE environment variable.",
+		)
+	}
+
+	if config.CredentialsFile.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("credentials_file"),
+			"Unknown Migadu Credentials File",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the shared credentials file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_CREDENTIALS_FILE environment variable.",
+		)
+	}
+
+	if config.ValidateCredentials.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("validate_credentials"),
+			"Unknown Migadu Credentials Validation",
+			"The provider cannot determine whether to validate its credentials as there is an unknown configuration value for the credentials validation. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_VALIDATE_CREDENTIALS environment variable.",
+		)
+	}
+
+	if config.ProxyURL.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("proxy_url"),
+			"Unknown Migadu API Proxy URL",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the proxy URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_PROXY_URL environment variable.",
+		)
+	}
+
+	if config.CACertFile.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_file"),
+			"Unknown Migadu API CA Certificate File",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the CA certificate file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_CA_CERT_FILE environment variable.",
+		)
+	}
+
+	if config.CACertPEM.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_pem"),
+			"Unknown Migadu API CA Certificate",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the CA certificate. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_CA_CERT_PEM environment variable.",
+		)
+	}
+
+	if config.InsecureSkipVerify.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("insecure_skip_verify"),
+			"Unknown Migadu API TLS Verification",
+			"The provider cannot create the Migadu API client as there is an unknown configuration value for the TLS verification. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_INSECURE_SKIP_VERIFY environment variable.",
+		)
+	}
+
 	if config.Timeout.IsUnknown() {
 		response.Diagnostics.AddAttributeError(
 			path.Root("timeout"),
@@ -118,31 +456,153 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 		)
 	}
 
+	if config.IdentitiesConcurrency.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("identities_concurrency"),
+			"Unknown Migadu Identities Concurrency",
+			"The provider cannot determine how many identities to fetch concurrently as there is an unknown configuration value for the identities concurrency. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_IDENTITIES_CONCURRENCY environment variable.",
+		)
+	}
+
+	if config.ReadOnly.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("read_only"),
+			"Unknown Migadu Read-Only Mode",
+			"The provider cannot determine whether it is read-only as there is an unknown configuration value for the read-only mode. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_READ_ONLY environment variable.",
+		)
+	}
+
+	if config.AllowedDomains.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("allowed_domains"),
+			"Unknown Migadu Allowed Domains",
+			"The provider cannot determine which domains are allowed as there is an unknown configuration value for the allowed domains. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_ALLOWED_DOMAINS environment variable.",
+		)
+	}
+
+	if config.DeniedDomains.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("denied_domains"),
+			"Unknown Migadu Denied Domains",
+			"The provider cannot determine which domains are denied as there is an unknown configuration value for the denied domains. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_DENIED_DOMAINS environment variable.",
+		)
+	}
+
+	if config.AuditLogPath.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("audit_log_path"),
+			"Unknown Migadu Audit Log Path",
+			"The provider cannot write its audit log as there is an unknown configuration value for the audit log path. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_AUDIT_LOG_PATH environment variable.",
+		)
+	}
+
+	if config.ReadCache.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("read_cache"),
+			"Unknown Migadu Read Cache",
+			"The provider cannot determine whether to cache reads as there is an unknown configuration value for the read cache. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_READ_CACHE environment variable.",
+		)
+	}
+
+	if config.LogHTTP.IsUnknown() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("log_http"),
+			"Unknown Migadu HTTP Logging",
+			"The provider cannot determine whether to log HTTP exchanges as there is an unknown configuration value for the HTTP logging. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the MIGADU_LOG_HTTP environment variable.",
+		)
+	}
+
+	policy := newPolicy(ctx, config.Policy, &response.Diagnostics)
+
 	if response.Diagnostics.HasError() {
 		return
 	}
 
 	endpoint := os.Getenv("MIGADU_ENDPOINT")
-	username := os.Getenv("MIGADU_USERNAME")
-	token := os.Getenv("MIGADU_TOKEN")
+	credentialsFile := os.Getenv("MIGADU_CREDENTIALS_FILE")
 	timeout := os.Getenv("MIGADU_TIMEOUT")
+	identitiesConcurrency := os.Getenv("MIGADU_IDENTITIES_CONCURRENCY")
+	readOnly := os.Getenv("MIGADU_READ_ONLY")
+	allowedDomains := splitDomains(os.Getenv("MIGADU_ALLOWED_DOMAINS"))
+	deniedDomains := splitDomains(os.Getenv("MIGADU_DENIED_DOMAINS"))
+	auditLogPath := os.Getenv("MIGADU_AUDIT_LOG_PATH")
+	logHTTP := os.Getenv("MIGADU_LOG_HTTP")
+	readCache := os.Getenv("MIGADU_READ_CACHE")
+	validateCredentials := os.Getenv("MIGADU_VALIDATE_CREDENTIALS")
+	proxyURL := os.Getenv("MIGADU_PROXY_URL")
+	caCertFile := os.Getenv("MIGADU_CA_CERT_FILE")
+	caCertPEM := os.Getenv("MIGADU_CA_CERT_PEM")
+	insecureSkipVerify := os.Getenv("MIGADU_INSECURE_SKIP_VERIFY")
 
 	if !config.Endpoint.IsNull() {
 		endpoint = config.Endpoint.ValueString()
 	}
 
-	if !config.Username.IsNull() {
-		username = config.Username.ValueString()
-	}
-
-	if !config.Token.IsNull() {
-		token = config.Token.ValueString()
+	if !config.CredentialsFile.IsNull() {
+		credentialsFile = config.CredentialsFile.ValueString()
 	}
 
 	if !config.Timeout.IsNull() {
 		timeout = strconv.FormatInt(config.Timeout.ValueInt64(), 10)
 	}
 
+	if !config.IdentitiesConcurrency.IsNull() {
+		identitiesConcurrency = strconv.FormatInt(config.IdentitiesConcurrency.ValueInt64(), 10)
+	}
+
+	if !config.ReadOnly.IsNull() {
+		readOnly = strconv.FormatBool(config.ReadOnly.ValueBool())
+	}
+
+	if !config.AuditLogPath.IsNull() {
+		auditLogPath = config.AuditLogPath.ValueString()
+	}
+
+	if !config.ReadCache.IsNull() {
+		readCache = strconv.FormatBool(config.ReadCache.ValueBool())
+	}
+
+	if !config.LogHTTP.IsNull() {
+		logHTTP = strconv.FormatBool(config.LogHTTP.ValueBool())
+	}
+
+	if !config.ValidateCredentials.IsNull() {
+		validateCredentials = strconv.FormatBool(config.ValidateCredentials.ValueBool())
+	}
+
+	if !config.ProxyURL.IsNull() {
+		proxyURL = config.ProxyURL.ValueString()
+	}
+
+	if !config.CACertFile.IsNull() {
+		caCertFile = config.CACertFile.ValueString()
+	}
+
+	if !config.CACertPEM.IsNull() {
+		caCertPEM = config.CACertPEM.ValueString()
+	}
+
+	if !config.InsecureSkipVerify.IsNull() {
+		insecureSkipVerify = strconv.FormatBool(config.InsecureSkipVerify.ValueBool())
+	}
+
+	if !config.AllowedDomains.IsNull() {
+		allowedDomains = nil
+		response.Diagnostics.Append(config.AllowedDomains.ElementsAs(ctx, &allowedDomains, false)...)
+	}
+
+	if !config.DeniedDomains.IsNull() {
+		deniedDomains = nil
+		response.Diagnostics.Append(config.DeniedDomains.ElementsAs(ctx, &deniedDomains, false)...)
+	}
+
 	if endpoint == "" {
 		endpoint = "https://api.migadu.com/v1/"
 	}
@@ -151,12 +611,71 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 		timeout = "10"
 	}
 
+	if identitiesConcurrency == "" {
+		identitiesConcurrency = "4"
+	}
+
+	if readOnly == "" {
+		readOnly = "false"
+	}
+
+	if logHTTP == "" {
+		logHTTP = "false"
+	}
+
+	if readCache == "" {
+		readCache = "false"
+	}
+
+	if validateCredentials == "" {
+		validateCredentials = "false"
+	}
+
+	if insecureSkipVerify == "" {
+		insecureSkipVerify = "false"
+	}
+
+	if credentialsFile == "" {
+		credentialsFile = DefaultCredentialsFile()
+	}
+
+	credentials, err := ResolveCredentials(ctx, credentialsFile, CredentialSources{
+		Username:     config.Username.ValueString(),
+		Token:        config.Token.ValueString(),
+		TokenFile:    config.TokenFile.ValueString(),
+		TokenCommand: config.TokenCommand.ValueString(),
+		Profile:      config.Profile.ValueString(),
+	}, CredentialSources{
+		Username:     os.Getenv("MIGADU_USERNAME"),
+		Token:        os.Getenv("MIGADU_TOKEN"),
+		TokenFile:    os.Getenv("MIGADU_TOKEN_FILE"),
+		TokenCommand: os.Getenv("MIGADU_TOKEN_COMMAND"),
+		Profile:      os.Getenv("MIGADU_PROFILE"),
+	})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Unable to Read Migadu API Credentials",
+			"The provider cannot create the Migadu API client as one of its credential sources failed: "+err.Error(),
+		)
+		return
+	}
+	username := credentials.Username
+	token := credentials.Token
+
+	useSandbox := sandbox.IsEndpoint(endpoint)
+	if useSandbox && username == "" {
+		username = "sandbox"
+	}
+	if useSandbox && token == "" {
+		token = "sandbox"
+	}
+
 	if username == "" {
 		response.Diagnostics.AddAttributeError(
 			path.Root("username"),
 			"Missing Migadu API Username",
 			"The provider cannot create the Migadu API client as there is a missing or empty value for the Migadu API username. "+
-				"Set the username value in the configuration or use the MIGADU_USERNAME environment variable. "+
+				"Set the username value in the configuration, use the MIGADU_USERNAME environment variable, or select a profile of the shared credentials file. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -166,7 +685,7 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 			path.Root("token"),
 			"Missing Migadu API Token",
 			"The provider cannot create the Migadu API client as there is a missing or empty value for the Migadu API token. "+
-				"Set the password value in the configuration or use the MIGADU_TOKEN environment variable. "+
+				"Set the token, token_file, or token_command value in the configuration, use the matching MIGADU_TOKEN, MIGADU_TOKEN_FILE, or MIGADU_TOKEN_COMMAND environment variable, or select a profile of the shared credentials file. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -180,6 +699,106 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 		)
 	}
 
+	concurrentIdentities, err := strconv.Atoi(identitiesConcurrency)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("identities_concurrency"),
+			"Invalid Migadu Identities Concurrency",
+			"The supplied identities concurrency cannot be parsed into a number: "+err.Error(),
+		)
+	} else if concurrentIdentities < 1 {
+		response.Diagnostics.AddAttributeError(
+			path.Root("identities_concurrency"),
+			"Invalid Migadu Identities Concurrency",
+			"The supplied identities concurrency must be at least 1, got: "+identitiesConcurrency,
+		)
+	}
+
+	readOnlyMode, err := strconv.ParseBool(readOnly)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("read_only"),
+			"Invalid Migadu Read-Only Mode",
+			"The supplied read-only mode cannot be parsed into a boolean: "+err.Error(),
+		)
+	}
+
+	logHTTPExchanges, err := strconv.ParseBool(logHTTP)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("log_http"),
+			"Invalid Migadu HTTP Logging",
+			"The supplied HTTP logging value cannot be parsed into a boolean: "+err.Error(),
+		)
+	}
+
+	cacheReads, err := strconv.ParseBool(readCache)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("read_cache"),
+			"Invalid Migadu Read Cache",
+			"The supplied read cache value cannot be parsed into a boolean: "+err.Error(),
+		)
+	}
+
+	validateCredentialsOnConfigure, err := strconv.ParseBool(validateCredentials)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("validate_credentials"),
+			"Invalid Migadu Credentials Validation",
+			"The supplied credentials validation value cannot be parsed into a boolean: "+err.Error(),
+		)
+	}
+
+	if validateCredentialsOnConfigure && !useSandbox {
+		if err := ValidateEndpoint(endpoint); err != nil {
+			response.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid Migadu API Endpoint",
+				"The Migadu API endpoint must be a well-formed https URL: "+err.Error(),
+			)
+		}
+	}
+
+	skipTLSVerification, err := strconv.ParseBool(insecureSkipVerify)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("insecure_skip_verify"),
+			"Invalid Migadu API TLS Verification",
+			"The supplied TLS verification value cannot be parsed into a boolean: "+err.Error(),
+		)
+	}
+
+	transport, err := NewHTTPTransport(HTTPTransportOptions{
+		ProxyURL:           proxyURL,
+		CACertFile:         caCertFile,
+		CACertPEM:          caCertPEM,
+		InsecureSkipVerify: skipTLSVerification,
+		UserAgent:          userAgent(p.version, request.TerraformVersion),
+	})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid Migadu API HTTP Transport",
+			"The provider cannot create the HTTP transport for the Migadu API client: "+err.Error(),
+		)
+	}
+
+	domainScope := DomainScope{}
+	domainScope.Allowed = normalizeDomains(path.Root("allowed_domains"), allowedDomains, &response.Diagnostics)
+	domainScope.Denied = normalizeDomains(path.Root("denied_domains"), deniedDomains, &response.Diagnostics)
+
+	var auditLog *AuditLog
+	if auditLogPath != "" {
+		auditLog, err = NewAuditLog(auditLogPath)
+		if err != nil {
+			response.Diagnostics.AddAttributeError(
+				path.Root("audit_log_path"),
+				"Invalid Migadu Audit Log Path",
+				"The supplied audit log path cannot be opened for writing: "+err.Error(),
+			)
+		}
+	}
+
 	if response.Diagnostics.HasError() {
 		return
 	}
@@ -188,6 +807,16 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 	ctx = tflog.SetField(ctx, "migadu_username", username)
 	ctx = tflog.SetField(ctx, "migadu_token", token)
 	ctx = tflog.SetField(ctx, "migadu_timeout", timeout)
+	ctx = tflog.SetField(ctx, "migadu_identities_concurrency", concurrentIdentities)
+	ctx = tflog.SetField(ctx, "migadu_read_only", readOnlyMode)
+	ctx = tflog.SetField(ctx, "migadu_allowed_domains", domainScope.Allowed)
+	ctx = tflog.SetField(ctx, "migadu_denied_domains", domainScope.Denied)
+	ctx = tflog.SetField(ctx, "migadu_audit_log_path", auditLogPath)
+	ctx = tflog.SetField(ctx, "migadu_log_http", logHTTPExchanges)
+	ctx = tflog.SetField(ctx, "migadu_read_cache", cacheReads)
+	ctx = tflog.SetField(ctx, "migadu_validate_credentials", validateCredentialsOnConfigure)
+	ctx = tflog.SetField(ctx, "migadu_proxy_url", proxyURL)
+	ctx = tflog.SetField(ctx, "migadu_insecure_skip_verify", skipTLSVerification)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "migadu_username")
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "migadu_token")
 
@@ -203,15 +832,93 @@ func (p *MigaduProvider) Configure(ctx context.Context, request provider.Configu
 		)
 		return
 	}
+	c.HTTPClient.Transport = transport
+
+	if useSandbox {
+		api, err := sandbox.Open(endpoint)
+		if err != nil {
+			response.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Unable to Open Migadu Sandbox",
+				"The provider cannot open the sandbox selected by the Migadu API endpoint: "+err.Error(),
+			)
+			return
+		}
+		tflog.Info(ctx, "Using Migadu sandbox instead of the Migadu API")
+		c.Endpoint = sandbox.Endpoint
+		c.HTTPClient.Transport = api
+	}
 
-	response.DataSourceData = c
-	response.ResourceData = c
+	if logHTTPExchanges {
+		c.HTTPClient.Transport = &HTTPTracingTransport{
+			Next:     c.HTTPClient.Transport,
+			Username: username,
+			Token:    token,
+		}
+	}
+
+	if validateCredentialsOnConfigure && !useSandbox {
+		tflog.Debug(ctx, "Validating Migadu API credentials")
+		response.Diagnostics.Append(ValidateCredentials(ctx, c)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var cache *ReadCache
+	if cacheReads {
+		cache = NewReadCache()
+	}
+
+	providerData := &ProviderData{
+		MigaduClient: c,
+		ReadOnly:     readOnlyMode,
+		DomainScope:  domainScope,
+		Policy:       policy,
+		AuditLog:     auditLog,
+		ReadCache:    cache,
+
+		IdentitiesConcurrency: concurrentIdentities,
+	}
+	response.DataSourceData = providerData
+	response.ResourceData = providerData
+	response.ListResourceData = providerData
 
 	tflog.Info(ctx, "Configured Migadu client")
 }
 
+// splitDomains splits a comma separated list of domains as used in environment variables.
+func splitDomains(value string) []custom_types.DomainNameValue {
+	var domains []custom_types.DomainNameValue
+	for _, domain := range strings.Split(value, ",") {
+		if strings.TrimSpace(domain) != "" {
+			domains = append(domains, custom_types.NewDomainNameValue(domain))
+		}
+	}
+	return domains
+}
+
+// normalizeDomains returns the normalized form of all given domains and adds an error for each invalid domain.
+func normalizeDomains(attributePath path.Path, domains []custom_types.DomainNameValue, diagnostics *diag.Diagnostics) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		value, err := domain.NormalizedValue()
+		if err != nil {
+			diagnostics.AddAttributeError(
+				attributePath,
+				"Invalid Migadu Domain Scope",
+				"The domain '"+domain.ValueString()+"' cannot be converted to ASCII: "+err.Error(),
+			)
+			continue
+		}
+		normalized = append(normalized, value)
+	}
+	return normalized
+}
+
 func (p *MigaduProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		NewAddressDataSource,
 		NewAliasDataSource,
 		NewAliasesDataSource,
 		NewIdentitiesDataSource,
@@ -231,3 +938,12 @@ func (p *MigaduProvider) Resources(_ context.Context) []func() resource.Resource
 		NewRewriteRuleResource,
 	}
 }
+
+func (p *MigaduProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewAliasListResource,
+		NewIdentityListResource,
+		NewMailboxListResource,
+		NewRewriteRuleListResource,
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"slices"
+)
+
+// ProviderData is passed from the provider to all resources, data sources, and list resources during their
+// configuration.
+type ProviderData struct {
+	MigaduClient *client.MigaduClient
+	ReadOnly     bool
+	DomainScope  DomainScope
+	Policy       Policy
+	AuditLog     *AuditLog
+	ReadCache    *ReadCache
+	// IdentitiesConcurrency limits the concurrent requests for the identities of mailboxes.
+	IdentitiesConcurrency int
+}
+
+// DomainScope restricts the domains that can be used with the provider. Both lists contain normalized domain names.
+// An empty allow list allows all domains which are not denied.
+type DomainScope struct {
+	Allowed []string
+	Denied  []string
+}
+
+// Diagnostics returns an error in case the given domain is outside the scope. Unknown domains are not checked.
+func (s DomainScope) Diagnostics(attributePath path.Path, domainName custom_types.DomainNameValue) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	if domainName.IsNull() || domainName.IsUnknown() {
+		return diagnostics
+	}
+
+	normalized, err := domainName.NormalizedValue()
+	if err != nil {
+		// invalid domain names are reported by the validation of the attribute itself
+		return diagnostics
+	}
+
+	if slices.Contains(s.Denied, normalized) {
+		diagnostics.AddAttributeError(
+			attributePath,
+			"Domain Not Allowed",
+			"The domain '"+domainName.ValueString()+"' is part of the 'denied_domains' of the provider configuration. "+
+				"Check the value of the domain name or change the provider configuration to allow this domain.",
+		)
+	} else if len(s.Allowed) > 0 && !slices.Contains(s.Allowed, normalized) {
+		diagnostics.AddAttributeError(
+			attributePath,
+			"Domain Not Allowed",
+			"The domain '"+domainName.ValueString()+"' is not part of the 'allowed_domains' of the provider configuration. "+
+				"Check the value of the domain name or change the provider configuration to allow this domain.",
+		)
+	}
+	return diagnostics
+}
+
+// allows reports whether the given normalized domain name is inside the scope.
+func (s DomainScope) allows(normalized string) bool {
+	return !slices.Contains(s.Denied, normalized) && (len(s.Allowed) == 0 || slices.Contains(s.Allowed, normalized))
+}
+
+// resourcePlanDiagnostics returns an error for each plan that would create, update, or delete an object while the
+// provider is read-only, for each plan that uses a domain outside the domain scope of the provider, and for each
+// violation of the policy of the provider. The name of the object is used in the summary of the read-only diagnostic.
+func resourcePlanDiagnostics(ctx context.Context, readOnly bool, domainScope DomainScope, policy Policy, resourceType string, name string, request resource.ModifyPlanRequest) diag.Diagnostics {
+	diagnostics := readOnlyPlanDiagnostics(readOnly, name, request)
+
+	domainPath := path.Root("domain_name")
+	if !request.Plan.Raw.IsNull() {
+		var domainName custom_types.DomainNameValue
+		diagnostics.Append(request.Plan.GetAttribute(ctx, domainPath, &domainName)...)
+		diagnostics.Append(domainScope.Diagnostics(domainPath, domainName)...)
+	} else if !request.State.Raw.IsNull() {
+		var domainName custom_types.DomainNameValue
+		diagnostics.Append(request.State.GetAttribute(ctx, domainPath, &domainName)...)
+		diagnostics.Append(domainScope.Diagnostics(domainPath, domainName)...)
+	}
+
+	diagnostics.Append(policy.planDiagnostics(resourceType, request)...)
+	return diagnostics
+}
+
+// readOnlyPlanDiagnostics returns an error for each plan that would create, update, or delete an object while the
+// provider is read-only. The name of the object is used in the summary of the diagnostic.
+func readOnlyPlanDiagnostics(readOnly bool, name string, request resource.ModifyPlanRequest) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+	if !readOnly {
+		return diagnostics
+	}
+
+	var operation string
+	switch {
+	case request.State.Raw.IsNull():
+		operation = "create"
+	case request.Plan.Raw.IsNull():
+		operation = "delete"
+	case !request.Plan.Raw.Equal(request.State.Raw):
+		operation = "update"
+	default:
+		return diagnostics
+	}
+
+	diagnostics.AddError(
+		"Read-Only Provider Cannot Change "+name,
+		"The provider is configured to be read-only and will therefore not "+operation+" this "+name+". "+
+			"Remove 'read_only = true' from the provider configuration and unset the MIGADU_READ_ONLY environment variable to allow changes.",
+	)
+	return diagnostics
+}
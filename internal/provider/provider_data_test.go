@@ -0,0 +1,275 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProviderData_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{
+		Aliases: []model.Alias{
+			{
+				LocalPart:    "some",
+				DomainName:   "example.com",
+				Address:      "some@example.com",
+				Destinations: []string{"other@example.com"},
+			},
+		},
+	}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		readOnly bool
+		prior    func(tftypes.Value) tftypes.Value
+		proposed func(tftypes.Value) tftypes.Value
+		want     string
+	}{
+		"create": {
+			readOnly: true,
+			prior:    nullValue,
+			proposed: sameValue,
+			want:     "Read-Only Provider Cannot Change Alias",
+		},
+		"update": {
+			readOnly: true,
+			prior:    sameValue,
+			proposed: func(value tftypes.Value) tftypes.Value {
+				return withAttribute(t, value, "is_internal", tftypes.NewValue(tftypes.Bool, true))
+			},
+			want: "Read-Only Provider Cannot Change Alias",
+		},
+		"delete": {
+			readOnly: true,
+			prior:    sameValue,
+			proposed: nullValue,
+			want:     "Read-Only Provider Cannot Change Alias",
+		},
+		"no-op": {
+			readOnly: true,
+			prior:    sameValue,
+			proposed: sameValue,
+		},
+		"writable": {
+			readOnly: false,
+			prior:    sameValue,
+			proposed: nullValue,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			providerServer := configuredProviderServerWith(t, server.URL, map[string]tftypes.Value{
+				"read_only": tftypes.NewValue(tftypes.Bool, testCase.readOnly),
+			})
+			schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatalf("GetProviderSchema error: %s", err)
+			}
+			resourceType := schemaResponse.ResourceSchemas["migadu_alias"].ValueType()
+
+			imported := importResource(t, providerServer, &tfprotov6.ImportResourceStateRequest{
+				TypeName: "migadu_alias",
+				ID:       "some@example.com",
+			})
+			read := readResource(t, providerServer, &tfprotov6.ReadResourceRequest{
+				TypeName:     "migadu_alias",
+				CurrentState: imported.State,
+			})
+			assertNoDiagnostics(t, read.Diagnostics)
+			current, err := read.NewState.Unmarshal(resourceType)
+			if err != nil {
+				t.Fatalf("Could not read state: %s", err)
+			}
+
+			prior := dynamicValue(t, resourceType, testCase.prior(current))
+			proposed := dynamicValue(t, resourceType, testCase.proposed(current))
+			response, err := providerServer.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         "migadu_alias",
+				PriorState:       prior,
+				ProposedNewState: proposed,
+				Config:           proposed,
+			})
+			if err != nil {
+				t.Fatalf("PlanResourceChange error: %s", err)
+			}
+
+			if testCase.want == "" {
+				assertNoDiagnostics(t, response.Diagnostics)
+			} else if assert.Len(t, response.Diagnostics, 1, "diagnostics") {
+				assert.Equal(t, testCase.want, response.Diagnostics[0].Summary, "summary")
+			}
+		})
+	}
+}
+
+func TestProviderData_DomainScope(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{
+		Aliases: []model.Alias{
+			{
+				LocalPart:    "some",
+				DomainName:   "xn--ho-hia.de",
+				Address:      "some@xn--ho-hia.de",
+				Destinations: []string{"other@xn--ho-hia.de"},
+			},
+		},
+	}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		allowed []string
+		denied  []string
+		domain  string
+		want    string
+	}{
+		"unrestricted": {
+			domain: "hoß.de",
+		},
+		"allowed-unicode": {
+			allowed: []string{"hoß.de"},
+			domain:  "hoß.de",
+		},
+		"allowed-punycode": {
+			allowed: []string{"xn--ho-hia.de"},
+			domain:  "HOß.de",
+		},
+		"not-allowed": {
+			allowed: []string{"example.com"},
+			domain:  "hoß.de",
+			want:    "Domain Not Allowed",
+		},
+		"denied": {
+			denied: []string{"xn--ho-hia.de"},
+			domain: "hoß.de",
+			want:   "Domain Not Allowed",
+		},
+		"allowed-and-denied": {
+			allowed: []string{"hoß.de"},
+			denied:  []string{"hoß.de"},
+			domain:  "hoß.de",
+			want:    "Domain Not Allowed",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			providerServer := configuredProviderServerWith(t, server.URL, map[string]tftypes.Value{
+				"allowed_domains": stringSet(testCase.allowed),
+				"denied_domains":  stringSet(testCase.denied),
+			})
+			schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatalf("GetProviderSchema error: %s", err)
+			}
+
+			t.Run("resource", func(t *testing.T) {
+				resourceType := schemaResponse.ResourceSchemas["migadu_alias"].ValueType()
+				proposed := objectValue(resourceType, map[string]tftypes.Value{
+					"local_part":   tftypes.NewValue(tftypes.String, "some"),
+					"domain_name":  tftypes.NewValue(tftypes.String, testCase.domain),
+					"destinations": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "other@example.com")}),
+				})
+				response, err := providerServer.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+					TypeName:         "migadu_alias",
+					PriorState:       dynamicValue(t, resourceType, tftypes.NewValue(resourceType, nil)),
+					ProposedNewState: dynamicValue(t, resourceType, proposed),
+					Config:           dynamicValue(t, resourceType, proposed),
+				})
+				if err != nil {
+					t.Fatalf("PlanResourceChange error: %s", err)
+				}
+				assertDiagnosticSummary(t, testCase.want, response.Diagnostics)
+			})
+
+			t.Run("data-source", func(t *testing.T) {
+				dataSourceType := schemaResponse.DataSourceSchemas["migadu_aliases"].ValueType()
+				config := objectValue(dataSourceType, map[string]tftypes.Value{
+					"domain_name": tftypes.NewValue(tftypes.String, testCase.domain),
+				})
+				response, err := providerServer.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+					TypeName: "migadu_aliases",
+					Config:   dynamicValue(t, dataSourceType, config),
+				})
+				if err != nil {
+					t.Fatalf("ReadDataSource error: %s", err)
+				}
+				assertDiagnosticSummary(t, testCase.want, response.Diagnostics)
+			})
+
+			t.Run("list-resource", func(t *testing.T) {
+				_, diagnostics := listResourcesWith(t, providerServer, "migadu_alias", map[string]string{"domain_name": testCase.domain})
+				assertDiagnosticSummary(t, testCase.want, diagnostics)
+			})
+		})
+	}
+}
+
+func stringSet(values []string) tftypes.Value {
+	setType := tftypes.Set{ElementType: tftypes.String}
+	if values == nil {
+		return tftypes.NewValue(setType, nil)
+	}
+	elements := make([]tftypes.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, tftypes.NewValue(tftypes.String, value))
+	}
+	return tftypes.NewValue(setType, elements)
+}
+
+// objectValue creates an object of the given type with the given attributes. All other attributes are null.
+func objectValue(objectType tftypes.Type, attributes map[string]tftypes.Value) tftypes.Value {
+	values := make(map[string]tftypes.Value)
+	for name, attributeType := range objectType.(tftypes.Object).AttributeTypes {
+		if value, ok := attributes[name]; ok {
+			values[name] = value
+		} else {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	return tftypes.NewValue(objectType, values)
+}
+
+func assertDiagnosticSummary(t *testing.T, want string, diagnostics []*tfprotov6.Diagnostic) {
+	if want == "" {
+		assertNoDiagnostics(t, diagnostics)
+		return
+	}
+	if assert.Len(t, diagnostics, 1, "diagnostics") {
+		assert.Equal(t, want, diagnostics[0].Summary, "summary")
+	}
+}
+
+func nullValue(value tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(value.Type(), nil)
+}
+
+func sameValue(value tftypes.Value) tftypes.Value {
+	return value
+}
+
+func withAttribute(t *testing.T, value tftypes.Value, name string, attribute tftypes.Value) tftypes.Value {
+	var attributes map[string]tftypes.Value
+	if err := value.As(&attributes); err != nil {
+		t.Fatalf("Could not read attributes: %s", err)
+	}
+	attributes[name] = attribute
+	return tftypes.NewValue(value.Type(), attributes)
+}
+
+func dynamicValue(t testing.TB, valueType tftypes.Type, value tftypes.Value) *tfprotov6.DynamicValue {
+	data, err := tfprotov6.NewDynamicValue(valueType, value)
+	if err != nil {
+		t.Fatalf("Could not create dynamic value: %s", err)
+	}
+	return &data
+}
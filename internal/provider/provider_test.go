@@ -10,9 +10,13 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	internal "github.com/metio/terraform-provider-migadu/internal/provider"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -26,6 +30,136 @@ func TestMigaduProvider_Metadata(t *testing.T) {
 	assert.Equal(t, "migadu", response.TypeName, "TypeName")
 }
 
+func TestMigaduProvider_Configure_UnknownToken(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		deferralAllowed bool
+		want            string
+	}{
+		"deferral-allowed": {
+			deferralAllowed: true,
+		},
+		"deferral-not-allowed": {
+			deferralAllowed: false,
+			want:            "Unknown Migadu API Token",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server, err := providerserver.NewProtocol6WithError(internal.New("test")())()
+			if err != nil {
+				t.Fatalf("Could not create provider server: %s", err)
+			}
+			schemaResponse, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatalf("GetProviderSchema error: %s", err)
+			}
+
+			configType := schemaResponse.Provider.ValueType()
+			config := objectValue(configType, map[string]tftypes.Value{
+				"username": tftypes.NewValue(tftypes.String, "username"),
+				"token":    tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			})
+			configureResponse, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+				Config: dynamicValue(t, configType, config),
+				ClientCapabilities: &tfprotov6.ConfigureProviderClientCapabilities{
+					DeferralAllowed: testCase.deferralAllowed,
+				},
+			})
+			if err != nil {
+				t.Fatalf("ConfigureProvider error: %s", err)
+			}
+			assertDiagnosticSummary(t, testCase.want, configureResponse.Diagnostics)
+			if testCase.want != "" {
+				return
+			}
+
+			dataSourceType := schemaResponse.DataSourceSchemas["migadu_aliases"].ValueType()
+			readResponse, err := server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+				TypeName: "migadu_aliases",
+				Config: dynamicValue(t, dataSourceType, objectValue(dataSourceType, map[string]tftypes.Value{
+					"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+				})),
+				ClientCapabilities: &tfprotov6.ReadDataSourceClientCapabilities{
+					DeferralAllowed: true,
+				},
+			})
+			if err != nil {
+				t.Fatalf("ReadDataSource error: %s", err)
+			}
+			assertNoDiagnostics(t, readResponse.Diagnostics)
+			if assert.NotNil(t, readResponse.Deferred, "deferred") {
+				assert.Equal(t, tfprotov6.DeferredReasonProviderConfigUnknown, readResponse.Deferred.Reason, "reason")
+			}
+
+			resourceType := schemaResponse.ResourceSchemas["migadu_alias"].ValueType()
+			proposed := objectValue(resourceType, map[string]tftypes.Value{
+				"local_part":   tftypes.NewValue(tftypes.String, "some"),
+				"domain_name":  tftypes.NewValue(tftypes.String, "example.com"),
+				"destinations": stringSet([]string{"other@example.com"}),
+			})
+			planResponse, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         "migadu_alias",
+				PriorState:       dynamicValue(t, resourceType, tftypes.NewValue(resourceType, nil)),
+				ProposedNewState: dynamicValue(t, resourceType, proposed),
+				Config:           dynamicValue(t, resourceType, proposed),
+				ClientCapabilities: &tfprotov6.PlanResourceChangeClientCapabilities{
+					DeferralAllowed: true,
+				},
+			})
+			if err != nil {
+				t.Fatalf("PlanResourceChange error: %s", err)
+			}
+			assertNoDiagnostics(t, planResponse.Diagnostics)
+			if assert.NotNil(t, planResponse.Deferred, "deferred") {
+				assert.Equal(t, tfprotov6.DeferredReasonProviderConfigUnknown, planResponse.Deferred.Reason, "reason")
+			}
+		})
+	}
+}
+
+func TestMigaduProvider_Configure_IdentitiesConcurrency(t *testing.T) {
+	testCases := map[string]struct {
+		concurrency tftypes.Value
+		environment string
+		want        string
+	}{
+		"default": {
+			concurrency: tftypes.NewValue(tftypes.Number, nil),
+		},
+		"configured": {
+			concurrency: tftypes.NewValue(tftypes.Number, 8),
+		},
+		"environment": {
+			concurrency: tftypes.NewValue(tftypes.Number, nil),
+			environment: "8",
+		},
+		"zero": {
+			concurrency: tftypes.NewValue(tftypes.Number, 0),
+			want:        "Invalid Migadu Identities Concurrency",
+		},
+		"invalid-environment": {
+			concurrency: tftypes.NewValue(tftypes.Number, nil),
+			environment: "many",
+			want:        "Invalid Migadu Identities Concurrency",
+		},
+		"unknown": {
+			concurrency: tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+			want:        "Unknown Migadu Identities Concurrency",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("MIGADU_IDENTITIES_CONCURRENCY", testCase.environment)
+			_, diagnostics := configureProviderServer(t, "memory://identities-concurrency", map[string]tftypes.Value{
+				"identities_concurrency": testCase.concurrency,
+			})
+			assertDiagnosticSummary(t, testCase.want, diagnostics)
+		})
+	}
+}
+
 func providerConfig(endpoint string) string {
 	return fmt.Sprintf(`
 		provider "migadu" {
@@ -38,7 +172,7 @@ func providerConfig(endpoint string) string {
 
 var (
 	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-		"migadu": providerserver.NewProtocol6WithError(internal.New()),
+		"migadu": providerserver.NewProtocol6WithError(internal.New("test")()),
 	}
 )
 
@@ -62,3 +196,377 @@ type ResourceTestStep[T any] struct {
 	Send T
 	Want T
 }
+
+func upgradeState(t *testing.T, r fwresource.Resource, version int64, rawState string) tfsdk.State {
+	ctx := context.Background()
+	schemaResponse := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResponse)
+
+	upgrader, ok := r.(fwresource.ResourceWithUpgradeState).UpgradeState(ctx)[version]
+	if !ok {
+		t.Fatalf("No state upgrader for version %d", version)
+	}
+
+	request := fwresource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: []byte(rawState)},
+	}
+	response := &fwresource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResponse.Schema,
+		},
+	}
+	upgrader.StateUpgrader(ctx, request, response)
+	if response.Diagnostics.HasError() {
+		t.Fatalf("UpgradeState diagnostics: %+v", response.Diagnostics)
+	}
+
+	return response.State
+}
+
+type ResourceIdentityTestCase struct {
+	TypeName        string
+	ImportID        string
+	Identity        map[string]string
+	ChangedIdentity map[string]string
+}
+
+// testResourceIdentity imports a resource by its legacy ID as well as its identity and verifies that the identity is
+// persisted and checked during Read. It talks directly to the provider server since the Terraform version used in
+// tests might not support resource identity yet.
+func testResourceIdentity(t *testing.T, endpoint string, testCase ResourceIdentityTestCase) {
+	ctx := context.Background()
+	server := configuredProviderServer(t, endpoint)
+
+	identitySchemas, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("GetResourceIdentitySchemas error: %s", err)
+	}
+	identityType := identitySchemas.IdentitySchemas[testCase.TypeName].ValueType()
+
+	t.Run("import-by-id", func(t *testing.T) {
+		imported := importResource(t, server, &tfprotov6.ImportResourceStateRequest{
+			TypeName: testCase.TypeName,
+			ID:       testCase.ImportID,
+		})
+		read := readResource(t, server, &tfprotov6.ReadResourceRequest{
+			TypeName:        testCase.TypeName,
+			CurrentState:    imported.State,
+			CurrentIdentity: imported.Identity,
+			Private:         imported.Private,
+		})
+		assertNoDiagnostics(t, read.Diagnostics)
+		assert.Equal(t, testCase.Identity, identityValues(t, identityType, read.NewIdentity), "Identity")
+	})
+
+	t.Run("import-by-identity", func(t *testing.T) {
+		imported := importResource(t, server, &tfprotov6.ImportResourceStateRequest{
+			TypeName: testCase.TypeName,
+			Identity: identityData(t, identityType, testCase.Identity),
+		})
+		read := readResource(t, server, &tfprotov6.ReadResourceRequest{
+			TypeName:        testCase.TypeName,
+			CurrentState:    imported.State,
+			CurrentIdentity: imported.Identity,
+			Private:         imported.Private,
+		})
+		assertNoDiagnostics(t, read.Diagnostics)
+		assert.Equal(t, testCase.Identity, identityValues(t, identityType, read.NewIdentity), "Identity")
+
+		refreshed := readResource(t, server, &tfprotov6.ReadResourceRequest{
+			TypeName:        testCase.TypeName,
+			CurrentState:    read.NewState,
+			CurrentIdentity: read.NewIdentity,
+		})
+		assertNoDiagnostics(t, refreshed.Diagnostics)
+		assert.Equal(t, testCase.Identity, identityValues(t, identityType, refreshed.NewIdentity), "Identity")
+	})
+
+	t.Run("changed-identity", func(t *testing.T) {
+		imported := importResource(t, server, &tfprotov6.ImportResourceStateRequest{
+			TypeName: testCase.TypeName,
+			ID:       testCase.ImportID,
+		})
+		read := readResource(t, server, &tfprotov6.ReadResourceRequest{
+			TypeName:        testCase.TypeName,
+			CurrentState:    imported.State,
+			CurrentIdentity: identityData(t, identityType, testCase.ChangedIdentity),
+		})
+		if len(read.Diagnostics) == 0 || read.Diagnostics[0].Summary != "Unexpected Identity Change" {
+			t.Fatalf("Expected identity change error, got: %+v", read.Diagnostics)
+		}
+	})
+}
+
+func configuredProviderServer(t *testing.T, endpoint string) tfprotov6.ProviderServer {
+	return configuredProviderServerWith(t, endpoint, nil)
+}
+
+// configuredProviderServerWith configures a provider server with the given additional provider configuration.
+func configuredProviderServerWith(t *testing.T, endpoint string, additionalConfig map[string]tftypes.Value) tfprotov6.ProviderServer {
+	server, diagnostics := configureProviderServer(t, endpoint, additionalConfig)
+	assertNoDiagnostics(t, diagnostics)
+	return server
+}
+
+// configureProviderServer configures a provider server with the given additional provider configuration and returns
+// all diagnostics of the configuration.
+func configureProviderServer(t testing.TB, endpoint string, additionalConfig map[string]tftypes.Value) (tfprotov6.ProviderServer, []*tfprotov6.Diagnostic) {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(internal.New("test")())()
+	if err != nil {
+		t.Fatalf("Could not create provider server: %s", err)
+	}
+
+	schemaResponse, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+
+	configType := schemaResponse.Provider.ValueType().(tftypes.Object)
+	configValues := make(map[string]tftypes.Value, len(configType.AttributeTypes))
+	for name, attributeType := range configType.AttributeTypes {
+		configValues[name] = tftypes.NewValue(attributeType, nil)
+	}
+	configValues["username"] = tftypes.NewValue(tftypes.String, "username")
+	configValues["token"] = tftypes.NewValue(tftypes.String, "token")
+	configValues["endpoint"] = tftypes.NewValue(tftypes.String, endpoint)
+	for name, value := range additionalConfig {
+		configValues[name] = value
+	}
+
+	config, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, configValues))
+	if err != nil {
+		t.Fatalf("Could not create provider configuration: %s", err)
+	}
+
+	configureResponse, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: &config})
+	if err != nil {
+		t.Fatalf("ConfigureProvider error: %s", err)
+	}
+
+	return server, configureResponse.Diagnostics
+}
+
+func importResource(t *testing.T, server tfprotov6.ProviderServer, request *tfprotov6.ImportResourceStateRequest) *tfprotov6.ImportedResource {
+	response, err := server.ImportResourceState(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ImportResourceState error: %s", err)
+	}
+	assertNoDiagnostics(t, response.Diagnostics)
+
+	if len(response.ImportedResources) != 1 {
+		t.Fatalf("Expected exactly one imported resource, got: %d", len(response.ImportedResources))
+	}
+	return response.ImportedResources[0]
+}
+
+func readResource(t testing.TB, server tfprotov6.ProviderServer, request *tfprotov6.ReadResourceRequest) *tfprotov6.ReadResourceResponse {
+	response, err := server.ReadResource(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ReadResource error: %s", err)
+	}
+	return response
+}
+
+func identityData(t *testing.T, identityType tftypes.Type, identity map[string]string) *tfprotov6.ResourceIdentityData {
+	values := make(map[string]tftypes.Value, len(identity))
+	for name, value := range identity {
+		values[name] = tftypes.NewValue(tftypes.String, value)
+	}
+
+	data, err := tfprotov6.NewDynamicValue(identityType, tftypes.NewValue(identityType, values))
+	if err != nil {
+		t.Fatalf("Could not create identity data: %s", err)
+	}
+	return &tfprotov6.ResourceIdentityData{IdentityData: &data}
+}
+
+func identityValues(t *testing.T, identityType tftypes.Type, data *tfprotov6.ResourceIdentityData) map[string]string {
+	if data == nil || data.IdentityData == nil {
+		t.Fatalf("Expected identity data, got none")
+	}
+
+	value, err := data.IdentityData.Unmarshal(identityType)
+	if err != nil {
+		t.Fatalf("Could not read identity data: %s", err)
+	}
+
+	var attributes map[string]tftypes.Value
+	if err = value.As(&attributes); err != nil {
+		t.Fatalf("Could not read identity attributes: %s", err)
+	}
+
+	identity := make(map[string]string, len(attributes))
+	for name, attribute := range attributes {
+		var str string
+		if err = attribute.As(&str); err != nil {
+			t.Fatalf("Could not read identity attribute %s: %s", name, err)
+		}
+		identity[name] = str
+	}
+	return identity
+}
+
+func assertNoDiagnostics(t *testing.T, diagnostics []*tfprotov6.Diagnostic) {
+	var summaries []string
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			summaries = append(summaries, diagnostic.Summary+": "+diagnostic.Detail)
+		}
+	}
+	if len(summaries) > 0 {
+		t.Fatalf("Unexpected diagnostics: %s", strings.Join(summaries, "; "))
+	}
+}
+
+type ListedResource struct {
+	DisplayName string
+	ID          string
+	Identity    map[string]string
+}
+
+// listResources calls the list resource of the given type and returns all results including the resource object. It
+// talks directly to the provider server since the Terraform version used in tests might not support list resources yet.
+func listResources(t *testing.T, endpoint string, typeName string, config map[string]string) ([]ListedResource, []*tfprotov6.Diagnostic) {
+	return listResourcesWith(t, configuredProviderServer(t, endpoint), typeName, config)
+}
+
+// listResourcesWith calls the list resource of the given type using an already configured provider server.
+func listResourcesWith(t *testing.T, server tfprotov6.ProviderServer, typeName string, config map[string]string) ([]ListedResource, []*tfprotov6.Diagnostic) {
+	ctx := context.Background()
+
+	schemaResponse, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+	identitySchemas, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("GetResourceIdentitySchemas error: %s", err)
+	}
+	resourceType := schemaResponse.ResourceSchemas[typeName].ValueType()
+	identityType := identitySchemas.IdentitySchemas[typeName].ValueType()
+	configType := schemaResponse.ListResourceSchemas[typeName].ValueType()
+
+	configValues := make(map[string]tftypes.Value, len(config))
+	for name, attributeType := range configType.(tftypes.Object).AttributeTypes {
+		if value, ok := config[name]; ok {
+			configValues[name] = tftypes.NewValue(attributeType, value)
+		} else {
+			configValues[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	configData, err := tfprotov6.NewDynamicValue(configType, tftypes.NewValue(configType, configValues))
+	if err != nil {
+		t.Fatalf("Could not create list configuration: %s", err)
+	}
+
+	stream, err := server.(tfprotov6.ProviderServerWithListResource).ListResource(ctx, &tfprotov6.ListResourceRequest{
+		TypeName:        typeName,
+		Config:          &configData,
+		IncludeResource: true,
+	})
+	if err != nil {
+		t.Fatalf("ListResource error: %s", err)
+	}
+
+	var listed []ListedResource
+	var diagnostics []*tfprotov6.Diagnostic
+	for result := range stream.Results {
+		diagnostics = append(diagnostics, result.Diagnostics...)
+		if result.Identity == nil {
+			continue
+		}
+
+		resourceValue, err := result.Resource.Unmarshal(resourceType)
+		if err != nil {
+			t.Fatalf("Could not read listed resource: %s", err)
+		}
+		var attributes map[string]tftypes.Value
+		if err = resourceValue.As(&attributes); err != nil {
+			t.Fatalf("Could not read listed resource attributes: %s", err)
+		}
+		var id string
+		if err = attributes["id"].As(&id); err != nil {
+			t.Fatalf("Could not read listed resource ID: %s", err)
+		}
+
+		listed = append(listed, ListedResource{
+			DisplayName: result.DisplayName,
+			ID:          id,
+			Identity:    identityValues(t, identityType, result.Identity),
+		})
+	}
+	return listed, diagnostics
+}
+
+// readDataSource reads a data source with the given configuration through the provider server and returns the
+// attributes of its state. All attributes not part of the configuration are null.
+func readDataSource(t *testing.T, server tfprotov6.ProviderServer, typeName string, config map[string]tftypes.Value) map[string]tftypes.Value {
+	ctx := context.Background()
+	schemaResponse, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+	dataSourceType := schemaResponse.DataSourceSchemas[typeName].ValueType()
+
+	response, err := server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: typeName,
+		Config:   dynamicValue(t, dataSourceType, objectValue(dataSourceType, config)),
+	})
+	if err != nil {
+		t.Fatalf("ReadDataSource error: %s", err)
+	}
+	assertNoDiagnostics(t, response.Diagnostics)
+
+	state, err := response.State.Unmarshal(dataSourceType)
+	if err != nil {
+		t.Fatalf("Could not read state: %s", err)
+	}
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		t.Fatalf("Could not read attributes: %s", err)
+	}
+	return attributes
+}
+
+// nestedStrings returns the given string attribute of each object in a list of objects.
+func nestedStrings(t *testing.T, list tftypes.Value, name string) []string {
+	var objects []tftypes.Value
+	if err := list.As(&objects); err != nil {
+		t.Fatalf("Could not read list: %s", err)
+	}
+	values := make([]string, 0, len(objects))
+	for _, object := range objects {
+		var attributes map[string]tftypes.Value
+		if err := object.As(&attributes); err != nil {
+			t.Fatalf("Could not read object: %s", err)
+		}
+		var value string
+		if err := attributes[name].As(&value); err != nil {
+			t.Fatalf("Could not read %s: %s", name, err)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// nestedStringsByKey returns the given string attribute of each object in a map of objects.
+func nestedStringsByKey(t *testing.T, objectMap tftypes.Value, name string) map[string]string {
+	var objects map[string]tftypes.Value
+	if err := objectMap.As(&objects); err != nil {
+		t.Fatalf("Could not read map: %s", err)
+	}
+	values := make(map[string]string, len(objects))
+	for key, object := range objects {
+		var attributes map[string]tftypes.Value
+		if err := object.As(&attributes); err != nil {
+			t.Fatalf("Could not read object: %s", err)
+		}
+		var value string
+		if err := attributes[name].As(&value); err != nil {
+			t.Fatalf("Could not read %s: %s", name, err)
+		}
+		values[key] = value
+	}
+	return values
+}
@@ -0,0 +1,66 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"slices"
+)
+
+// convertRawState converts the raw state of a resource into the current schema of that resource. Attributes listed
+// in removedAttributes are dropped, attributes listed in setAttributes are de-duplicated since they used to be lists
+// in versions prior to 2023.8.23, and all attributes unknown to the current schema are ignored. This allows states
+// written by old and current versions of this provider to share the same schema version, and states of renamed
+// resources to be moved into their new resource type.
+func convertRawState(ctx context.Context, r resource.Resource, rawState *tfprotov6.RawState, removedAttributes []string, setAttributes []string) (tftypes.Value, error) {
+	schemaResponse := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResponse)
+	schemaType := schemaResponse.Schema.Type().TerraformType(ctx)
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(rawState.JSON, &attributes); err != nil {
+		return tftypes.Value{}, err
+	}
+
+	for _, name := range removedAttributes {
+		delete(attributes, name)
+	}
+
+	for _, name := range setAttributes {
+		value, ok := attributes[name]
+		if !ok {
+			continue
+		}
+
+		var elements []string
+		if err := json.Unmarshal(value, &elements); err != nil || elements == nil {
+			continue
+		}
+
+		slices.Sort(elements)
+		deduplicated, err := json.Marshal(slices.Compact(elements))
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		attributes[name] = deduplicated
+	}
+
+	convertedJSON, err := json.Marshal(attributes)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+
+	convertedRawState := tfprotov6.RawState{JSON: convertedJSON}
+	return convertedRawState.UnmarshalWithOpts(schemaType, tfprotov6.UnmarshalOpts{
+		ValueFromJSONOpts: tftypes.ValueFromJSONOpts{
+			IgnoreUndefinedAttributes: true,
+		},
+	})
+}
@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"golang.org/x/net/idna"
+	"strings"
+	"sync"
+)
+
+// ReadCache keeps the aliases, mailboxes, and rewrite rules of each domain in memory for the lifetime of a provider
+// configuration. The first read of an object fetches the entire list of its domain, all further reads of the same
+// domain are answered from memory. Objects missing in a list are fetched individually, so that deleted objects are
+// still reported by the API. Its methods can be called on a nil ReadCache in which case every read calls the API.
+type ReadCache struct {
+	mutex   sync.Mutex
+	domains map[string]*readCacheDomain
+}
+
+// readCacheDomain holds the cached lists of a single domain. Its mutex ensures that concurrent reads of the same
+// domain fetch each list only once.
+type readCacheDomain struct {
+	mutex        sync.Mutex
+	aliases      *model.Aliases
+	mailboxes    *model.Mailboxes
+	rewriteRules *model.RewriteRules
+}
+
+// NewReadCache creates an empty read cache.
+func NewReadCache() *ReadCache {
+	return &ReadCache{domains: make(map[string]*readCacheDomain)}
+}
+
+// Invalidate removes all cached objects of the given domain. It must be called after every change to an object of
+// that domain.
+func (c *ReadCache) Invalidate(domain string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.domains, readCacheKey(domain))
+}
+
+// GetAliases returns all aliases of the given domain.
+func (c *ReadCache) GetAliases(ctx context.Context, migaduClient *client.MigaduClient, domain string) (*model.Aliases, error) {
+	if c == nil {
+		return migaduClient.GetAliases(ctx, domain)
+	}
+	entry := c.domain(domain)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.aliases == nil {
+		tflog.Debug(ctx, "Filling read cache with aliases", map[string]any{"domain_name": domain})
+		aliases, err := migaduClient.GetAliases(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+		entry.aliases = aliases
+	}
+	return entry.aliases, nil
+}
+
+// GetAlias returns a single alias of the given domain.
+func (c *ReadCache) GetAlias(ctx context.Context, migaduClient *client.MigaduClient, domain string, localPart string) (*model.Alias, error) {
+	if c == nil {
+		return migaduClient.GetAlias(ctx, domain, localPart)
+	}
+	aliases, err := c.GetAliases(ctx, migaduClient, domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases.Aliases {
+		if alias.LocalPart == localPart {
+			return &alias, nil
+		}
+	}
+	return migaduClient.GetAlias(ctx, domain, localPart)
+}
+
+// GetMailbox returns a single mailbox of the given domain.
+func (c *ReadCache) GetMailbox(ctx context.Context, migaduClient *client.MigaduClient, domain string, localPart string) (*model.Mailbox, error) {
+	if c == nil {
+		return migaduClient.GetMailbox(ctx, domain, localPart)
+	}
+	mailboxes, err := c.getMailboxes(ctx, migaduClient, domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, mailbox := range mailboxes.Mailboxes {
+		if mailbox.LocalPart == localPart {
+			return &mailbox, nil
+		}
+	}
+	return migaduClient.GetMailbox(ctx, domain, localPart)
+}
+
+// GetRewriteRule returns a single rewrite rule of the given domain.
+func (c *ReadCache) GetRewriteRule(ctx context.Context, migaduClient *client.MigaduClient, domain string, slug string) (*model.RewriteRule, error) {
+	if c == nil {
+		return migaduClient.GetRewriteRule(ctx, domain, slug)
+	}
+	rewriteRules, err := c.getRewriteRules(ctx, migaduClient, domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, rewriteRule := range rewriteRules.RewriteRules {
+		if rewriteRule.Name == slug {
+			return &rewriteRule, nil
+		}
+	}
+	return migaduClient.GetRewriteRule(ctx, domain, slug)
+}
+
+func (c *ReadCache) getMailboxes(ctx context.Context, migaduClient *client.MigaduClient, domain string) (*model.Mailboxes, error) {
+	entry := c.domain(domain)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.mailboxes == nil {
+		tflog.Debug(ctx, "Filling read cache with mailboxes", map[string]any{"domain_name": domain})
+		mailboxes, err := migaduClient.GetMailboxes(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+		entry.mailboxes = mailboxes
+	}
+	return entry.mailboxes, nil
+}
+
+func (c *ReadCache) getRewriteRules(ctx context.Context, migaduClient *client.MigaduClient, domain string) (*model.RewriteRules, error) {
+	entry := c.domain(domain)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.rewriteRules == nil {
+		tflog.Debug(ctx, "Filling read cache with rewrite rules", map[string]any{"domain_name": domain})
+		rewriteRules, err := migaduClient.GetRewriteRules(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+		entry.rewriteRules = rewriteRules
+	}
+	return entry.rewriteRules, nil
+}
+
+func (c *ReadCache) domain(domain string) *readCacheDomain {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := readCacheKey(domain)
+	entry, ok := c.domains[key]
+	if !ok {
+		entry = &readCacheDomain{}
+		c.domains[key] = entry
+	}
+	return entry
+}
+
+// readCacheKey normalizes domain names so that their unicode and punycode forms share the same cache entry.
+func readCacheKey(domain string) string {
+	if ascii, err := idna.ToASCII(domain); err == nil {
+		return strings.ToLower(ascii)
+	}
+	return strings.ToLower(domain)
+}
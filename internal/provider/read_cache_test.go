@@ -0,0 +1,311 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReadCache(t *testing.T) {
+	ctx := context.Background()
+	var mutex sync.Mutex
+	var requests []string
+	api := simulator.MigaduAPI(t, &simulator.State{
+		Aliases: []model.Alias{
+			{LocalPart: "first", DomainName: "example.com", Destinations: []string{"other@example.com"}},
+			{LocalPart: "second", DomainName: "example.com", Destinations: []string{"other@example.com"}},
+			{LocalPart: "some", DomainName: "xn--ho-hia.de", Destinations: []string{"other@example.com"}},
+		},
+		Mailboxes: []model.Mailbox{
+			{LocalPart: "first", DomainName: "example.com", Name: "First"},
+			{LocalPart: "second", DomainName: "example.com", Name: "Second"},
+		},
+		Rewrites: []model.RewriteRule{
+			{Name: "first", DomainName: "example.com", LocalPartRule: "first-*"},
+			{Name: "second", DomainName: "example.com", LocalPartRule: "second-*"},
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mutex.Lock()
+		requests = append(requests, request.URL.Path)
+		mutex.Unlock()
+		api(writer, request)
+	}))
+	defer server.Close()
+	migaduClient := tracingClient(t, server.URL, "username", "token")
+
+	testCases := map[string]struct {
+		cache func() *provider.ReadCache
+		read  func(cache *provider.ReadCache) error
+		want  []string
+	}{
+		"aliases": {
+			cache: provider.NewReadCache,
+			read: func(cache *provider.ReadCache) error {
+				for _, localPart := range []string{"first", "second", "first"} {
+					alias, err := cache.GetAlias(ctx, migaduClient, "example.com", localPart)
+					if err != nil {
+						return err
+					}
+					assert.Equal(t, localPart, alias.LocalPart, "local part")
+				}
+				return nil
+			},
+			want: []string{"/domains/example.com/aliases"},
+		},
+		"uncached": {
+			cache: func() *provider.ReadCache { return nil },
+			read: func(cache *provider.ReadCache) error {
+				for _, localPart := range []string{"first", "second"} {
+					if _, err := cache.GetAlias(ctx, migaduClient, "example.com", localPart); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: []string{"/domains/example.com/aliases/first", "/domains/example.com/aliases/second"},
+		},
+		"missing": {
+			cache: provider.NewReadCache,
+			read: func(cache *provider.ReadCache) error {
+				_, err := cache.GetAlias(ctx, migaduClient, "example.com", "missing")
+				var requestError *client.RequestError
+				if assert.True(t, errors.As(err, &requestError), "request error") {
+					assert.Equal(t, http.StatusNotFound, requestError.StatusCode, "status code")
+				}
+				return nil
+			},
+			want: []string{"/domains/example.com/aliases", "/domains/example.com/aliases/missing"},
+		},
+		"invalidate": {
+			cache: provider.NewReadCache,
+			read: func(cache *provider.ReadCache) error {
+				if _, err := cache.GetAlias(ctx, migaduClient, "example.com", "first"); err != nil {
+					return err
+				}
+				cache.Invalidate("EXAMPLE.com")
+				_, err := cache.GetAlias(ctx, migaduClient, "example.com", "second")
+				return err
+			},
+			want: []string{"/domains/example.com/aliases", "/domains/example.com/aliases"},
+		},
+		"idna": {
+			cache: provider.NewReadCache,
+			read: func(cache *provider.ReadCache) error {
+				if _, err := cache.GetAlias(ctx, migaduClient, "hoß.de", "some"); err != nil {
+					return err
+				}
+				_, err := cache.GetAlias(ctx, migaduClient, "xn--ho-hia.de", "some")
+				return err
+			},
+			want: []string{"/domains/xn--ho-hia.de/aliases"},
+		},
+		"mailboxes": {
+			cache: provider.NewReadCache,
+			read: func(cache *provider.ReadCache) error {
+				for _, localPart := range []string{"first", "second"} {
+					mailbox, err := cache.GetMailbox(ctx, migaduClient, "example.com", localPart)
+					if err != nil {
+						return err
+					}
+					assert.Equal(t, localPart, mailbox.LocalPart, "local part")
+				}
+				return nil
+			},
+			want: []string{"/domains/example.com/mailboxes"},
+		},
+		"rewrite-rules": {
+			cache: provider.NewReadCache,
+			read: func(cache *provider.ReadCache) error {
+				for _, name := range []string{"first", "second"} {
+					rewriteRule, err := cache.GetRewriteRule(ctx, migaduClient, "example.com", name)
+					if err != nil {
+						return err
+					}
+					assert.Equal(t, name, rewriteRule.Name, "name")
+				}
+				return nil
+			},
+			want: []string{"/domains/example.com/rewrites"},
+		},
+		"concurrent": {
+			cache: provider.NewReadCache,
+			read: func(cache *provider.ReadCache) error {
+				var group sync.WaitGroup
+				errs := make([]error, 10)
+				for index := range errs {
+					group.Add(1)
+					go func() {
+						defer group.Done()
+						_, errs[index] = cache.GetAlias(ctx, migaduClient, "example.com", "first")
+					}()
+				}
+				group.Wait()
+				return errors.Join(errs...)
+			},
+			want: []string{"/domains/example.com/aliases"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requests = nil
+			if err := testCase.read(testCase.cache()); err != nil {
+				t.Fatalf("Read error: %s", err)
+			}
+			assert.Equal(t, testCase.want, requests, "requests")
+		})
+	}
+}
+
+func TestReadCache_AliasResource(t *testing.T) {
+	ctx := context.Background()
+	var requests atomic.Int64
+	api := sandbox.New(sandbox.State{
+		Aliases: []model.Alias{
+			{LocalPart: "first", DomainName: "example.com", Address: "first@example.com", Destinations: []string{"other@example.com"}},
+			{LocalPart: "second", DomainName: "example.com", Address: "second@example.com", Destinations: []string{"other@example.com"}},
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		api.ServeHTTP(writer, request)
+	}))
+	defer server.Close()
+
+	providerServer, diagnostics := configureProviderServer(t, server.URL, map[string]tftypes.Value{
+		"read_cache": tftypes.NewValue(tftypes.Bool, true),
+	})
+	assertNoDiagnostics(t, diagnostics)
+	schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema error: %s", err)
+	}
+	resourceType := schemaResponse.ResourceSchemas["migadu_alias"].ValueType()
+
+	read := func(localPart string) tftypes.Value {
+		response := readResource(t, providerServer, &tfprotov6.ReadResourceRequest{
+			TypeName:     "migadu_alias",
+			CurrentState: dynamicValue(t, resourceType, aliasState(resourceType, localPart, "other@example.com")),
+		})
+		assertNoDiagnostics(t, response.Diagnostics)
+		state, err := response.NewState.Unmarshal(resourceType)
+		if err != nil {
+			t.Fatalf("Could not read state: %s", err)
+		}
+		return state
+	}
+
+	read("first")
+	read("second")
+	assert.Equal(t, int64(1), requests.Load(), "requests after refresh")
+
+	prior := aliasState(resourceType, "first", "other@example.com")
+	planned := aliasState(resourceType, "first", "changed@example.com")
+	response, err := providerServer.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     "migadu_alias",
+		PriorState:   dynamicValue(t, resourceType, prior),
+		PlannedState: dynamicValue(t, resourceType, planned),
+		Config:       dynamicValue(t, resourceType, planned),
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange error: %s", err)
+	}
+	assertNoDiagnostics(t, response.Diagnostics)
+	assert.Equal(t, int64(2), requests.Load(), "requests after update")
+
+	var attributes map[string]tftypes.Value
+	if err := read("first").As(&attributes); err != nil {
+		t.Fatalf("Could not read attributes: %s", err)
+	}
+	assert.Equal(t, int64(3), requests.Load(), "requests after invalidation")
+	assert.Equal(t, tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "changed@example.com"),
+	}), attributes["destinations"], "destinations")
+}
+
+// BenchmarkAliasResource_Read refreshes all aliases of a large domain with and without the read cache. The number of
+// API requests per refresh is reported as the 'requests/op' metric.
+func BenchmarkAliasResource_Read(b *testing.B) {
+	ctx := context.Background()
+	var aliases []model.Alias
+	for index := range 800 {
+		localPart := fmt.Sprintf("alias-%d", index)
+		aliases = append(aliases, model.Alias{
+			LocalPart:    localPart,
+			DomainName:   "example.com",
+			Address:      localPart + "@example.com",
+			Destinations: []string{"other@example.com"},
+		})
+	}
+	var requests atomic.Int64
+	api := sandbox.New(sandbox.State{Aliases: aliases})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		api.ServeHTTP(writer, request)
+	}))
+	defer server.Close()
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("read_cache=%t", cached), func(b *testing.B) {
+			requests.Store(0)
+			for b.Loop() {
+				providerServer, diagnostics := configureProviderServer(b, server.URL, map[string]tftypes.Value{
+					"read_cache": tftypes.NewValue(tftypes.Bool, cached),
+				})
+				if len(diagnostics) > 0 {
+					b.Fatalf("ConfigureProvider diagnostics: %v", diagnostics)
+				}
+				schemaResponse, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+				if err != nil {
+					b.Fatalf("GetProviderSchema error: %s", err)
+				}
+				resourceType := schemaResponse.ResourceSchemas["migadu_alias"].ValueType()
+
+				for _, alias := range aliases {
+					response := readResource(b, providerServer, &tfprotov6.ReadResourceRequest{
+						TypeName:     "migadu_alias",
+						CurrentState: dynamicValue(b, resourceType, aliasState(resourceType, alias.LocalPart, "other@example.com")),
+					})
+					if len(response.Diagnostics) > 0 {
+						b.Fatalf("ReadResource diagnostics: %v", response.Diagnostics)
+					}
+				}
+			}
+			b.ReportMetric(float64(requests.Load())/float64(b.N), "requests/op")
+		})
+	}
+}
+
+func aliasState(resourceType tftypes.Type, localPart string, destination string) tftypes.Value {
+	return objectValue(resourceType, map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, localPart+"@example.com"),
+		"local_part":  tftypes.NewValue(tftypes.String, localPart),
+		"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+		"address":     tftypes.NewValue(tftypes.String, localPart+"@example.com"),
+		"destinations": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, destination),
+		}),
+		"is_internal":        tftypes.NewValue(tftypes.Bool, false),
+		"expirable":          tftypes.NewValue(tftypes.Bool, false),
+		"expires_on":         tftypes.NewValue(tftypes.String, ""),
+		"remove_upon_expiry": tftypes.NewValue(tftypes.Bool, false),
+	})
+}
@@ -20,32 +20,29 @@ func CreateRewriteRuleIDString(domainName, name string) string {
 	return fmt.Sprintf("%s/%s", domainName, name)
 }
 
+// rewriteRuleAttributes maps the API fields of a rewrite rule to the attributes of the rewrite rule resource.
+var rewriteRuleAttributes = rootAttributes(map[string]string{
+	"name":            "name",
+	"domain_name":     "domain_name",
+	"local_part_rule": "local_part_rule",
+	"order_num":       "order_num",
+	"destinations":    "destinations",
+})
+
 func RewriteRuleCreateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Creating RewriteRule Rule",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Creating RewriteRule Rule", "rewrite rule", apiOperationCreate, err, rewriteRuleAttributes)
 }
 
 func RewriteRuleReadError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Reading RewriteRule Rule",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Reading RewriteRule Rule", "rewrite rule", apiOperationRead, err, nil)
 }
 
 func RewriteRuleUpdateError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Updating RewriteRule Rule",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Updating RewriteRule Rule", "rewrite rule", apiOperationUpdate, err, rewriteRuleAttributes)
 }
 
 func RewriteRuleDeleteError(err error) diag.Diagnostic {
-	return diag.NewErrorDiagnostic(
-		"Error Deleting RewriteRule Rule",
-		standardAPIErrorDetail(err),
-	)
+	return apiErrorDiagnostic("Error Deleting RewriteRule Rule", "rewrite rule", apiOperationDelete, err, nil)
 }
 
 func RewriteRuleImportError(id string) diag.Diagnostic {
@@ -54,3 +51,10 @@ func RewriteRuleImportError(id string) diag.Diagnostic {
 		standardImportErrorDetail("domain_name/name", id),
 	)
 }
+
+func RewriteRuleMoveStateError(err error) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Error Moving RewriteRule Rule State",
+		standardMoveStateErrorDetail(err),
+	)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
@@ -28,6 +29,7 @@ func NewRewriteRuleDataSource() datasource.DataSource {
 
 type RewriteRuleDataSource struct {
 	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
 }
 
 type RewriteRuleDataSourceModel struct {
@@ -111,12 +113,13 @@ func (d *RewriteRuleDataSource) Configure(_ context.Context, request datasource.
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.MigaduClient = providerData.MigaduClient
+		d.DomainScope = providerData.DomainScope
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -128,6 +131,11 @@ func (d *RewriteRuleDataSource) Read(ctx context.Context, request datasource.Rea
 		return
 	}
 
+	response.Diagnostics.Append(d.DomainScope.Diagnostics(path.Root("domain_name"), data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	rewrite, err := d.MigaduClient.GetRewriteRule(ctx, data.DomainName.ValueString(), data.Name.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(RewriteRuleReadError(err))
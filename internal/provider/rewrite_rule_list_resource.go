@@ -0,0 +1,135 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+)
+
+var (
+	_ list.ListResource              = (*RewriteRuleListResource)(nil)
+	_ list.ListResourceWithConfigure = (*RewriteRuleListResource)(nil)
+)
+
+func NewRewriteRuleListResource() list.ListResource {
+	return &RewriteRuleListResource{}
+}
+
+type RewriteRuleListResource struct {
+	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
+}
+
+type RewriteRuleListResourceModel struct {
+	DomainName custom_types.DomainNameValue `tfsdk:"domain_name"`
+}
+
+func (r *RewriteRuleListResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_rewrite_rule"
+}
+
+func (r *RewriteRuleListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, response *list.ListResourceSchemaResponse) {
+	response.Schema = schema.Schema{
+		Description:         "Lists all rewrite rules of a domain.",
+		MarkdownDescription: "Lists all rewrite rules of a domain.",
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				Description:         "The domain name of the rewrite rules.",
+				MarkdownDescription: "The domain name of the rewrite rules.",
+				Required:            true,
+				Optional:            false,
+				CustomType:          custom_types.DomainNameType{},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *RewriteRuleListResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.DomainScope = providerData.DomainScope
+	} else {
+		response.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+	}
+}
+
+func (r *RewriteRuleListResource) List(ctx context.Context, request list.ListRequest, stream *list.ListResultsStream) {
+	var config RewriteRuleListResourceModel
+	diags := request.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	diags = r.DomainScope.Diagnostics(path.Root("domain_name"), config.DomainName)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	rewriteRules, err := r.MigaduClient.GetRewriteRules(ctx, config.DomainName.ValueString())
+	if err != nil {
+		stream.Results = list.ListResultsStreamDiagnostics(diag.Diagnostics{RewriteRuleReadError(err)})
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for _, rewriteRule := range rewriteRules.RewriteRules {
+			result := request.NewListResult(ctx)
+			result.DisplayName = CreateRewriteRuleIDString(config.DomainName.ValueString(), rewriteRule.Name)
+
+			result.Diagnostics.Append(result.Identity.Set(ctx, RewriteRuleResourceIdentityModel{
+				DomainName: types.StringValue(config.DomainName.ValueString()),
+				Name:       types.StringValue(rewriteRule.Name),
+			})...)
+
+			if request.IncludeResource {
+				state, diags := newRewriteRuleResourceModel(ctx, config.DomainName, rewriteRule)
+				result.Diagnostics.Append(diags...)
+				result.Diagnostics.Append(result.Resource.Set(ctx, state)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+func newRewriteRuleResourceModel(ctx context.Context, domainName custom_types.DomainNameValue, rewriteRule model.RewriteRule) (RewriteRuleResourceModel, diag.Diagnostics) {
+	destinations, diags := custom_types.NewEmailAddressSetValueFrom(ctx, rewriteRule.Destinations)
+
+	return RewriteRuleResourceModel{
+		ID:            types.StringValue(CreateRewriteRuleIDString(domainName.ValueString(), rewriteRule.Name)),
+		DomainName:    domainName,
+		Name:          types.StringValue(rewriteRule.Name),
+		LocalPartRule: types.StringValue(rewriteRule.LocalPartRule),
+		OrderNum:      types.Int64Value(rewriteRule.OrderNum),
+		Destinations:  destinations,
+	}, diags
+}
@@ -0,0 +1,125 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package provider_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/migadu-client.go/simulator"
+	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestRewriteRuleListResource_Schema(t *testing.T) {
+	ctx := context.Background()
+	schemaRequest := list.ListResourceSchemaRequest{}
+	schemaResponse := &list.ListResourceSchemaResponse{}
+
+	provider.NewRewriteRuleListResource().ListResourceConfigSchema(ctx, schemaRequest, schemaResponse)
+
+	if schemaResponse.Diagnostics.HasError() {
+		t.Fatalf("Schema method diagnostics: %+v", schemaResponse.Diagnostics)
+	}
+
+	diagnostics := schemaResponse.Schema.ValidateImplementation(ctx)
+	if diagnostics.HasError() {
+		t.Fatalf("Schema validation diagnostics: %+v", diagnostics)
+	}
+}
+
+func TestRewriteRuleListResource_API_Success(t *testing.T) {
+	testCases := map[string]struct {
+		domain string
+		state  []model.RewriteRule
+		want   []ListedResource
+	}{
+		"empty": {
+			domain: "example.com",
+			state:  []model.RewriteRule{},
+			want:   nil,
+		},
+		"multiple": {
+			domain: "example.com",
+			state: []model.RewriteRule{
+				{
+					DomainName:    "example.com",
+					Name:          "first",
+					LocalPartRule: "first-*",
+					OrderNum:      1,
+					Destinations:  []string{"first@example.com"},
+				},
+				{
+					DomainName:    "example.com",
+					Name:          "second",
+					LocalPartRule: "second-*",
+					OrderNum:      2,
+					Destinations:  []string{"second@example.com", "other@example.com"},
+				},
+				{
+					DomainName:    "example.org",
+					Name:          "first",
+					LocalPartRule: "first-*",
+					OrderNum:      1,
+					Destinations:  []string{"first@example.org"},
+				},
+			},
+			want: []ListedResource{
+				{
+					DisplayName: "example.com/first",
+					ID:          "example.com/first",
+					Identity:    map[string]string{"domain_name": "example.com", "name": "first"},
+				},
+				{
+					DisplayName: "example.com/second",
+					ID:          "example.com/second",
+					Identity:    map[string]string{"domain_name": "example.com", "name": "second"},
+				},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{Rewrites: testCase.state}))
+			defer server.Close()
+
+			listed, diagnostics := listResources(t, server.URL, "migadu_rewrite_rule", map[string]string{"domain_name": testCase.domain})
+
+			assertNoDiagnostics(t, diagnostics)
+			assert.ElementsMatch(t, testCase.want, listed, "Listed")
+		})
+	}
+}
+
+func TestRewriteRuleListResource_API_Errors(t *testing.T) {
+	testCases := map[string]APIErrorTestCase{
+		"error-404": {
+			StatusCode: http.StatusNotFound,
+			ErrorRegex: "GetRewriteRules: status: 404",
+		},
+		"error-500": {
+			StatusCode: http.StatusInternalServerError,
+			ErrorRegex: "GetRewriteRules: status: 500",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(simulator.MigaduAPI(t, &simulator.State{StatusCode: testCase.StatusCode}))
+			defer server.Close()
+
+			listed, diagnostics := listResources(t, server.URL, "migadu_rewrite_rule", map[string]string{"domain_name": "example.com"})
+
+			assert.Empty(t, listed, "Listed")
+			if len(diagnostics) != 1 || !regexp.MustCompile(testCase.ErrorRegex).MatchString(diagnostics[0].Detail) {
+				t.Fatalf("Expected error matching %s, got: %+v", testCase.ErrorRegex, diagnostics)
+			}
+		})
+	}
+}
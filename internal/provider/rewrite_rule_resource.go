@@ -14,12 +14,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/metio/migadu-client.go/client"
 	"github.com/metio/migadu-client.go/model"
@@ -31,7 +33,10 @@ import (
 var (
 	_ resource.Resource                = (*RewriteRuleResource)(nil)
 	_ resource.ResourceWithConfigure   = (*RewriteRuleResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*RewriteRuleResource)(nil)
 	_ resource.ResourceWithImportState = (*RewriteRuleResource)(nil)
+	_ resource.ResourceWithIdentity    = (*RewriteRuleResource)(nil)
+	_ resource.ResourceWithMoveState   = (*RewriteRuleResource)(nil)
 )
 
 func NewRewriteRuleResource() resource.Resource {
@@ -40,6 +45,11 @@ func NewRewriteRuleResource() resource.Resource {
 
 type RewriteRuleResource struct {
 	MigaduClient *client.MigaduClient
+	ReadOnly     bool
+	DomainScope  DomainScope
+	Policy       Policy
+	AuditLog     *AuditLog
+	ReadCache    *ReadCache
 }
 
 type RewriteRuleResourceModel struct {
@@ -51,6 +61,11 @@ type RewriteRuleResourceModel struct {
 	Destinations  custom_types.EmailAddressSetValue `tfsdk:"destinations"`
 }
 
+type RewriteRuleResourceIdentityModel struct {
+	DomainName types.String `tfsdk:"domain_name"`
+	Name       types.String `tfsdk:"name"`
+}
+
 func (r *RewriteRuleResource) Metadata(_ context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
 	response.TypeName = request.ProviderTypeName + "_rewrite_rule"
 }
@@ -136,21 +151,46 @@ func (r *RewriteRuleResource) Schema(_ context.Context, _ resource.SchemaRequest
 		},
 	}
 }
+
+func (r *RewriteRuleResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, response *resource.IdentitySchemaResponse) {
+	response.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"domain_name": identityschema.StringAttribute{
+				Description:       "The domain name of the rewrite rule.",
+				RequiredForImport: true,
+			},
+			"name": identityschema.StringAttribute{
+				Description:       "The name of the rewrite rule.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
 func (r *RewriteRuleResource) Configure(_ context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if request.ProviderData == nil {
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		r.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		r.MigaduClient = providerData.MigaduClient
+		r.ReadOnly = providerData.ReadOnly
+		r.DomainScope = providerData.DomainScope
+		r.Policy = providerData.Policy
+		r.AuditLog = providerData.AuditLog
+		r.ReadCache = providerData.ReadCache
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
 
+func (r *RewriteRuleResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	response.Diagnostics.Append(resourcePlanDiagnostics(ctx, r.ReadOnly, r.DomainScope, r.Policy, "migadu_rewrite_rule", "Rewrite Rule", request)...)
+}
+
 func (r *RewriteRuleResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 	var plan RewriteRuleResourceModel
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
@@ -174,7 +214,9 @@ func (r *RewriteRuleResource) Create(ctx context.Context, request resource.Creat
 	}
 
 	createdRewrite, err := r.MigaduClient.CreateRewriteRule(ctx, plan.DomainName.ValueString(), rewrite)
+	r.ReadCache.Invalidate(plan.DomainName.ValueString())
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_rewrite_rule", AuditOperationCreate, CreateRewriteRuleID(plan.DomainName, plan.Name), tftypes.Value{}, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(RewriteRuleCreateError(err))
 		return
 	}
@@ -184,7 +226,12 @@ func (r *RewriteRuleResource) Create(ctx context.Context, request resource.Creat
 	plan.LocalPartRule = types.StringValue(createdRewrite.LocalPartRule)
 	plan.OrderNum = types.Int64Value(createdRewrite.OrderNum)
 
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_rewrite_rule", AuditOperationCreate, CreateRewriteRuleID(plan.DomainName, plan.Name), tftypes.Value{}, auditState(ctx, request.Plan, plan), nil)...)
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, RewriteRuleResourceIdentityModel{
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+		Name:       plan.Name,
+	})...)
 }
 
 func (r *RewriteRuleResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
@@ -194,7 +241,7 @@ func (r *RewriteRuleResource) Read(ctx context.Context, request resource.ReadReq
 		return
 	}
 
-	rewrite, err := r.MigaduClient.GetRewriteRule(ctx, state.DomainName.ValueString(), state.Name.ValueString())
+	rewrite, err := r.ReadCache.GetRewriteRule(ctx, r.MigaduClient, state.DomainName.ValueString(), state.Name.ValueString())
 	if err != nil {
 		var requestError *client.RequestError
 		if errors.As(err, &requestError) {
@@ -223,6 +270,10 @@ func (r *RewriteRuleResource) Read(ctx context.Context, request resource.ReadReq
 	state.OrderNum = types.Int64Value(rewrite.OrderNum)
 
 	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, RewriteRuleResourceIdentityModel{
+		DomainName: types.StringValue(state.DomainName.ValueString()),
+		Name:       state.Name,
+	})...)
 }
 
 func (r *RewriteRuleResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
@@ -246,7 +297,9 @@ func (r *RewriteRuleResource) Update(ctx context.Context, request resource.Updat
 	}
 
 	updatedRewrite, err := r.MigaduClient.UpdateRewriteRule(ctx, plan.DomainName.ValueString(), plan.Name.ValueString(), rewrite)
+	r.ReadCache.Invalidate(plan.DomainName.ValueString())
 	if err != nil {
+		response.Diagnostics.Append(r.AuditLog.Record("migadu_rewrite_rule", AuditOperationUpdate, CreateRewriteRuleID(plan.DomainName, plan.Name), request.State.Raw, tftypes.Value{}, err)...)
 		response.Diagnostics.Append(RewriteRuleUpdateError(err))
 		return
 	}
@@ -256,7 +309,12 @@ func (r *RewriteRuleResource) Update(ctx context.Context, request resource.Updat
 	plan.LocalPartRule = types.StringValue(updatedRewrite.LocalPartRule)
 	plan.OrderNum = types.Int64Value(updatedRewrite.OrderNum)
 
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_rewrite_rule", AuditOperationUpdate, CreateRewriteRuleID(plan.DomainName, plan.Name), request.State.Raw, auditState(ctx, request.Plan, plan), nil)...)
 	response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+	response.Diagnostics.Append(response.Identity.Set(ctx, RewriteRuleResourceIdentityModel{
+		DomainName: types.StringValue(plan.DomainName.ValueString()),
+		Name:       plan.Name,
+	})...)
 }
 
 func (r *RewriteRuleResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
@@ -267,6 +325,8 @@ func (r *RewriteRuleResource) Delete(ctx context.Context, request resource.Delet
 	}
 
 	_, err := r.MigaduClient.DeleteRewriteRule(ctx, state.DomainName.ValueString(), state.Name.ValueString())
+	response.Diagnostics.Append(r.AuditLog.Record("migadu_rewrite_rule", AuditOperationDelete, CreateRewriteRuleID(state.DomainName, state.Name), request.State.Raw, tftypes.Value{}, err)...)
+	r.ReadCache.Invalidate(state.DomainName.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(RewriteRuleDeleteError(err))
 		return
@@ -274,22 +334,64 @@ func (r *RewriteRuleResource) Delete(ctx context.Context, request resource.Delet
 }
 
 func (r *RewriteRuleResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
-	idParts := strings.Split(request.ID, "/")
+	var domainName, name string
 
-	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-		response.Diagnostics.Append(RewriteRuleImportError(request.ID))
-		return
+	if request.ID != "" {
+		idParts := strings.Split(request.ID, "/")
+
+		if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+			response.Diagnostics.Append(RewriteRuleImportError(request.ID))
+			return
+		}
+
+		domainName = idParts[0]
+		name = idParts[1]
+	} else {
+		var identity RewriteRuleResourceIdentityModel
+		response.Diagnostics.Append(request.Identity.Get(ctx, &identity)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		domainName = identity.DomainName.ValueString()
+		name = identity.Name.ValueString()
+
+		if domainName == "" || name == "" {
+			response.Diagnostics.Append(RewriteRuleImportError(CreateRewriteRuleIDString(domainName, name)))
+			return
+		}
 	}
 
-	domainName := idParts[0]
-	name := idParts[1]
 	tflog.Trace(ctx, "parsed import ID", map[string]interface{}{
 		"domain_name": domainName,
 		"name":        name,
 	})
 
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), request, response)
-
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), CreateRewriteRuleIDString(domainName, name))...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("domain_name"), domainName)...)
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("name"), name)...)
 }
+
+func (r *RewriteRuleResource) MoveState(_ context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			// migadu_rewrite was renamed to migadu_rewrite_rule in 2023.8.23, see MIGRATIONS.md
+			StateMover: func(ctx context.Context, request resource.MoveStateRequest, response *resource.MoveStateResponse) {
+				if request.SourceTypeName != "migadu_rewrite" || !strings.HasSuffix(request.SourceProviderAddress, "metio/migadu") {
+					return
+				}
+
+				movedState, err := convertRawState(ctx, r, request.SourceRawState, []string{
+					"destinations_punycode",
+				}, []string{
+					"destinations",
+				})
+				if err != nil {
+					response.Diagnostics.Append(RewriteRuleMoveStateError(err))
+					return
+				}
+				response.TargetState.Raw = movedState
+			},
+		},
+	}
+}
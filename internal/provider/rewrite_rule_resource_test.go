@@ -9,10 +9,13 @@ import (
 	"context"
 	"fmt"
 	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -484,3 +487,115 @@ func TestRewriteRuleResource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestRewriteRuleResource_MoveState(t *testing.T) {
+	testCases := map[string]struct {
+		sourceTypeName        string
+		sourceProviderAddress string
+		rawState              string
+		moved                 bool
+		want                  []string
+	}{
+		"migadu_rewrite": {
+			sourceTypeName:        "migadu_rewrite",
+			sourceProviderAddress: "registry.terraform.io/metio/migadu",
+			rawState: `{
+				"id": "hoß.de/test",
+				"domain_name": "hoß.de",
+				"name": "test",
+				"local_part_rule": "prefix-*",
+				"order_num": 5,
+				"destinations": ["some@hoß.de", "other@example.com", "some@hoß.de"],
+				"destinations_punycode": ["some@xn--ho-hia.de", "other@example.com", "some@xn--ho-hia.de"]
+			}`,
+			moved: true,
+			want:  []string{"other@example.com", "some@hoß.de"},
+		},
+		"other-provider": {
+			sourceTypeName:        "migadu_rewrite",
+			sourceProviderAddress: "registry.terraform.io/example/migadu",
+			rawState:              `{"id": "example.com/test"}`,
+			moved:                 false,
+		},
+		"other-resource": {
+			sourceTypeName:        "migadu_alias",
+			sourceProviderAddress: "registry.terraform.io/metio/migadu",
+			rawState:              `{"id": "test@example.com"}`,
+			moved:                 false,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := provider.NewRewriteRuleResource()
+			schemaResponse := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResponse)
+
+			request := fwresource.MoveStateRequest{
+				SourceTypeName:        testCase.sourceTypeName,
+				SourceProviderAddress: testCase.sourceProviderAddress,
+				SourceRawState:        &tfprotov6.RawState{JSON: []byte(testCase.rawState)},
+			}
+			response := &fwresource.MoveStateResponse{
+				TargetState: tfsdk.State{
+					Schema: schemaResponse.Schema,
+				},
+			}
+			r.(fwresource.ResourceWithMoveState).MoveState(ctx)[0].StateMover(ctx, request, response)
+			if response.Diagnostics.HasError() {
+				t.Fatalf("MoveState diagnostics: %+v", response.Diagnostics)
+			}
+
+			if !testCase.moved {
+				assert.True(t, response.TargetState.Raw.IsNull(), "TargetState")
+				return
+			}
+
+			var rule provider.RewriteRuleResourceModel
+			diagnostics := response.TargetState.Get(ctx, &rule)
+			if diagnostics.HasError() {
+				t.Fatalf("Could not read moved state: %+v", diagnostics)
+			}
+
+			var destinations []string
+			diagnostics = rule.Destinations.ElementsAs(ctx, &destinations, false)
+			if diagnostics.HasError() {
+				t.Fatalf("Could not read destinations: %+v", diagnostics)
+			}
+
+			assert.Equal(t, "test", rule.Name.ValueString(), "Name")
+			assert.Equal(t, "prefix-*", rule.LocalPartRule.ValueString(), "LocalPartRule")
+			assert.Equal(t, int64(5), rule.OrderNum.ValueInt64(), "OrderNum")
+			assert.ElementsMatch(t, testCase.want, destinations, "Destinations")
+		})
+	}
+}
+
+func TestRewriteRuleResource_Identity(t *testing.T) {
+	state := &simulator.State{
+		Rewrites: []model.RewriteRule{
+			{
+				DomainName:    "example.com",
+				Name:          "test",
+				LocalPartRule: "prefix-*",
+				OrderNum:      5,
+				Destinations:  []string{"dest@example.com"},
+			},
+		},
+	}
+	server := httptest.NewServer(simulator.MigaduAPI(t, state))
+	defer server.Close()
+
+	testResourceIdentity(t, server.URL, ResourceIdentityTestCase{
+		TypeName: "migadu_rewrite_rule",
+		ImportID: "example.com/test",
+		Identity: map[string]string{
+			"domain_name": "example.com",
+			"name":        "test",
+		},
+		ChangedIdentity: map[string]string{
+			"domain_name": "example.com",
+			"name":        "other",
+		},
+	})
+}
@@ -6,15 +6,19 @@
 package provider
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/metio/migadu-client.go/client"
 	"github.com/metio/terraform-provider-migadu/internal/provider/custom_types"
+	"slices"
+	"strings"
 )
 
 var (
@@ -28,12 +32,16 @@ func NewRewriteRulesDataSource() datasource.DataSource {
 
 type RewriteRulesDataSource struct {
 	MigaduClient *client.MigaduClient
+	DomainScope  DomainScope
 }
 
 type RewriteRulesDataSourceModel struct {
-	ID         custom_types.DomainNameValue `tfsdk:"id"`
-	DomainName custom_types.DomainNameValue `tfsdk:"domain_name"`
-	Rewrites   []RewriteRuleModel           `tfsdk:"rewrites"`
+	ID             custom_types.DomainNameValue   `tfsdk:"id"`
+	DomainName     custom_types.DomainNameValue   `tfsdk:"domain_name"`
+	NameRegex      types.String                   `tfsdk:"name_regex"`
+	Destination    custom_types.EmailAddressValue `tfsdk:"destination"`
+	Rewrites       []RewriteRuleModel             `tfsdk:"rewrites"`
+	RewritesByName map[string]RewriteRuleModel    `tfsdk:"rewrites_by_name"`
 }
 
 type RewriteRuleModel struct {
@@ -72,56 +80,24 @@ func (d *RewriteRulesDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"name_regex":  regexFilterAttribute("Only return rewrite rules whose name matches this regular expression."),
+			"destination": destinationFilterAttribute("rewrite rules"),
 			"rewrites": schema.ListNestedAttribute{
-				Description:         "The configured rewrite rules for the given 'domain_name'.",
-				MarkdownDescription: "The configured rewrite rules for the given `domain_name`.",
+				Description:         "The configured rewrite rules for the given 'domain_name' sorted by their order number and name.",
+				MarkdownDescription: "The configured rewrite rules for the given `domain_name` sorted by their order number and name.",
 				Required:            false,
 				Optional:            false,
 				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"local_part_rule": schema.StringAttribute{
-							Description:         "The local part expression of the rewrite rule",
-							MarkdownDescription: "The local part expression of the rewrite rule",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"domain_name": schema.StringAttribute{
-							Description:         "The domain of the rewrite rule.",
-							MarkdownDescription: "The domain of the rewrite rule.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-							CustomType:          custom_types.DomainNameType{},
-						},
-						"name": schema.StringAttribute{
-							Description:         "The name (slug) of the rewrite rule.",
-							MarkdownDescription: "The name (slug) of the rewrite rule.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"order_num": schema.Int64Attribute{
-							Description:         "The order number of the rewrite rule.",
-							MarkdownDescription: "The order number of the rewrite rule.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-						},
-						"destinations": schema.SetAttribute{
-							Description:         "The destinations of the rewrite rule.",
-							MarkdownDescription: "The destinations of the rewrite rule.",
-							Required:            false,
-							Optional:            false,
-							Computed:            true,
-							CustomType: custom_types.EmailAddressSetType{
-								SetType: types.SetType{
-									ElemType: custom_types.EmailAddressType{},
-								},
-							},
-						},
-					},
+					Attributes: rewriteRuleModelAttributes(),
+				},
+			},
+			"rewrites_by_name": schema.MapNestedAttribute{
+				Description:         "The configured rewrite rules for the given 'domain_name' keyed by their name.",
+				MarkdownDescription: "The configured rewrite rules for the given `domain_name` keyed by their name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: rewriteRuleModelAttributes(),
 				},
 			},
 		},
@@ -133,12 +109,13 @@ func (d *RewriteRulesDataSource) Configure(_ context.Context, request datasource
 		return
 	}
 
-	if migaduClient, ok := request.ProviderData.(*client.MigaduClient); ok {
-		d.MigaduClient = migaduClient
+	if providerData, ok := request.ProviderData.(*ProviderData); ok {
+		d.MigaduClient = providerData.MigaduClient
+		d.DomainScope = providerData.DomainScope
 	} else {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.MigaduClient, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 	}
 }
@@ -150,6 +127,17 @@ func (d *RewriteRulesDataSource) Read(ctx context.Context, request datasource.Re
 		return
 	}
 
+	response.Diagnostics.Append(d.DomainScope.Diagnostics(path.Root("domain_name"), data.DomainName)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	nameRegex := regexFilter(path.Root("name_regex"), data.NameRegex, &response.Diagnostics)
+	destination := destinationFilter(path.Root("destination"), data.Destination, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	rewrites, err := d.MigaduClient.GetRewriteRules(ctx, data.DomainName.ValueString())
 	if err != nil {
 		response.Diagnostics.Append(RewriteRuleReadError(err))
@@ -157,6 +145,10 @@ func (d *RewriteRulesDataSource) Read(ctx context.Context, request datasource.Re
 	}
 
 	for _, rewrite := range rewrites.RewriteRules {
+		if !matchesRegex(nameRegex, rewrite.Name) || !matchesDestination(destination, rewrite.Destinations) {
+			continue
+		}
+
 		destinations, diags := custom_types.NewEmailAddressSetValueFrom(ctx, rewrite.Destinations)
 		response.Diagnostics.Append(diags...)
 		if response.Diagnostics.HasError() {
@@ -174,7 +166,67 @@ func (d *RewriteRulesDataSource) Read(ctx context.Context, request datasource.Re
 		data.Rewrites = append(data.Rewrites, model)
 	}
 
+	slices.SortFunc(data.Rewrites, func(a, b RewriteRuleModel) int {
+		return cmp.Or(
+			cmp.Compare(a.OrderNum.ValueInt64(), b.OrderNum.ValueInt64()),
+			strings.Compare(a.Name.ValueString(), b.Name.ValueString()),
+		)
+	})
+	if data.Rewrites != nil {
+		data.RewritesByName = make(map[string]RewriteRuleModel, len(data.Rewrites))
+		for _, rewrite := range data.Rewrites {
+			data.RewritesByName[rewrite.Name.ValueString()] = rewrite
+		}
+	}
+
 	data.ID = data.DomainName
 
 	response.Diagnostics.Append(response.State.Set(ctx, &data)...)
 }
+
+// rewriteRuleModelAttributes returns the computed attributes of a single rewrite rule as used by the data sources.
+func rewriteRuleModelAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"local_part_rule": schema.StringAttribute{
+			Description:         "The local part expression of the rewrite rule",
+			MarkdownDescription: "The local part expression of the rewrite rule",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"domain_name": schema.StringAttribute{
+			Description:         "The domain of the rewrite rule.",
+			MarkdownDescription: "The domain of the rewrite rule.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+			CustomType:          custom_types.DomainNameType{},
+		},
+		"name": schema.StringAttribute{
+			Description:         "The name (slug) of the rewrite rule.",
+			MarkdownDescription: "The name (slug) of the rewrite rule.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"order_num": schema.Int64Attribute{
+			Description:         "The order number of the rewrite rule.",
+			MarkdownDescription: "The order number of the rewrite rule.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+		},
+		"destinations": schema.SetAttribute{
+			Description:         "The destinations of the rewrite rule.",
+			MarkdownDescription: "The destinations of the rewrite rule.",
+			Required:            false,
+			Optional:            false,
+			Computed:            true,
+			CustomType: custom_types.EmailAddressSetType{
+				SetType: types.SetType{
+					ElemType: custom_types.EmailAddressType{},
+				},
+			},
+		},
+	}
+}
@@ -9,10 +9,13 @@ import (
 	"context"
 	"fmt"
 	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/metio/migadu-client.go/model"
 	"github.com/metio/migadu-client.go/simulator"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -312,3 +315,25 @@ func TestRewriteRulesDataSource_Configuration_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestRewriteRulesDataSource_Keyed(t *testing.T) {
+	server := httptest.NewServer(sandbox.New(sandbox.State{
+		RewriteRules: []model.RewriteRule{
+			{Name: "support", DomainName: "example.com", LocalPartRule: "support-*", OrderNum: 2},
+			{Name: "sales", DomainName: "example.com", LocalPartRule: "sales-*", OrderNum: 1},
+			{Name: "billing", DomainName: "example.com", LocalPartRule: "billing-*", OrderNum: 2},
+		},
+	}))
+	defer server.Close()
+
+	attributes := readDataSource(t, configuredProviderServerWith(t, server.URL, nil), "migadu_rewrite_rules", map[string]tftypes.Value{
+		"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+	})
+
+	assert.Equal(t, []string{"sales", "billing", "support"}, nestedStrings(t, attributes["rewrites"], "name"), "rewrites")
+	assert.Equal(t, map[string]string{
+		"billing": "billing-*",
+		"sales":   "sales-*",
+		"support": "support-*",
+	}, nestedStringsByKey(t, attributes["rewrites_by_name"], "local_part_rule"), "rewrites_by_name")
+}
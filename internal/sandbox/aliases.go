@@ -0,0 +1,102 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package sandbox
+
+import (
+	"fmt"
+	"github.com/metio/migadu-client.go/model"
+	"net/http"
+	"slices"
+)
+
+func (a *API) findAlias(domain string, localPart string) int {
+	return slices.IndexFunc(a.state.Aliases, func(alias model.Alias) bool {
+		return alias.DomainName == domain && alias.LocalPart == localPart
+	})
+}
+
+func (a *API) getAliases(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	found := make([]model.Alias, 0)
+	for _, alias := range a.state.Aliases {
+		if alias.DomainName == domain {
+			found = append(found, alias)
+		}
+	}
+	writeJson(w, model.Aliases{Aliases: found})
+}
+
+func (a *API) getAlias(w http.ResponseWriter, r *http.Request) {
+	index := a.findAlias(r.PathValue("domain"), r.PathValue("localPart"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "alias not found")
+		return
+	}
+	writeJson(w, a.state.Aliases[index])
+}
+
+func (a *API) createAlias(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	var alias model.Alias
+	if !readJson(w, r, &alias) {
+		return
+	}
+	if alias.LocalPart == "" || len(alias.Destinations) == 0 {
+		writeError(w, http.StatusBadRequest, "local_part and destinations are required")
+		return
+	}
+	if a.findAlias(domain, alias.LocalPart) >= 0 {
+		writeError(w, http.StatusConflict, "alias already exists")
+		return
+	}
+
+	alias.DomainName = domain
+	alias.Address = fmt.Sprintf("%s@%s", alias.LocalPart, domain)
+
+	a.state.Aliases = append(a.state.Aliases, alias)
+	a.writeChange(w, alias)
+}
+
+func (a *API) updateAlias(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	localPart := r.PathValue("localPart")
+
+	index := a.findAlias(domain, localPart)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "alias not found")
+		return
+	}
+
+	var alias model.Alias
+	if !readJson(w, r, &alias) {
+		return
+	}
+	if len(alias.Destinations) == 0 {
+		writeError(w, http.StatusBadRequest, "destinations are required")
+		return
+	}
+
+	alias.LocalPart = localPart
+	alias.DomainName = domain
+	alias.Address = fmt.Sprintf("%s@%s", localPart, domain)
+
+	a.state.Aliases[index] = alias
+	a.writeChange(w, alias)
+}
+
+func (a *API) deleteAlias(w http.ResponseWriter, r *http.Request) {
+	index := a.findAlias(r.PathValue("domain"), r.PathValue("localPart"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "alias not found")
+		return
+	}
+
+	alias := a.state.Aliases[index]
+	a.state.Aliases = slices.Delete(a.state.Aliases, index, index+1)
+	a.writeChange(w, alias)
+}
@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package sandbox
+
+import (
+	"fmt"
+	"github.com/metio/migadu-client.go/model"
+	"net/http"
+	"slices"
+)
+
+func (a *API) findIdentity(domain string, localPart string, id string) int {
+	return slices.IndexFunc(a.state.Identities, func(identity Identity) bool {
+		return identity.DomainName == domain && identity.Mailbox == localPart && identity.LocalPart == id
+	})
+}
+
+func (a *API) getIdentities(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	localPart := r.PathValue("localPart")
+
+	if a.findMailbox(domain, localPart) < 0 {
+		writeError(w, http.StatusNotFound, "mailbox not found")
+		return
+	}
+
+	found := make([]model.Identity, 0)
+	for _, identity := range a.state.Identities {
+		if identity.DomainName == domain && identity.Mailbox == localPart {
+			found = append(found, identity.Identity)
+		}
+	}
+	writeJson(w, model.Identities{Identities: found})
+}
+
+func (a *API) getIdentity(w http.ResponseWriter, r *http.Request) {
+	index := a.findIdentity(r.PathValue("domain"), r.PathValue("localPart"), r.PathValue("id"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "identity not found")
+		return
+	}
+	writeJson(w, a.state.Identities[index].Identity)
+}
+
+func (a *API) createIdentity(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	localPart := r.PathValue("localPart")
+
+	if a.findMailbox(domain, localPart) < 0 {
+		writeError(w, http.StatusNotFound, "mailbox not found")
+		return
+	}
+
+	var identity model.Identity
+	if !readJson(w, r, &identity) {
+		return
+	}
+	if identity.LocalPart == "" || identity.Name == "" {
+		writeError(w, http.StatusBadRequest, "local_part and name are required")
+		return
+	}
+	if a.findIdentity(domain, localPart, identity.LocalPart) >= 0 {
+		writeError(w, http.StatusConflict, "identity already exists")
+		return
+	}
+
+	identity.DomainName = domain
+	identity.Address = fmt.Sprintf("%s@%s", identity.LocalPart, domain)
+	identity.Password = ""
+
+	a.state.Identities = append(a.state.Identities, Identity{Mailbox: localPart, Identity: identity})
+	a.writeChange(w, identity)
+}
+
+func (a *API) updateIdentity(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	localPart := r.PathValue("localPart")
+	id := r.PathValue("id")
+
+	index := a.findIdentity(domain, localPart, id)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "identity not found")
+		return
+	}
+
+	var identity model.Identity
+	if !readJson(w, r, &identity) {
+		return
+	}
+	if identity.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	identity.LocalPart = id
+	identity.DomainName = domain
+	identity.Address = fmt.Sprintf("%s@%s", id, domain)
+	identity.Password = ""
+
+	a.state.Identities[index] = Identity{Mailbox: localPart, Identity: identity}
+	a.writeChange(w, identity)
+}
+
+func (a *API) deleteIdentity(w http.ResponseWriter, r *http.Request) {
+	index := a.findIdentity(r.PathValue("domain"), r.PathValue("localPart"), r.PathValue("id"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "identity not found")
+		return
+	}
+
+	identity := a.state.Identities[index].Identity
+	a.state.Identities = slices.Delete(a.state.Identities, index, index+1)
+	a.writeChange(w, identity)
+}
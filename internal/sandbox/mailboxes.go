@@ -0,0 +1,116 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package sandbox
+
+import (
+	"fmt"
+	"github.com/metio/migadu-client.go/model"
+	"net/http"
+	"slices"
+)
+
+func (a *API) findMailbox(domain string, localPart string) int {
+	return slices.IndexFunc(a.state.Mailboxes, func(mailbox model.Mailbox) bool {
+		return mailbox.DomainName == domain && mailbox.LocalPart == localPart
+	})
+}
+
+func (a *API) getMailboxes(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	found := make([]model.Mailbox, 0)
+	for _, mailbox := range a.state.Mailboxes {
+		if mailbox.DomainName == domain {
+			found = append(found, mailbox)
+		}
+	}
+	writeJson(w, model.Mailboxes{Mailboxes: found})
+}
+
+func (a *API) getMailbox(w http.ResponseWriter, r *http.Request) {
+	index := a.findMailbox(r.PathValue("domain"), r.PathValue("localPart"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "mailbox not found")
+		return
+	}
+	writeJson(w, a.state.Mailboxes[index])
+}
+
+func (a *API) createMailbox(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	var mailbox model.Mailbox
+	if !readJson(w, r, &mailbox) {
+		return
+	}
+	if mailbox.LocalPart == "" || mailbox.Name == "" {
+		writeError(w, http.StatusBadRequest, "local_part and name are required")
+		return
+	}
+	if mailbox.PasswordMethod == "invitation" && mailbox.PasswordRecoveryEmail == "" {
+		writeError(w, http.StatusBadRequest, "password_recovery_email is required for invitations")
+		return
+	}
+	if a.findMailbox(domain, mailbox.LocalPart) >= 0 {
+		writeError(w, http.StatusConflict, "mailbox already exists")
+		return
+	}
+
+	mailbox.DomainName = domain
+	mailbox.Address = fmt.Sprintf("%s@%s", mailbox.LocalPart, domain)
+	mailbox.Password = ""
+	mailbox.Identities = nil
+
+	a.state.Mailboxes = append(a.state.Mailboxes, mailbox)
+	a.writeChange(w, mailbox)
+}
+
+func (a *API) updateMailbox(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	localPart := r.PathValue("localPart")
+
+	index := a.findMailbox(domain, localPart)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "mailbox not found")
+		return
+	}
+
+	var mailbox model.Mailbox
+	if !readJson(w, r, &mailbox) {
+		return
+	}
+	if mailbox.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	mailbox.LocalPart = localPart
+	mailbox.DomainName = domain
+	mailbox.Address = fmt.Sprintf("%s@%s", localPart, domain)
+	mailbox.Password = ""
+	mailbox.Identities = nil
+
+	a.state.Mailboxes[index] = mailbox
+	a.writeChange(w, mailbox)
+}
+
+func (a *API) deleteMailbox(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	localPart := r.PathValue("localPart")
+
+	index := a.findMailbox(domain, localPart)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "mailbox not found")
+		return
+	}
+
+	mailbox := a.state.Mailboxes[index]
+	a.state.Mailboxes = slices.Delete(a.state.Mailboxes, index, index+1)
+	a.state.Identities = slices.DeleteFunc(a.state.Identities, func(identity Identity) bool {
+		return identity.DomainName == domain && identity.Mailbox == localPart
+	})
+	a.writeChange(w, mailbox)
+}
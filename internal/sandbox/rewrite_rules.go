@@ -0,0 +1,129 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package sandbox
+
+import (
+	"github.com/metio/migadu-client.go/model"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// rewriteRuleRequest is the format used by clients to send rewrite rules. Unlike responses, requests contain the
+// destinations as a single comma separated string.
+type rewriteRuleRequest struct {
+	Name          string `json:"name"`
+	LocalPartRule string `json:"local_part_rule"`
+	OrderNum      int64  `json:"order_num"`
+	Destinations  string `json:"destinations"`
+}
+
+func (a *API) findRewriteRule(domain string, slug string) int {
+	return slices.IndexFunc(a.state.RewriteRules, func(rewriteRule model.RewriteRule) bool {
+		return rewriteRule.DomainName == domain && rewriteRule.Name == slug
+	})
+}
+
+func (a *API) getRewriteRules(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	found := make([]model.RewriteRule, 0)
+	for _, rewriteRule := range a.state.RewriteRules {
+		if rewriteRule.DomainName == domain {
+			found = append(found, rewriteRule)
+		}
+	}
+	writeJson(w, model.RewriteRules{RewriteRules: found})
+}
+
+func (a *API) getRewriteRule(w http.ResponseWriter, r *http.Request) {
+	index := a.findRewriteRule(r.PathValue("domain"), r.PathValue("slug"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "rewrite rule not found")
+		return
+	}
+	writeJson(w, a.state.RewriteRules[index])
+}
+
+func (a *API) createRewriteRule(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	var request rewriteRuleRequest
+	if !readJson(w, r, &request) {
+		return
+	}
+	if request.Name == "" || request.LocalPartRule == "" || request.Destinations == "" {
+		writeError(w, http.StatusBadRequest, "name, local_part_rule, and destinations are required")
+		return
+	}
+	if a.findRewriteRule(domain, request.Name) >= 0 {
+		writeError(w, http.StatusConflict, "rewrite rule already exists")
+		return
+	}
+
+	rewriteRule := model.RewriteRule{
+		DomainName:    domain,
+		Name:          request.Name,
+		LocalPartRule: request.LocalPartRule,
+		OrderNum:      request.OrderNum,
+		Destinations:  strings.Split(request.Destinations, ","),
+	}
+
+	a.state.RewriteRules = append(a.state.RewriteRules, rewriteRule)
+	a.writeChange(w, rewriteRule)
+}
+
+func (a *API) updateRewriteRule(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	slug := r.PathValue("slug")
+
+	index := a.findRewriteRule(domain, slug)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "rewrite rule not found")
+		return
+	}
+
+	var request rewriteRuleRequest
+	if !readJson(w, r, &request) {
+		return
+	}
+	if request.LocalPartRule == "" || request.Destinations == "" {
+		writeError(w, http.StatusBadRequest, "local_part_rule and destinations are required")
+		return
+	}
+
+	name := slug
+	if request.Name != "" && request.Name != slug {
+		if a.findRewriteRule(domain, request.Name) >= 0 {
+			writeError(w, http.StatusConflict, "rewrite rule already exists")
+			return
+		}
+		name = request.Name
+	}
+
+	rewriteRule := model.RewriteRule{
+		DomainName:    domain,
+		Name:          name,
+		LocalPartRule: request.LocalPartRule,
+		OrderNum:      request.OrderNum,
+		Destinations:  strings.Split(request.Destinations, ","),
+	}
+
+	a.state.RewriteRules[index] = rewriteRule
+	a.writeChange(w, rewriteRule)
+}
+
+func (a *API) deleteRewriteRule(w http.ResponseWriter, r *http.Request) {
+	index := a.findRewriteRule(r.PathValue("domain"), r.PathValue("slug"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "rewrite rule not found")
+		return
+	}
+
+	rewriteRule := a.state.RewriteRules[index]
+	a.state.RewriteRules = slices.Delete(a.state.RewriteRules, index, index+1)
+	a.writeChange(w, rewriteRule)
+}
@@ -0,0 +1,239 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+// Package sandbox implements an in-process fake of the Migadu API. It allows to plan and apply configurations
+// without a Migadu account, for example to test modules offline.
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/metio/migadu-client.go/model"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// MemoryScheme selects a sandbox that keeps its state for the lifetime of the process.
+	MemoryScheme = "memory"
+	// FileScheme selects a sandbox that keeps its state in a JSON file.
+	FileScheme = "file"
+	// Endpoint is the API endpoint clients must use to reach a sandbox through its transport.
+	Endpoint = "http://sandbox.invalid"
+)
+
+// State contains all objects known to a sandbox. It is the format of the JSON file of a file sandbox.
+type State struct {
+	Mailboxes    []model.Mailbox     `json:"mailboxes"`
+	Identities   []Identity          `json:"identities"`
+	Aliases      []model.Alias       `json:"aliases"`
+	RewriteRules []model.RewriteRule `json:"rewrite_rules"`
+}
+
+// Identity is an identity along with the local part of the mailbox it belongs to.
+type Identity struct {
+	Mailbox string `json:"mailbox"`
+	model.Identity
+}
+
+// API is a fake of the Migadu API. It can be used as an http.Handler as well as an http.RoundTripper.
+type API struct {
+	mutex   sync.Mutex
+	state   State
+	path    string
+	handler http.Handler
+}
+
+var (
+	memoryMutex     sync.Mutex
+	memorySandboxes = make(map[string]*API)
+)
+
+// IsEndpoint returns true if the given endpoint selects a sandbox instead of a real API endpoint.
+func IsEndpoint(endpoint string) bool {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	return parsed.Scheme == MemoryScheme || parsed.Scheme == FileScheme
+}
+
+// Open returns the sandbox selected by the given endpoint. Endpoints like 'memory://' or 'memory://name' share their
+// state with all other sandboxes of the same name in the current process only. Terraform starts a new provider process
+// for plan and for apply, so their state does not carry over between them. Endpoints like 'file://state.json' or
+// 'file:///path/to/state.json' read their state from the given file, and write it back after each change.
+func Open(endpoint string) (*API, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsed.Scheme {
+	case MemoryScheme:
+		memoryMutex.Lock()
+		defer memoryMutex.Unlock()
+		name := parsed.Host + parsed.Path
+		if api, ok := memorySandboxes[name]; ok {
+			return api, nil
+		}
+		api := New(State{})
+		memorySandboxes[name] = api
+		return api, nil
+	case FileScheme:
+		path := parsed.Host + parsed.Path
+		if path == "" {
+			return nil, fmt.Errorf("missing file name in sandbox endpoint %q", endpoint)
+		}
+		api := New(State{})
+		api.path = path
+		if err = api.load(); err != nil {
+			return nil, err
+		}
+		return api, nil
+	default:
+		return nil, fmt.Errorf("unsupported sandbox endpoint %q, use %s:// or %s://", endpoint, MemoryScheme, FileScheme)
+	}
+}
+
+// New creates a sandbox that starts with the given state and keeps it in memory.
+func New(state State) *API {
+	api := &API{state: state}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /domains/{domain}/mailboxes", api.getMailboxes)
+	mux.HandleFunc("POST /domains/{domain}/mailboxes", api.createMailbox)
+	mux.HandleFunc("GET /domains/{domain}/mailboxes/{localPart}", api.getMailbox)
+	mux.HandleFunc("PUT /domains/{domain}/mailboxes/{localPart}", api.updateMailbox)
+	mux.HandleFunc("DELETE /domains/{domain}/mailboxes/{localPart}", api.deleteMailbox)
+	mux.HandleFunc("GET /domains/{domain}/mailboxes/{localPart}/identities", api.getIdentities)
+	mux.HandleFunc("POST /domains/{domain}/mailboxes/{localPart}/identities", api.createIdentity)
+	mux.HandleFunc("GET /domains/{domain}/mailboxes/{localPart}/identities/{id}", api.getIdentity)
+	mux.HandleFunc("PUT /domains/{domain}/mailboxes/{localPart}/identities/{id}", api.updateIdentity)
+	mux.HandleFunc("DELETE /domains/{domain}/mailboxes/{localPart}/identities/{id}", api.deleteIdentity)
+	mux.HandleFunc("GET /domains/{domain}/aliases", api.getAliases)
+	mux.HandleFunc("POST /domains/{domain}/aliases", api.createAlias)
+	mux.HandleFunc("GET /domains/{domain}/aliases/{localPart}", api.getAlias)
+	mux.HandleFunc("PUT /domains/{domain}/aliases/{localPart}", api.updateAlias)
+	mux.HandleFunc("DELETE /domains/{domain}/aliases/{localPart}", api.deleteAlias)
+	mux.HandleFunc("GET /domains/{domain}/rewrites", api.getRewriteRules)
+	mux.HandleFunc("POST /domains/{domain}/rewrites", api.createRewriteRule)
+	mux.HandleFunc("GET /domains/{domain}/rewrites/{slug}", api.getRewriteRule)
+	mux.HandleFunc("PUT /domains/{domain}/rewrites/{slug}", api.updateRewriteRule)
+	mux.HandleFunc("DELETE /domains/{domain}/rewrites/{slug}", api.deleteRewriteRule)
+	api.handler = mux
+
+	return api
+}
+
+// State returns a copy of the current state of the sandbox.
+func (a *API) State() State {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	content, _ := json.Marshal(a.state)
+	var state State
+	_ = json.Unmarshal(content, &state)
+	return state
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.path != "" {
+		if err := a.load(); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	a.handler.ServeHTTP(w, r)
+}
+
+// RoundTrip answers requests without any network access.
+func (a *API) RoundTrip(request *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	a.ServeHTTP(recorder, request)
+	response := recorder.Result()
+	response.Request = request
+	return response, nil
+}
+
+func (a *API) load() error {
+	content, err := os.ReadFile(a.path)
+	if errors.Is(err, os.ErrNotExist) {
+		a.state = State{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var state State
+	if err = json.Unmarshal(content, &state); err != nil {
+		return fmt.Errorf("invalid sandbox state in %s: %w", a.path, err)
+	}
+	a.state = state
+	return nil
+}
+
+// save persists the state of file sandboxes. It must be called after each change while holding the mutex.
+func (a *API) save() error {
+	if a.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(a.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(a.path), filepath.Base(a.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporary.Name())
+	if _, err = temporary.Write(append(content, '\n')); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err = temporary.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporary.Name(), a.path)
+}
+
+func readJson(w http.ResponseWriter, r *http.Request, value any) bool {
+	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJson(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// writeChange persists the current state and answers with the given value.
+func (a *API) writeChange(w http.ResponseWriter, value any) {
+	if err := a.save(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJson(w, value)
+}
@@ -0,0 +1,328 @@
+/*
+ * SPDX-FileCopyrightText: The terraform-provider-migadu Authors
+ * SPDX-License-Identifier: 0BSD
+ */
+
+package sandbox_test
+
+import (
+	"context"
+	"errors"
+	"github.com/metio/migadu-client.go/client"
+	"github.com/metio/migadu-client.go/model"
+	"github.com/metio/terraform-provider-migadu/internal/sandbox"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newClient(t *testing.T, api *sandbox.API) *client.MigaduClient {
+	endpoint := sandbox.Endpoint
+	username := "username"
+	token := "token"
+	migaduClient, err := client.New(&endpoint, &username, &token, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Could not create client: %s", err)
+	}
+	migaduClient.HTTPClient.Transport = api
+	return migaduClient
+}
+
+func assertStatusCode(t *testing.T, err error, statusCode int) {
+	var requestError *client.RequestError
+	if !errors.As(err, &requestError) {
+		t.Fatalf("Expected request error, got: %v", err)
+	}
+	assert.Equal(t, statusCode, requestError.StatusCode, "status code")
+}
+
+func TestIsEndpoint(t *testing.T) {
+	testCases := map[string]bool{
+		"memory://":                  true,
+		"memory://name":              true,
+		"file://state.json":          true,
+		"file:///tmp/state.json":     true,
+		"https://api.migadu.com/v1/": false,
+		"":                           false,
+	}
+	for endpoint, want := range testCases {
+		t.Run(endpoint, func(t *testing.T) {
+			assert.Equal(t, want, sandbox.IsEndpoint(endpoint))
+		})
+	}
+}
+
+func TestOpen_Memory(t *testing.T) {
+	first, err := sandbox.Open("memory://shared")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	second, err := sandbox.Open("memory://shared")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	other, err := sandbox.Open("memory://other")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = newClient(t, first).CreateAlias(context.Background(), "example.com", &model.Alias{
+		LocalPart:    "some",
+		Destinations: []string{"other@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	assert.Len(t, second.State().Aliases, 1, "shared sandbox")
+	assert.Empty(t, other.State().Aliases, "other sandbox")
+}
+
+func TestOpen_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	api, err := sandbox.Open("file://" + path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	_, err = newClient(t, api).CreateMailbox(context.Background(), "example.com", &model.Mailbox{
+		LocalPart: "some",
+		Name:      "Some Name",
+		Password:  "secret",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Could not read state: %s", err)
+	}
+	assert.Contains(t, string(content), `"local_part": "some"`, "state file")
+	assert.NotContains(t, string(content), "secret", "state file")
+
+	reopened, err := sandbox.Open("file://" + path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	mailbox, err := newClient(t, reopened).GetMailbox(context.Background(), "example.com", "some")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "some@example.com", mailbox.Address, "address")
+}
+
+func TestOpen_Errors(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte("{"), 0o600); err != nil {
+		t.Fatalf("Could not write state: %s", err)
+	}
+
+	testCases := map[string]string{
+		"file://":                "missing file name",
+		"file://" + invalid:      "invalid sandbox state",
+		"https://api.migadu.com": "unsupported sandbox endpoint",
+	}
+	for endpoint, want := range testCases {
+		t.Run(endpoint, func(t *testing.T) {
+			_, err := sandbox.Open(endpoint)
+
+			assert.ErrorContains(t, err, want)
+		})
+	}
+}
+
+func TestAPI_Mailboxes(t *testing.T) {
+	ctx := context.Background()
+	api := sandbox.New(sandbox.State{})
+	migaduClient := newClient(t, api)
+
+	created, err := migaduClient.CreateMailbox(ctx, "hoß.de", &model.Mailbox{
+		LocalPart: "some",
+		Name:      "Some Name",
+		Password:  "secret",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "xn--ho-hia.de", created.DomainName, "domain name")
+	assert.Equal(t, "some@xn--ho-hia.de", created.Address, "address")
+	assert.Empty(t, created.Password, "password")
+
+	_, err = migaduClient.CreateMailbox(ctx, "hoß.de", &model.Mailbox{LocalPart: "some", Name: "Some Name"})
+	assertStatusCode(t, err, http.StatusConflict)
+
+	updated, err := migaduClient.UpdateMailbox(ctx, "hoß.de", "some", &model.Mailbox{Name: "Other Name"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "Other Name", updated.Name, "name")
+	assert.Equal(t, "some", updated.LocalPart, "local part")
+
+	_, err = migaduClient.CreateIdentity(ctx, "hoß.de", "some", &model.Identity{LocalPart: "other", Name: "Other"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	mailboxes, err := migaduClient.GetMailboxes(ctx, "hoß.de")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Len(t, mailboxes.Mailboxes, 1, "mailboxes")
+
+	_, err = migaduClient.DeleteMailbox(ctx, "hoß.de", "some")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Empty(t, api.State().Identities, "identities of deleted mailbox")
+
+	_, err = migaduClient.GetMailbox(ctx, "hoß.de", "some")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestAPI_Identities(t *testing.T) {
+	ctx := context.Background()
+	api := sandbox.New(sandbox.State{
+		Mailboxes: []model.Mailbox{
+			{LocalPart: "some", DomainName: "example.com", Address: "some@example.com", Name: "Some"},
+			{LocalPart: "another", DomainName: "example.com", Address: "another@example.com", Name: "Another"},
+		},
+	})
+	migaduClient := newClient(t, api)
+
+	_, err := migaduClient.CreateIdentity(ctx, "example.com", "missing", &model.Identity{LocalPart: "other", Name: "Other"})
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	created, err := migaduClient.CreateIdentity(ctx, "example.com", "some", &model.Identity{
+		LocalPart:   "other",
+		Name:        "Other",
+		PasswordUse: "custom",
+		Password:    "secret",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "other@example.com", created.Address, "address")
+	assert.Empty(t, created.Password, "password")
+
+	_, err = migaduClient.CreateIdentity(ctx, "example.com", "some", &model.Identity{LocalPart: "other", Name: "Other"})
+	assertStatusCode(t, err, http.StatusConflict)
+
+	identities, err := migaduClient.GetIdentities(ctx, "example.com", "another")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Empty(t, identities.Identities, "identities of other mailbox")
+
+	updated, err := migaduClient.UpdateIdentity(ctx, "example.com", "some", "other", &model.Identity{Name: "Updated"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "Updated", updated.Name, "name")
+
+	identity, err := migaduClient.GetIdentity(ctx, "example.com", "some", "other")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "Updated", identity.Name, "name")
+
+	_, err = migaduClient.DeleteIdentity(ctx, "example.com", "some", "other")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	_, err = migaduClient.GetIdentity(ctx, "example.com", "some", "other")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestAPI_Aliases(t *testing.T) {
+	ctx := context.Background()
+	migaduClient := newClient(t, sandbox.New(sandbox.State{}))
+
+	created, err := migaduClient.CreateAlias(ctx, "example.com", &model.Alias{
+		LocalPart:    "some",
+		Destinations: []string{"other@hoß.de"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "some@example.com", created.Address, "address")
+	assert.Equal(t, []string{"other@xn--ho-hia.de"}, created.Destinations, "destinations")
+
+	_, err = migaduClient.CreateAlias(ctx, "example.com", &model.Alias{LocalPart: "empty"})
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = migaduClient.CreateAlias(ctx, "example.com", &model.Alias{LocalPart: "some", Destinations: []string{"other@example.com"}})
+	assertStatusCode(t, err, http.StatusConflict)
+
+	updated, err := migaduClient.UpdateAlias(ctx, "example.com", "some", &model.Alias{
+		Destinations: []string{"other@example.com", "another@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, []string{"other@example.com", "another@example.com"}, updated.Destinations, "destinations")
+
+	aliases, err := migaduClient.GetAliases(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Len(t, aliases.Aliases, 1, "aliases")
+
+	_, err = migaduClient.DeleteAlias(ctx, "example.com", "some")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	_, err = migaduClient.DeleteAlias(ctx, "example.com", "some")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestAPI_RewriteRules(t *testing.T) {
+	ctx := context.Background()
+	migaduClient := newClient(t, sandbox.New(sandbox.State{}))
+
+	created, err := migaduClient.CreateRewriteRule(ctx, "example.com", &model.RewriteRule{
+		Name:          "sample",
+		LocalPartRule: "prefix-*",
+		OrderNum:      1,
+		Destinations:  []string{"some@example.com", "other@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, []string{"some@example.com", "other@example.com"}, created.Destinations, "destinations")
+
+	_, err = migaduClient.CreateRewriteRule(ctx, "example.com", &model.RewriteRule{
+		Name:          "sample",
+		LocalPartRule: "other-*",
+		Destinations:  []string{"some@example.com"},
+	})
+	assertStatusCode(t, err, http.StatusConflict)
+
+	updated, err := migaduClient.UpdateRewriteRule(ctx, "example.com", "sample", &model.RewriteRule{
+		Name:          "sample",
+		LocalPartRule: "other-*",
+		OrderNum:      2,
+		Destinations:  []string{"some@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Equal(t, "other-*", updated.LocalPartRule, "local part rule")
+	assert.Equal(t, int64(2), updated.OrderNum, "order num")
+
+	rewriteRules, err := migaduClient.GetRewriteRules(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	assert.Len(t, rewriteRules.RewriteRules, 1, "rewrite rules")
+
+	_, err = migaduClient.DeleteRewriteRule(ctx, "example.com", "sample")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	_, err = migaduClient.GetRewriteRule(ctx, "example.com", "sample")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
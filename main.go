@@ -9,10 +9,15 @@ import (
 	"context"
 	"flag"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/metio/terraform-provider-migadu/internal/command"
 	"github.com/metio/terraform-provider-migadu/internal/provider"
 	"log"
+	"os"
 )
 
+// version is set by goreleaser during the release build.
+var version = "dev"
+
 // Run the documentation generation tool
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate --provider-name=terraform-provider-migadu
 
@@ -21,13 +26,17 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
+	if flag.NArg() > 0 {
+		os.Exit(command.Run(context.Background(), flag.Args(), os.Stdout, os.Stderr))
+	}
+
 	opts := providerserver.ServeOpts{
 		Address:         "registry.terraform.io/metio/migadu",
 		Debug:           debug,
 		ProtocolVersion: 6,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New, opts)
+	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 	if err != nil {
 		log.Fatal(err)
 	}